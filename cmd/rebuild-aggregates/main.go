@@ -0,0 +1,26 @@
+// Команда rebuild-aggregates пересчитывает таблицу агрегатов аналитики по расходам.
+// Нужна, если агрегаты разошлись с расходами, например после правки данных вручную.
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+	"wanderwallet/initializers"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+func main() {
+	initializers.LoadEnvVariables()
+	initializers.ConnectToDb()
+	if err := initializers.DB.AutoMigrate(&models.ExpenseAggregate{}); err != nil {
+		log.Fatalf("DB migration failed: %v", err)
+	}
+
+	start := time.Now()
+	if err := repository.NewAggregateRepository(initializers.DB).RebuildAggregates(context.Background()); err != nil {
+		log.Fatalf("Failed to rebuild analytics aggregates: %v", err)
+	}
+	log.Printf("Analytics aggregates rebuilt in %v\n", time.Since(start).Round(time.Millisecond))
+}
@@ -13,9 +13,11 @@ import (
 	"os"
 	"os/signal"
 	"time"
+	_ "time/tzdata" // проверка параметра tz аналитики не должна зависеть от tzdata в системе
 	"wanderwallet/initializers"
 	"wanderwallet/internal/config"
 	"wanderwallet/internal/controllers"
+	"wanderwallet/internal/mail"
 	"wanderwallet/internal/middleware"
 	"wanderwallet/internal/repository"
 	"wanderwallet/internal/routes"
@@ -51,20 +53,53 @@ func main() {
 	travelRepo := repository.NewTravelRepository(initializers.DB)
 	categoryRepo := repository.NewCategoryRepository(initializers.DB)
 	expenseRepo := repository.NewExpenseRepository(initializers.DB)
+	reconciliationRepo := repository.NewReconciliationRepository(initializers.DB)
+	reportRepo := repository.NewExpenseReportRepository(initializers.DB)
+	policyRepo := repository.NewPolicyRepository(initializers.DB)
+	allowanceRepo := repository.NewAllowanceRepository(initializers.DB)
+	insightRepo := repository.NewInsightRepository(initializers.DB)
+	tripReportRepo := repository.NewTripReportRepository(initializers.DB)
+	sessionRepo := repository.NewSessionRepository(initializers.DB)
+
+	var mailer mail.Mailer = mail.NewLogMailer(cfg.MailFrom, cfg.MailDir)
+	if cfg.SMTPAddr != "" {
+		mailer = mail.NewSMTPMailer(cfg.SMTPAddr, cfg.MailFrom, cfg.SMTPUser, cfg.SMTPPassword)
+	}
 
-	userService := services.NewUserService(userRepo)
+	userService := services.NewUserService(userRepo, sessionRepo, mailer, cfg.PasswordResetURL)
 	travelService := services.NewTravelService(travelRepo)
-	categoryService := services.NewCategoryService(categoryRepo, expenseRepo)
-	expenseService := services.NewExpenseService(expenseRepo)
-	analyticsService := services.NewAnalyticsService(expenseRepo)
+	policyService := services.NewPolicyService(policyRepo, expenseRepo, travelRepo, categoryRepo)
+	insightService := services.NewInsightService(insightRepo, expenseRepo, travelRepo)
+	categoryService := services.NewCategoryService(categoryRepo, expenseRepo, policyService, insightService)
+	expenseService := services.NewExpenseService(expenseRepo, policyService, insightService, cfg.DuplicateWindow)
+	var analyticsRepo repository.AnalyticsRepositoryInterface = repository.NewAggregateRepository(initializers.DB)
+	if cfg.LiveAnalytics {
+		log.Println("Analytics is served by live queries to expenses")
+		analyticsRepo = expenseRepo
+	}
+	analyticsService := services.NewAnalyticsService(analyticsRepo, categoryRepo, travelRepo)
+	forecastService := services.NewForecastService(expenseRepo, travelRepo)
+	statisticsService := services.NewStatisticsService(expenseRepo, travelRepo)
+	reviewService := services.NewReviewService(expenseRepo, travelRepo)
+	chartService := services.NewChartService(analyticsService)
+	tripReportService := services.NewTripReportService(tripReportRepo, expenseRepo, analyticsService)
+	reconciliationService := services.NewReconciliationService(reconciliationRepo, expenseRepo, policyService, insightService)
+	reportService := services.NewExpenseReportService(reportRepo, expenseRepo)
+	allowanceService := services.NewAllowanceService(allowanceRepo, categoryRepo, expenseService)
 
 	userController := controllers.NewUserController(userService)
 	travelController := controllers.NewTravelController(travelService)
 	expenseController := controllers.NewExpenseController(expenseService, categoryService, travelService)
 	categoryController := controllers.NewCategoryController(categoryService, expenseService)
-	analyticsController := controllers.NewAnalyticsController(expenseService, analyticsService)
+	analyticsController := controllers.NewAnalyticsController(expenseService, analyticsService, forecastService, statisticsService, reviewService, chartService, travelService)
+	reconciliationController := controllers.NewReconciliationController(reconciliationService, travelService)
+	reportController := controllers.NewExpenseReportController(reportService, travelService)
+	policyController := controllers.NewPolicyController(policyService, travelService, categoryService)
+	allowanceController := controllers.NewAllowanceController(allowanceService, travelService)
+	insightController := controllers.NewInsightController(insightService)
+	tripReportController := controllers.NewTripReportController(tripReportService, travelService)
 
-	routes.SetupRouter(r, userController, travelController, expenseController, categoryController, analyticsController)
+	routes.SetupRouter(r, userController, travelController, expenseController, categoryController, analyticsController, reconciliationController, reportController, policyController, allowanceController, insightController, tripReportController)
 
 	srv := &http.Server{
 		Addr:    cfg.RunAddress,
@@ -89,6 +124,10 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
+	// Отчёты и письма, начатые до остановки, доделываются
+	tripReportService.Wait()
+	userService.Wait()
+	insightService.Wait()
 
 	log.Println("Server exiting gracefully")
 }
@@ -0,0 +1,81 @@
+package initializers
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+const defaultPerDiemRatesFile = "data/per_diem_rates.csv"
+
+// loadPerDiemRates загружает таблицу ставок суточных из CSV-файла
+// (PER_DIEM_RATES_FILE или data/per_diem_rates.csv). Существующие ставки обновляются.
+func loadPerDiemRates() {
+	path := os.Getenv("PER_DIEM_RATES_FILE")
+	if path == "" {
+		path = defaultPerDiemRatesFile
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		log.Printf("не удалось открыть файл ставок суточных: %v", err)
+		return
+	}
+	defer f.Close()
+
+	rates, err := parsePerDiemRates(f)
+	if err != nil {
+		log.Printf("не удалось разобрать файл ставок суточных %s: %v", path, err)
+		return
+	}
+	if err := repository.NewAllowanceRepository(DB).UpsertRates(context.Background(), rates); err != nil {
+		log.Printf("не удалось сохранить ставки суточных: %v", err)
+	}
+}
+
+// parsePerDiemRates читает CSV с заголовком country,city,daily_rate,mileage_rate
+func parsePerDiemRates(r io.Reader) ([]models.PerDiemRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	rates := make([]models.PerDiemRate, 0, len(records)-1)
+	for i, rec := range records[1:] {
+		daily, err := strconv.ParseFloat(rec[2], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid daily_rate: %w", i+2, err)
+		}
+		mileage := 0.0
+		if rec[3] != "" {
+			if mileage, err = strconv.ParseFloat(rec[3], 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid mileage_rate: %w", i+2, err)
+			}
+		}
+		rates = append(rates, models.PerDiemRate{
+			Country:     strings.ToUpper(strings.TrimSpace(rec[0])),
+			City:        strings.TrimSpace(rec[1]),
+			DailyRate:   daily,
+			MileageRate: mileage,
+		})
+	}
+	return rates, nil
+}
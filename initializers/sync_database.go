@@ -1,41 +1,220 @@
 package initializers
 
 import (
+	"context"
 	"log"
+	"os"
+	"strings"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 func SyncDatabase() {
+	hadAggregates := DB.Migrator().HasTable(&models.ExpenseAggregate{})
+	hadTimeFlag := DB.Migrator().HasColumn(&models.Expense{}, "HasTime")
+	prepareUniqueEmails()
 	if err := DB.AutoMigrate(
 		&models.User{},
 		&models.Travel{},
 		&models.Expense{},
 		&models.Category{},
+		&models.CategoryPreference{},
+		&models.BankTransaction{},
+		&models.ReconciliationMatch{},
+		&models.ExpenseReport{},
+		&models.ExpenseReportLine{},
+		&models.ExpenseReportTransition{},
+		&models.SpendingPolicy{},
+		&models.PolicyViolation{},
+		&models.PerDiemRate{},
+		&models.Insight{},
+		&models.TripReportJob{},
+		&models.ExpenseAggregate{},
+		&models.Session{},
+		&models.RefreshToken{},
+		&models.PasswordResetToken{},
 	); err != nil {
 		log.Fatalf("DB migration failed: %v", err)
 	}
 
+	if !hadTimeFlag {
+		markTimedExpenses()
+	}
+	movedExpenses := migrateCategoryNamespaces()
 	seedCategories()
+	flippedAmounts := migrateNegativeAmounts()
+	assignApprovers()
+	loadPerDiemRates()
+	if !hadAggregates || movedExpenses || flippedAmounts {
+		buildAggregates()
+	}
+}
+
+// buildAggregates пересчитывает агрегаты аналитики по сохранённым расходам: после создания
+// таблицы и после миграций, которые меняют расходы в обход репозитория
+func buildAggregates() {
+	if err := repository.NewAggregateRepository(DB).RebuildAggregates(context.Background()); err != nil {
+		log.Printf("не удалось построить агрегаты аналитики: %v", err)
+	}
+}
+
+// migrateCategoryNamespaces переводит категории с глобально уникального имени на
+// уникальность в пределах пользователя. Старое ограничение удаляется, а расходы,
+// ошибочно привязанные к категории другого пользователя, переносятся в одноимённую
+// категорию владельца расхода (она создаётся при необходимости). Возвращает true,
+// если какие-то расходы перенесены.
+func migrateCategoryNamespaces() bool {
+	for _, name := range []string{"uni_categories_name", "categories_name_key"} {
+		if DB.Migrator().HasConstraint(&models.Category{}, name) {
+			if err := DB.Migrator().DropConstraint(&models.Category{}, name); err != nil {
+				log.Printf("не удалось удалить ограничение %s: %v", name, err)
+			}
+		}
+	}
+	if DB.Migrator().HasIndex(&models.Category{}, "idx_categories_name") {
+		if err := DB.Migrator().DropIndex(&models.Category{}, "idx_categories_name"); err != nil {
+			log.Printf("не удалось удалить индекс idx_categories_name: %v", err)
+		}
+	}
+
+	var moved int64
+	err := DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			INSERT INTO categories (name, user_id, builtin, created_at, updated_at)
+			SELECT DISTINCT c.name, e.user_id, false, NOW(), NOW()
+			FROM expenses e
+			JOIN categories c ON c.id = e.category_id
+			WHERE c.user_id IS NOT NULL AND c.user_id <> e.user_id
+			  AND NOT EXISTS (
+				SELECT 1 FROM categories own
+				WHERE own.user_id = e.user_id AND own.name = c.name AND own.deleted_at IS NULL
+			  )`).Error; err != nil {
+			return err
+		}
+		result := tx.Exec(`
+			UPDATE expenses e SET category_id = own.id
+			FROM categories c, categories own
+			WHERE c.id = e.category_id
+			  AND c.user_id IS NOT NULL AND c.user_id <> e.user_id
+			  AND own.user_id = e.user_id AND own.name = c.name AND own.deleted_at IS NULL`)
+		moved = result.RowsAffected
+		return result.Error
+	})
+	if err != nil {
+		log.Printf("не удалось перенести расходы в категории владельцев: %v", err)
+		return false
+	}
+	return moved > 0
+}
+
+// prepareUniqueEmails готовит пользователей к уникальному индексу по почте: адрес, указанный
+// у нескольких пользователей, удаляется у всех — неизвестно, кому из них он принадлежит, и код
+// сброса пароля не должен уйти чужому. Прежний неуникальный индекс удаляется.
+func prepareUniqueEmails() {
+	if !DB.Migrator().HasColumn(&models.User{}, "Email") {
+		return
+	}
+	result := DB.Exec(`
+		UPDATE users SET email = ''
+		WHERE email IN (SELECT email FROM users WHERE email <> '' GROUP BY email HAVING COUNT(*) > 1)`)
+	if result.Error != nil {
+		log.Printf("не удалось удалить повторяющиеся адреса почты: %v", result.Error)
+	} else if result.RowsAffected > 0 {
+		log.Printf("адрес почты удалён у %d пользователей: он был указан у нескольких", result.RowsAffected)
+	}
+	if DB.Migrator().HasIndex(&models.User{}, "idx_users_email") {
+		if err := DB.Migrator().DropIndex(&models.User{}, "idx_users_email"); err != nil {
+			log.Printf("не удалось удалить индекс idx_users_email: %v", err)
+		}
+	}
+}
+
+// assignApprovers выдаёт роль утверждающего логинам из APPROVER_LOGINS (через запятую)
+func assignApprovers() {
+	var logins []string
+	for _, l := range strings.Split(os.Getenv("APPROVER_LOGINS"), ",") {
+		if l = strings.TrimSpace(l); l != "" {
+			logins = append(logins, l)
+		}
+	}
+	if len(logins) == 0 {
+		return
+	}
+	if err := DB.Model(&models.User{}).
+		Where("login IN ?", logins).
+		Update("role", models.RoleApprover).Error; err != nil {
+		log.Printf("не удалось назначить утверждающих: %v", err)
+	}
+}
+
+// markTimedExpenses заполняет has_time у записей, созданных до появления флага: раньше
+// время определялось по самой дате, и запись не в полночь UTC считалась записью со временем
+func markTimedExpenses() {
+	if err := DB.Model(&models.Expense{}).
+		Where("(created_at AT TIME ZONE 'UTC')::time <> '00:00'").
+		Update("has_time", true).Error; err != nil {
+		log.Printf("не удалось отметить записи со временем: %v", err)
+	}
+}
+
+// migrateNegativeAmounts переводит старые записи с отрицательной суммой в возвраты,
+// так как теперь сумма всегда положительна, а направление задаёт вид записи.
+// Возвращает true, если какие-то записи изменены.
+func migrateNegativeAmounts() bool {
+	result := DB.Model(&models.Expense{}).
+		Where("amount < 0").
+		Updates(map[string]any{"kind": models.KindRefund, "amount": gorm.Expr("-amount")})
+	if result.Error != nil {
+		log.Printf("не удалось перенести отрицательные суммы: %v", result.Error)
+		return false
+	}
+	return result.RowsAffected > 0
+}
+
+// builtinAppearance — иконка и цвет встроенных категорий по умолчанию
+var builtinAppearance = map[string][2]string{
+	"transport":     {"bus", "#1e88e5"},
+	"food":          {"utensils", "#fb8c00"},
+	"lodging":       {"bed", "#8e24aa"},
+	"groceries":     {"shopping-basket", "#43a047"},
+	"shopping":      {"shopping-bag", "#d81b60"},
+	"sights":        {"landmark", "#6d4c41"},
+	"entertainment": {"ticket", "#fdd835"},
+	"general":       {"wallet", "#757575"},
+	"fees":          {"receipt", "#546e7a"},
+	"per_diem":      {"calendar-day", "#00897b"},
+	"mileage":       {"car", "#3949ab"},
 }
+
+// seedCategories заводит встроенные категории и проставляет им ключи и оформление.
+// Уже существующие категории находятся по ключу или по русскому названию.
 func seedCategories() {
-	categories := []models.Category{
-		{Name: "Транспорт", Builtin: true},
-		{Name: "Питание", Builtin: true},
-		{Name: "Жильё", Builtin: true},
-		{Name: "Продукты", Builtin: true},
-		{Name: "Шоппинг", Builtin: true},
-		{Name: "Достопримечательности", Builtin: true},
-		{Name: "Развлечения", Builtin: true},
-		{Name: "Общие", Builtin: true},
-		{Name: "Дополнительные сборы и платежи", Builtin: true},
-	}
-
-	for _, c := range categories {
+	for _, b := range i18n.Builtin {
+		name := b.Names[i18n.Default]
+		appearance := builtinAppearance[b.Key]
 		var existing models.Category
-		if err := DB.Where("name = ? AND user_id IS NULL", c.Name).First(&existing).Error; err != nil {
-			if err := DB.Create(&c).Error; err != nil {
-				log.Printf("не удалось создать категорию %s: %v", c.Name, err)
+		err := DB.Where("user_id IS NULL AND (key = ? OR name = ?)", b.Key, name).First(&existing).Error
+		if err == nil {
+			updates := map[string]any{}
+			if existing.Key != b.Key {
+				updates["key"] = b.Key
+			}
+			if existing.Icon == "" && existing.Color == "" {
+				updates["icon"], updates["color"] = appearance[0], appearance[1]
 			}
+			if len(updates) > 0 {
+				if err := DB.Model(&existing).Updates(updates).Error; err != nil {
+					log.Printf("не удалось обновить категорию %s: %v", name, err)
+				}
+			}
+			continue
+		}
+		c := models.Category{Name: name, Key: b.Key, Builtin: true, Icon: appearance[0], Color: appearance[1]}
+		if err := DB.Create(&c).Error; err != nil {
+			log.Printf("не удалось создать категорию %s: %v", name, err)
 		}
 	}
 }
@@ -0,0 +1,123 @@
+// Package charts рисует простые графики аналитики в SVG и PNG без внешних зависимостей.
+// График сначала собирается из примитивов на Canvas, затем выводится в нужном формате.
+package charts
+
+import (
+	"image/color"
+	"math"
+)
+
+type Point struct {
+	X, Y float64
+}
+
+type Anchor string
+
+const (
+	AnchorStart  Anchor = "start"
+	AnchorMiddle Anchor = "middle"
+	AnchorEnd    Anchor = "end"
+)
+
+type shape interface{}
+
+type polygonShape struct {
+	points []Point
+	fill   color.RGBA
+}
+
+type lineShape struct {
+	points []Point
+	width  float64
+	stroke color.RGBA
+	dashed bool
+}
+
+type textShape struct {
+	at     Point
+	text   string
+	size   float64
+	fill   color.RGBA
+	anchor Anchor
+	bold   bool
+}
+
+// Canvas — холст в пикселях с началом координат в левом верхнем углу
+type Canvas struct {
+	Width, Height int
+	Background    color.RGBA
+	shapes        []shape
+}
+
+func NewCanvas(width, height int, background color.RGBA) *Canvas {
+	return &Canvas{Width: width, Height: height, Background: background}
+}
+
+func (c *Canvas) Rect(x, y, w, h float64, fill color.RGBA) {
+	if w < 0 {
+		x, w = x+w, -w
+	}
+	if h < 0 {
+		y, h = y+h, -h
+	}
+	c.Polygon([]Point{{x, y}, {x + w, y}, {x + w, y + h}, {x, y + h}}, fill)
+}
+
+func (c *Canvas) Polygon(points []Point, fill color.RGBA) {
+	c.shapes = append(c.shapes, polygonShape{points: points, fill: fill})
+}
+
+func (c *Canvas) Line(points []Point, width float64, stroke color.RGBA, dashed bool) {
+	c.shapes = append(c.shapes, lineShape{points: points, width: width, stroke: stroke, dashed: dashed})
+}
+
+// Text выводит строку; y — базовая линия
+func (c *Canvas) Text(x, y float64, text string, size float64, fill color.RGBA, anchor Anchor, bold bool) {
+	c.shapes = append(c.shapes, textShape{at: Point{x, y}, text: text, size: size, fill: fill, anchor: anchor, bold: bold})
+}
+
+// Sector — сектор круга от угла from до to (радианы, по часовой стрелке от «12 часов»).
+// При inner > 0 получается сектор кольца.
+func (c *Canvas) Sector(center Point, outer, inner, from, to float64, fill color.RGBA) {
+	steps := int(math.Ceil((to-from)/(math.Pi/90))) + 1
+	arc := func(r float64, reverse bool) []Point {
+		points := make([]Point, 0, steps+1)
+		for i := 0; i <= steps; i++ {
+			k := i
+			if reverse {
+				k = steps - i
+			}
+			a := from + (to-from)*float64(k)/float64(steps)
+			points = append(points, Point{center.X + r*math.Sin(a), center.Y - r*math.Cos(a)})
+		}
+		return points
+	}
+	points := arc(outer, false)
+	if inner > 0 {
+		points = append(points, arc(inner, true)...)
+	} else {
+		points = append(points, center)
+	}
+	c.Polygon(points, fill)
+}
+
+// Drawer получает примитивы холста; позволяет выводить график в другие форматы
+type Drawer interface {
+	Polygon(points []Point, fill color.RGBA)
+	Line(points []Point, width float64, stroke color.RGBA, dashed bool)
+	Text(at Point, text string, size float64, fill color.RGBA, anchor Anchor, bold bool)
+}
+
+// Draw передаёт примитивы холста в порядке рисования; фон не передаётся
+func (c *Canvas) Draw(d Drawer) {
+	for _, s := range c.shapes {
+		switch s := s.(type) {
+		case polygonShape:
+			d.Polygon(s.points, s.fill)
+		case lineShape:
+			d.Line(s.points, s.width, s.stroke, s.dashed)
+		case textShape:
+			d.Text(s.at, s.text, s.size, s.fill, s.anchor, s.bold)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package charts
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+)
+
+// Допустимый размер изображения в пикселях
+const (
+	MinWidth      = 200
+	MaxWidth      = 2000
+	MinHeight     = 150
+	MaxHeight     = 2000
+	DefaultWidth  = 640
+	DefaultHeight = 400
+)
+
+type Theme struct {
+	Background color.RGBA
+	Text       color.RGBA
+	Muted      color.RGBA // подписи осей и сетка
+	Budget     color.RGBA
+	Palette    []color.RGBA
+}
+
+var palette = []color.RGBA{
+	{0x4e, 0x79, 0xa7, 255}, {0xf2, 0x8e, 0x2b, 255}, {0xe1, 0x57, 0x59, 255}, {0x76, 0xb7, 0xb2, 255},
+	{0x59, 0xa1, 0x4f, 255}, {0xed, 0xc9, 0x48, 255}, {0xb0, 0x7a, 0xa1, 255}, {0xff, 0x9d, 0xa7, 255},
+	{0x9c, 0x75, 0x5f, 255}, {0xba, 0xb0, 0xac, 255},
+}
+
+var themes = map[string]Theme{
+	"light": {
+		Background: color.RGBA{0xff, 0xff, 0xff, 255},
+		Text:       color.RGBA{0x22, 0x22, 0x22, 255},
+		Muted:      color.RGBA{0x88, 0x88, 0x88, 255},
+		Budget:     color.RGBA{0xd6, 0x27, 0x28, 255},
+		Palette:    palette,
+	},
+	"dark": {
+		Background: color.RGBA{0x1e, 0x1e, 0x24, 255},
+		Text:       color.RGBA{0xee, 0xee, 0xee, 255},
+		Muted:      color.RGBA{0x8a, 0x8a, 0x96, 255},
+		Budget:     color.RGBA{0xff, 0x6b, 0x6b, 255},
+		Palette:    palette,
+	},
+}
+
+// ThemeByName возвращает тему light или dark; пустое имя — light
+func ThemeByName(name string) (Theme, bool) {
+	if name == "" {
+		name = "light"
+	}
+	t, ok := themes[name]
+	return t, ok
+}
+
+type Options struct {
+	Width  int
+	Height int
+	Theme  Theme
+	Title  string
+	// Подпись при отсутствии данных
+	Empty string
+}
+
+// Value — значение с подписью: сектор круговой диаграммы или точка ряда
+type Value struct {
+	Label string
+	Value float64
+}
+
+const (
+	margin    = 16.0
+	titleSize = 16.0
+	labelSize = 11.0
+)
+
+func newChart(opts Options) *Canvas {
+	c := NewCanvas(opts.Width, opts.Height, opts.Theme.Background)
+	c.Text(margin, margin+titleSize, opts.Title, titleSize, opts.Theme.Text, AnchorStart, true)
+	return c
+}
+
+func drawEmpty(c *Canvas, opts Options) {
+	c.Text(float64(opts.Width)/2, float64(opts.Height)/2, opts.Empty, labelSize+2, opts.Theme.Muted, AnchorMiddle, false)
+}
+
+// Pie рисует круговую диаграмму положительных значений с легендой справа; при donut — кольцо
+func Pie(opts Options, values []Value, donut bool) *Canvas {
+	c := newChart(opts)
+	var total float64
+	for _, v := range values {
+		if v.Value > 0 {
+			total += v.Value
+		}
+	}
+	if total == 0 {
+		drawEmpty(c, opts)
+		return c
+	}
+
+	top := margin*2 + titleSize
+	legendWidth := float64(opts.Width) * 0.4
+	radius := math.Min(float64(opts.Width)-legendWidth-margin*2, float64(opts.Height)-top-margin) / 2
+	center := Point{margin + radius, top + radius}
+	inner := 0.0
+	if donut {
+		inner = radius * 0.55
+	}
+
+	angle := 0.0
+	legendX := float64(opts.Width) - legendWidth
+	row := labelSize * 1.8
+	for i, v := range values {
+		if v.Value <= 0 {
+			continue
+		}
+		fill := opts.Theme.Palette[i%len(opts.Theme.Palette)]
+		sweep := 2 * math.Pi * v.Value / total
+		c.Sector(center, radius, inner, angle, angle+sweep, fill)
+		angle += sweep
+
+		y := top + row*float64(i)
+		if y+row > float64(opts.Height)-margin {
+			continue
+		}
+		c.Rect(legendX, y, labelSize, labelSize, fill)
+		c.Text(legendX+labelSize*1.6, y+labelSize, fmt.Sprintf("%.0f%%", v.Value/total*100), labelSize, opts.Theme.Text, AnchorStart, true)
+		c.Text(legendX+labelSize*5, y+labelSize, v.Label, labelSize, opts.Theme.Text, AnchorStart, false)
+	}
+	if donut {
+		c.Text(center.X, center.Y+labelSize/2, formatAmount(total), labelSize+3, opts.Theme.Text, AnchorMiddle, true)
+	}
+	return c
+}
+
+// Bars рисует столбчатую диаграмму; отрицательные значения идут вниз от нуля
+func Bars(opts Options, values []Value) *Canvas {
+	c := newChart(opts)
+	if len(values) == 0 {
+		drawEmpty(c, opts)
+		return c
+	}
+	lo, hi := 0.0, 0.0
+	for _, v := range values {
+		lo, hi = math.Min(lo, v.Value), math.Max(hi, v.Value)
+	}
+	p := newPlot(c, opts, lo, hi)
+
+	slot := (p.right - p.left) / float64(len(values))
+	gap := math.Min(slot*0.2, 8)
+	for i, v := range values {
+		x := p.left + slot*float64(i) + gap/2
+		c.Rect(x, p.y(0), slot-gap, p.y(v.Value)-p.y(0), opts.Theme.Palette[0])
+	}
+	p.xLabels(values, func(i int) float64 { return p.left + slot*(float64(i)+0.5) })
+	return c
+}
+
+// Line рисует линию значений; budget != nil добавляет горизонтальную пунктирную линию бюджета
+func Line(opts Options, values []Value, budget *float64, budgetLabel string) *Canvas {
+	c := newChart(opts)
+	if len(values) == 0 {
+		drawEmpty(c, opts)
+		return c
+	}
+	lo, hi := 0.0, 0.0
+	for _, v := range values {
+		lo, hi = math.Min(lo, v.Value), math.Max(hi, v.Value)
+	}
+	if budget != nil {
+		lo, hi = math.Min(lo, *budget), math.Max(hi, *budget)
+	}
+	p := newPlot(c, opts, lo, hi)
+
+	// отступ, чтобы крайние подписи оси X не обрезались
+	inset := labelSize * 1.8
+	x := func(i int) float64 {
+		if len(values) == 1 {
+			return (p.left + p.right) / 2
+		}
+		return p.left + inset + (p.right-p.left-2*inset)*float64(i)/float64(len(values)-1)
+	}
+	if budget != nil {
+		y := p.y(*budget)
+		c.Line([]Point{{p.left, y}, {p.right, y}}, 1.5, opts.Theme.Budget, true)
+		c.Text(p.right, y-4, fmt.Sprintf("%s %s", budgetLabel, formatAmount(*budget)), labelSize, opts.Theme.Budget, AnchorEnd, false)
+	}
+
+	points := make([]Point, 0, len(values)+2)
+	points = append(points, Point{x(0), p.y(0)})
+	for i, v := range values {
+		points = append(points, Point{x(i), p.y(v.Value)})
+	}
+	points = append(points, Point{x(len(values) - 1), p.y(0)})
+	area := opts.Theme.Palette[0]
+	area.A = 48
+	c.Polygon(points, area)
+	c.Line(points[1:len(points)-1], 2, opts.Theme.Palette[0], false)
+	p.xLabels(values, x)
+	return c
+}
+
+// plot — область построения с осью значений слева
+type plot struct {
+	canvas                   *Canvas
+	opts                     Options
+	left, right, top, bottom float64
+	lo, hi                   float64
+}
+
+func newPlot(c *Canvas, opts Options, lo, hi float64) *plot {
+	step := niceStep((hi - lo) / 4)
+	lo = math.Floor(lo/step) * step
+	hi = math.Ceil(hi/step) * step
+	if hi == lo {
+		hi = lo + step
+	}
+	p := &plot{
+		canvas: c,
+		opts:   opts,
+		left:   margin + labelSize*5,
+		right:  float64(opts.Width) - margin,
+		top:    margin*2 + titleSize,
+		bottom: float64(opts.Height) - margin - labelSize*1.5,
+		lo:     lo,
+		hi:     hi,
+	}
+	for v := lo; v <= hi+step/2; v += step {
+		y := p.y(v)
+		grid := opts.Theme.Muted
+		grid.A = 64
+		c.Line([]Point{{p.left, y}, {p.right, y}}, 1, grid, false)
+		c.Text(p.left-6, y+labelSize/3, formatAmount(v), labelSize, opts.Theme.Muted, AnchorEnd, false)
+	}
+	return p
+}
+
+func (p *plot) y(v float64) float64 {
+	return p.bottom - (v-p.lo)/(p.hi-p.lo)*(p.bottom-p.top)
+}
+
+// xLabels подписывает точки по оси X, пропуская подписи, которые не помещаются
+func (p *plot) xLabels(values []Value, x func(i int) float64) {
+	width := labelSize * 0.6 * float64(len(values[0].Label)+2)
+	every := int(math.Ceil(width / ((p.right - p.left) / float64(len(values)))))
+	if every < 1 {
+		every = 1
+	}
+	for i := 0; i < len(values); i += every {
+		p.canvas.Text(x(i), p.bottom+labelSize*1.4, values[i].Label, labelSize, p.opts.Theme.Muted, AnchorMiddle, false)
+	}
+}
+
+// niceStep округляет шаг сетки до 1, 2 или 5 × 10^n
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	exp := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5, 10} {
+		if raw <= m*exp {
+			return m * exp
+		}
+	}
+	return 10 * exp
+}
+
+func formatAmount(v float64) string {
+	if v == math.Trunc(v) || math.Abs(v) >= 100 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.2f", v)
+}
@@ -0,0 +1,100 @@
+package charts
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCharts(t *testing.T) {
+	theme, ok := ThemeByName("")
+	assert.True(t, ok)
+	opts := Options{Width: 320, Height: 200, Theme: theme, Title: "Траты & итоги", Empty: "Нет данных"}
+	budget := 300.0
+	series := []Value{{"05-01", 100}, {"05-02", -20}, {"05-03", 250}}
+
+	for name, canvas := range map[string]*Canvas{
+		"pie":   Pie(opts, []Value{{"Питание", 3}, {"Жильё", 1}, {"Пусто", 0}}, false),
+		"donut": Pie(opts, []Value{{"Питание", 1}}, true),
+		"bars":  Bars(opts, series),
+		"line":  Line(opts, series, &budget, "Бюджет"),
+		"empty": Bars(opts, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			svg := string(canvas.SVG())
+			assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="320" height="200"`))
+			assert.Contains(t, svg, "Траты &amp; итоги")
+
+			data, err := canvas.PNG()
+			assert.NoError(t, err)
+			img, err := png.Decode(bytes.NewReader(data))
+			assert.NoError(t, err)
+			assert.Equal(t, 320, img.Bounds().Dx())
+			assert.Equal(t, 200, img.Bounds().Dy())
+		})
+	}
+
+	_, ok = ThemeByName("sepia")
+	assert.False(t, ok)
+}
+
+func TestFillPolygonCoverage(t *testing.T) {
+	theme, _ := ThemeByName("dark")
+	c := NewCanvas(10, 10, theme.Background)
+	// прямоугольник покрывает половину пикселя по краю
+	c.Rect(2, 2, 4.5, 4, theme.Text)
+	data, err := c.PNG()
+	assert.NoError(t, err)
+	img, err := png.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+
+	r, _, _, _ := img.At(3, 3).RGBA()
+	assert.Equal(t, uint32(theme.Text.R)*0x101, r)
+	edge, _, _, _ := img.At(6, 3).RGBA()
+	bg, _, _, _ := img.At(8, 3).RGBA()
+	assert.Greater(t, edge, bg)
+	assert.Less(t, edge, r)
+}
+
+func TestPNGText(t *testing.T) {
+	theme, _ := ThemeByName("light")
+	inked := func(text string, anchor Anchor) (int, int) {
+		c := NewCanvas(120, 30, theme.Background)
+		c.Text(60, 20, text, 14, theme.Text, anchor, false)
+		data, err := c.PNG()
+		assert.NoError(t, err)
+		img, err := png.Decode(bytes.NewReader(data))
+		assert.NoError(t, err)
+		left, right := -1, -1
+		for x := 0; x < 120; x++ {
+			for y := 0; y < 30; y++ {
+				if r, _, _, _ := img.At(x, y).RGBA(); r < uint32(theme.Background.R)*0x101 {
+					if left < 0 {
+						left = x
+					}
+					right = x
+				}
+			}
+		}
+		return left, right
+	}
+
+	// кириллица выводится, а не пропускается
+	left, right := inked("Бюджет", AnchorStart)
+	assert.GreaterOrEqual(t, left, 60)
+	assert.Greater(t, right, left+30)
+
+	left, right = inked("Бюджет", AnchorEnd)
+	assert.LessOrEqual(t, right, 60)
+	assert.Greater(t, right, left+30)
+}
+
+func TestNiceStep(t *testing.T) {
+	assert.Equal(t, 1.0, niceStep(0))
+	assert.Equal(t, 2.0, niceStep(1.3))
+	assert.Equal(t, 50.0, niceStep(42))
+	assert.Equal(t, 1000.0, niceStep(600))
+}
@@ -0,0 +1,272 @@
+package charts
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+	"sort"
+	"wanderwallet/internal/fonts"
+)
+
+const (
+	subsamples = 4 // число подстрок развёртки на пиксель для сглаживания краёв
+	curveSteps = 6 // число отрезков, которыми спрямляется кривая контура глифа
+)
+
+// PNG растеризует холст. Текст выводится встроенным шрифтом Open Sans; символы,
+// которых в нём нет, заменяются на «?».
+func (c *Canvas) PNG() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, c.Width, c.Height))
+	for i := 0; i < len(img.Pix); i += 4 {
+		img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = c.Background.R, c.Background.G, c.Background.B, 255
+	}
+	for _, s := range c.shapes {
+		switch s := s.(type) {
+		case polygonShape:
+			fillPolygon(img, s.points, s.fill)
+		case lineShape:
+			for _, segment := range lineSegments(s) {
+				fillPolygon(img, strokeSegment(segment[0], segment[1], s.width), s.stroke)
+			}
+		case textShape:
+			drawText(img, s)
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// fillPolygon закрашивает многоугольник со сглаживанием
+func fillPolygon(img *image.RGBA, points []Point, fill color.RGBA) {
+	if len(points) < 3 {
+		return
+	}
+	fillContours(img, [][]Point{points}, fill)
+}
+
+// crossing — пересечение подстроки развёртки с ребром; dir — направление ребра
+type crossing struct {
+	x   float64
+	dir int
+}
+
+// fillContours закрашивает область из нескольких замкнутых контуров по правилу ненулевой
+// обмотки, как у глифов TrueType: каждая строка пикселей пересекается несколькими
+// подстроками, а покрытие пикселя по горизонтали считается точно.
+func fillContours(img *image.RGBA, contours [][]Point, fill color.RGBA) {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for _, c := range contours {
+		for _, p := range c {
+			minY = math.Min(minY, p.Y)
+			maxY = math.Max(maxY, p.Y)
+		}
+	}
+	if minY > maxY {
+		return
+	}
+	top := int(math.Max(0, math.Floor(minY)))
+	bottom := int(math.Min(float64(height-1), math.Ceil(maxY)))
+
+	cover := make([]float64, width+1)
+	xs := make([]crossing, 0, 8)
+	for py := top; py <= bottom; py++ {
+		for i := range cover {
+			cover[i] = 0
+		}
+		for s := 0; s < subsamples; s++ {
+			y := float64(py) + (float64(s)+0.5)/subsamples
+			xs = xs[:0]
+			for _, points := range contours {
+				for i := range points {
+					a, b := points[i], points[(i+1)%len(points)]
+					switch {
+					case a.Y <= y && b.Y > y:
+						xs = append(xs, crossing{a.X + (y-a.Y)*(b.X-a.X)/(b.Y-a.Y), 1})
+					case b.Y <= y && a.Y > y:
+						xs = append(xs, crossing{a.X + (y-a.Y)*(b.X-a.X)/(b.Y-a.Y), -1})
+					}
+				}
+			}
+			sort.Slice(xs, func(i, j int) bool { return xs[i].x < xs[j].x })
+			winding := 0
+			for i, c := range xs {
+				if winding != 0 {
+					addSpan(cover, xs[i-1].x, c.x, 1.0/subsamples, width)
+				}
+				winding += c.dir
+			}
+		}
+		for px := 0; px < width; px++ {
+			if cover[px] > 0 {
+				blend(img, px, py, fill, math.Min(cover[px], 1))
+			}
+		}
+	}
+}
+
+func addSpan(cover []float64, x0, x1, weight float64, width int) {
+	x0 = math.Max(0, math.Min(float64(width), x0))
+	x1 = math.Max(0, math.Min(float64(width), x1))
+	if x1 <= x0 {
+		return
+	}
+	i0, i1 := int(x0), int(x1)
+	if i0 == i1 {
+		cover[i0] += (x1 - x0) * weight
+		return
+	}
+	cover[i0] += (float64(i0+1) - x0) * weight
+	for i := i0 + 1; i < i1; i++ {
+		cover[i] += weight
+	}
+	cover[i1] += (x1 - float64(i1)) * weight
+}
+
+func blend(img *image.RGBA, x, y int, c color.RGBA, coverage float64) {
+	a := coverage * float64(c.A) / 255
+	i := img.PixOffset(x, y)
+	img.Pix[i] = uint8(float64(img.Pix[i])*(1-a) + float64(c.R)*a + 0.5)
+	img.Pix[i+1] = uint8(float64(img.Pix[i+1])*(1-a) + float64(c.G)*a + 0.5)
+	img.Pix[i+2] = uint8(float64(img.Pix[i+2])*(1-a) + float64(c.B)*a + 0.5)
+}
+
+// lineSegments разбивает ломаную на отрезки, для пунктира — на штрихи
+func lineSegments(l lineShape) [][2]Point {
+	segments := make([][2]Point, 0, len(l.points))
+	dash, gap := l.width*4, l.width*3
+	offset := 0.0 // пройденная часть текущего периода штрих+пробел
+	for i := 0; i+1 < len(l.points); i++ {
+		a, b := l.points[i], l.points[i+1]
+		if !l.dashed {
+			segments = append(segments, [2]Point{a, b})
+			continue
+		}
+		length := math.Hypot(b.X-a.X, b.Y-a.Y)
+		for pos := 0.0; pos < length; {
+			var step float64
+			if offset < dash {
+				step = math.Min(dash-offset, length-pos)
+				segments = append(segments, [2]Point{lerp(a, b, pos/length), lerp(a, b, (pos+step)/length)})
+			} else {
+				step = math.Min(dash+gap-offset, length-pos)
+			}
+			pos += step
+			offset = math.Mod(offset+step, dash+gap)
+		}
+	}
+	return segments
+}
+
+// strokeSegment — прямоугольник толщиной width вдоль отрезка, с небольшим
+// выступом за концы, чтобы стыки ломаной не расходились
+func strokeSegment(a, b Point, width float64) []Point {
+	length := math.Hypot(b.X-a.X, b.Y-a.Y)
+	if length == 0 {
+		return nil
+	}
+	dx, dy := (b.X-a.X)/length, (b.Y-a.Y)/length
+	h := width / 2
+	a = Point{a.X - dx*h, a.Y - dy*h}
+	b = Point{b.X + dx*h, b.Y + dy*h}
+	nx, ny := -dy*h, dx*h
+	return []Point{{a.X + nx, a.Y + ny}, {b.X + nx, b.Y + ny}, {b.X - nx, b.Y - ny}, {a.X - nx, a.Y - ny}}
+}
+
+func lerp(a, b Point, t float64) Point {
+	return Point{a.X + (b.X-a.X)*t, a.Y + (b.Y-a.Y)*t}
+}
+
+// drawText выводит строку встроенным шрифтом: контуры всех глифов спрямляются
+// и закрашиваются вместе
+func drawText(img *image.RGBA, t textShape) {
+	face := fonts.Face(t.bold)
+	x := t.at.X
+	switch t.anchor {
+	case AnchorMiddle:
+		x -= face.TextWidth(t.text, t.size) / 2
+	case AnchorEnd:
+		x -= face.TextWidth(t.text, t.size)
+	}
+	scale := t.size / float64(face.UnitsPerEm)
+
+	var contours [][]Point
+	for _, r := range t.text {
+		g := face.Index(r)
+		outline, err := face.Outline(g)
+		if err != nil {
+			continue
+		}
+		for _, c := range outline {
+			contours = append(contours, flattenContour(c, Point{x, t.at.Y}, scale))
+		}
+		x += float64(face.Advance(g)) * scale
+	}
+	fillContours(img, contours, t.fill)
+}
+
+// flattenContour переводит контур глифа в координаты холста (origin — начало глифа
+// на базовой линии) и заменяет кривые ломаными
+func flattenContour(c fonts.Contour, origin Point, scale float64) []Point {
+	at := func(p fonts.OutlinePoint) Point {
+		return Point{origin.X + p.X*scale, origin.Y - p.Y*scale}
+	}
+	mid := func(a, b fonts.OutlinePoint) fonts.OutlinePoint {
+		return fonts.OutlinePoint{X: (a.X + b.X) / 2, Y: (a.Y + b.Y) / 2, OnCurve: true}
+	}
+	if len(c) == 0 {
+		return nil
+	}
+
+	// обход начинается с точки на кривой; если таких нет, с подразумеваемой
+	// между последней и первой точками
+	first, rest := mid(c[len(c)-1], c[0]), c
+	for i, p := range c {
+		if p.OnCurve {
+			first, rest = p, append(append(fonts.Contour{}, c[i+1:]...), c[:i]...)
+			break
+		}
+	}
+
+	points := []Point{at(first)}
+	current := first
+	var control *fonts.OutlinePoint
+	for _, p := range append(rest, first) {
+		if !p.OnCurve {
+			if control != nil {
+				next := mid(*control, p)
+				points = append(points, quadratic(at(current), at(*control), at(next))...)
+				current = next
+			}
+			control = &p
+			continue
+		}
+		if control != nil {
+			points = append(points, quadratic(at(current), at(*control), at(p))...)
+			control = nil
+		} else {
+			points = append(points, at(p))
+		}
+		current = p
+	}
+	return points
+}
+
+// quadratic — точки квадратичной кривой Безье после начальной
+func quadratic(a, control, b Point) []Point {
+	points := make([]Point, 0, curveSteps)
+	for i := 1; i <= curveSteps; i++ {
+		t := float64(i) / curveSteps
+		u := 1 - t
+		points = append(points, Point{
+			u*u*a.X + 2*u*t*control.X + t*t*b.X,
+			u*u*a.Y + 2*u*t*control.Y + t*t*b.Y,
+		})
+	}
+	return points
+}
@@ -0,0 +1,60 @@
+package charts
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+// SVG выводит холст как SVG-документ
+func (c *Canvas) SVG() []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="Helvetica, Arial, sans-serif">`,
+		c.Width, c.Height, c.Width, c.Height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, hexColor(c.Background))
+	for _, s := range c.shapes {
+		switch s := s.(type) {
+		case polygonShape:
+			fmt.Fprintf(&b, `<polygon points="%s" fill="%s"%s/>`, svgPoints(s.points), hexColor(s.fill), svgOpacity("fill", s.fill))
+		case lineShape:
+			dash := ""
+			if s.dashed {
+				dash = fmt.Sprintf(` stroke-dasharray="%.1f %.1f"`, s.width*4, s.width*3)
+			}
+			fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="%.1f" stroke-linejoin="round" stroke-linecap="round"%s%s/>`,
+				svgPoints(s.points), hexColor(s.stroke), s.width, svgOpacity("stroke", s.stroke), dash)
+		case textShape:
+			weight := ""
+			if s.bold {
+				weight = ` font-weight="bold"`
+			}
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="%.1f" fill="%s" text-anchor="%s"%s>`,
+				s.at.X, s.at.Y, s.size, hexColor(s.fill), s.anchor, weight)
+			_ = xml.EscapeText(&b, []byte(s.text))
+			b.WriteString(`</text>`)
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.Bytes()
+}
+
+func svgPoints(points []Point) string {
+	parts := make([]string, 0, len(points))
+	for _, p := range points {
+		parts = append(parts, fmt.Sprintf("%.2f,%.2f", p.X, p.Y))
+	}
+	return strings.Join(parts, " ")
+}
+
+func svgOpacity(attr string, c color.RGBA) string {
+	if c.A == 255 {
+		return ""
+	}
+	return fmt.Sprintf(` %s-opacity="%.2f"`, attr, float64(c.A)/255)
+}
+
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
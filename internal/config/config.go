@@ -2,13 +2,28 @@ package config
 
 import (
 	"flag"
+	"log"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 )
 
 type Config struct {
 	RunAddress  string
 	DatabaseURI string
+	// Окно, в котором расходы с одинаковой суммой считаются возможными дублями
+	DuplicateWindow time.Duration
+	// Считать аналитику запросами к расходам, а не по таблице агрегатов
+	LiveAnalytics bool
+	// SMTP-сервер для писем (host:port); пустой — письма пишутся в журнал и MailDir
+	SMTPAddr     string
+	SMTPUser     string
+	SMTPPassword string
+	MailFrom     string
+	MailDir      string
+	// Страница сброса пароля во фронтенде; код из письма добавляется параметром token
+	PasswordResetURL string
 }
 
 var (
@@ -20,6 +35,7 @@ func Init() *Config {
 	once.Do(func() {
 		addrFlag := flag.String("a", "", "address and port")
 		dbFlag := flag.String("d", "", "Postgres URI")
+		liveFlag := flag.Bool("live-analytics", false, "query expenses instead of analytics aggregates")
 		flag.Parse()
 
 		runAddr := "localhost:3000"
@@ -35,7 +51,38 @@ func Init() *Config {
 		} else if env := os.Getenv("DATABASE_URI"); env != "" {
 			dbURI = env
 		}
-		cfg = &Config{RunAddress: runAddr, DatabaseURI: dbURI}
+		duplicateWindow := 24 * time.Hour
+		if env := os.Getenv("DUPLICATE_WINDOW"); env != "" {
+			d, err := time.ParseDuration(env)
+			if err != nil {
+				log.Fatalf("invalid DUPLICATE_WINDOW: %v", err)
+			}
+			duplicateWindow = d
+		}
+		liveAnalytics := *liveFlag
+		if env := os.Getenv("ANALYTICS_LIVE_QUERIES"); env != "" && !liveAnalytics {
+			v, err := strconv.ParseBool(env)
+			if err != nil {
+				log.Fatalf("invalid ANALYTICS_LIVE_QUERIES: %v", err)
+			}
+			liveAnalytics = v
+		}
+		mailFrom := "noreply@wanderwallet.local"
+		if env := os.Getenv("MAIL_FROM"); env != "" {
+			mailFrom = env
+		}
+		cfg = &Config{
+			RunAddress:       runAddr,
+			DatabaseURI:      dbURI,
+			DuplicateWindow:  duplicateWindow,
+			LiveAnalytics:    liveAnalytics,
+			SMTPAddr:         os.Getenv("SMTP_ADDR"),
+			SMTPUser:         os.Getenv("SMTP_USER"),
+			SMTPPassword:     os.Getenv("SMTP_PASSWORD"),
+			MailFrom:         mailFrom,
+			MailDir:          os.Getenv("MAIL_DIR"),
+			PasswordResetURL: os.Getenv("PASSWORD_RESET_URL"),
+		}
 	})
 	return cfg
 }
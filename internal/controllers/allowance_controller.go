@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AllowanceController struct {
+	allowanceService *services.AllowanceService
+	travelService    *services.TravelService
+}
+
+func NewAllowanceController(allowanceService *services.AllowanceService, travelService *services.TravelService) *AllowanceController {
+	return &AllowanceController{
+		allowanceService: allowanceService,
+		travelService:    travelService,
+	}
+}
+
+// GetRates godoc
+// @Summary Ставки суточных и пробега
+// @Description Возвращает таблицу ставок, при необходимости только для одной страны
+// @Tags allowances
+// @Produce json
+// @Param country query string false "Код страны (ISO 3166-1 alpha-2)"
+// @Success 200 {array} dto.PerDiemRateResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/per-diem-rates [get]
+func (ctrl *AllowanceController) GetRates(c *gin.Context) {
+	rates, err := ctrl.allowanceService.GetRates(c.Request.Context(), c.Query("country"))
+	if err != nil {
+		log.Printf("Failed to get per-diem rates: %v\n", err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	resp := make([]dto.PerDiemRateResponse, 0, len(rates))
+	for _, r := range rates {
+		resp = append(resp, dto.PerDiemRateResponse{
+			Country:     r.Country,
+			City:        r.City,
+			DailyRate:   r.DailyRate,
+			MileageRate: r.MileageRate,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// GeneratePerDiem godoc
+// @Summary Начислить суточные
+// @Description Рассчитывает суточные по датам поездки с учётом неполных дней отъезда и возвращения и оплаченного питания. Ранее начисленные суточные поездки заменяются; если они уже входят в отчёт, возвращается 409.
+// @Tags allowances
+// @Accept json
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Param request body dto.GeneratePerDiemRequest true "Место назначения и питание"
+// @Success 200 {array} dto.ExpenseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/per-diem [post]
+func (ctrl *AllowanceController) GeneratePerDiem(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	var req dto.GeneratePerDiemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	meals := make([]models.MealsProvided, 0, len(req.Meals))
+	for _, m := range req.Meals {
+		date, err := time.Parse("2006-01-02", m.Date)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid date")
+			return
+		}
+		meals = append(meals, models.MealsProvided{
+			Date:      date,
+			Breakfast: m.Breakfast,
+			Lunch:     m.Lunch,
+			Dinner:    m.Dinner,
+		})
+	}
+
+	loc := i18n.FromContext(c)
+	entries, err := ctrl.allowanceService.GeneratePerDiem(c.Request.Context(), travel, req.Country, req.City, meals, loc)
+	if err != nil {
+		writeAllowanceError(c, travel.ID, err)
+		return
+	}
+
+	resp := make([]dto.ExpenseResponse, 0, len(entries))
+	for _, e := range entries {
+		resp = append(resp, toExpenseResponse(loc, e))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// CreateMileage godoc
+// @Summary Начислить компенсацию пробега
+// @Description Создаёт запись пробега: расстояние × ставка за километр. Без явной ставки используется ставка страны или города.
+// @Tags allowances
+// @Accept json
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Param request body dto.CreateMileageRequest true "Пробег"
+// @Success 200 {object} dto.ExpenseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/mileage [post]
+func (ctrl *AllowanceController) CreateMileage(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateMileageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid date")
+		return
+	}
+
+	expense := &models.Expense{
+		UserID:      user.ID,
+		TravelID:    travel.ID,
+		CreatedAt:   date,
+		Description: req.Comment,
+		Distance:    req.DistanceKm,
+	}
+	if err := ctrl.allowanceService.CreateMileage(c.Request.Context(), expense, req.Rate, req.Country, req.City); err != nil {
+		writeAllowanceError(c, travel.ID, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toExpenseResponse(i18n.FromContext(c), *expense))
+}
+
+func writeAllowanceError(c *gin.Context, travelID uint, err error) {
+	switch {
+	case errors.Is(err, services.ErrRateNotFound):
+		respondError(c, http.StatusNotFound, "no allowance rate for this location")
+	case errors.Is(err, services.ErrInvalidPeriod), isEntryValidationError(err):
+		respondError(c, http.StatusBadRequest, err.Error())
+	case errors.Is(err, services.ErrExpenseReported):
+		respondError(c, http.StatusConflict, "per-diem entries are included in a report")
+	default:
+		log.Printf("Failed to add allowance for travel %d: %v\n", travelID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+}
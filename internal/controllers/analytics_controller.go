@@ -1,10 +1,14 @@
 package controllers
 
 import (
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/services"
 
@@ -12,26 +16,38 @@ import (
 )
 
 type AnalyticsController struct {
-	expenseService   *services.ExpenseService
-	analyticsService *services.AnalyticsService
+	expenseService    *services.ExpenseService
+	analyticsService  *services.AnalyticsService
+	forecastService   *services.ForecastService
+	statisticsService *services.StatisticsService
+	reviewService     *services.ReviewService
+	chartService      *services.ChartService
+	travelService     *services.TravelService
 }
 
-func NewAnalyticsController(expenseService *services.ExpenseService, analyticsService *services.AnalyticsService) *AnalyticsController {
+func NewAnalyticsController(expenseService *services.ExpenseService, analyticsService *services.AnalyticsService, forecastService *services.ForecastService, statisticsService *services.StatisticsService, reviewService *services.ReviewService, chartService *services.ChartService, travelService *services.TravelService) *AnalyticsController {
 	return &AnalyticsController{
-		expenseService:   expenseService,
-		analyticsService: analyticsService,
+		expenseService:    expenseService,
+		analyticsService:  analyticsService,
+		forecastService:   forecastService,
+		statisticsService: statisticsService,
+		reviewService:     reviewService,
+		chartService:      chartService,
+		travelService:     travelService,
 	}
 }
 
 // GetAnalytics godoc
 // @Summary Получение агрегированной аналитики
-// @Description Возвращает суммы по категориям, динамику по датам и общую сумму расходов за период
+// @Description Возвращает суммы по категориям, упорядоченный временной ряд с нулями в пустых периодах и общую сумму расходов за период
 // @Tags analytics
 // @Accept json
 // @Produce json
 // @Param travel_id query int true "ID путешествия"
 // @Param from query string false "Дата начала, формат YYYY-MM-DD"
 // @Param to query string false "Дата окончания, формат YYYY-MM-DD"
+// @Param granularity query string false "Шаг временного ряда: day (по умолчанию), week или month"
+// @Param tz query string false "Часовой пояс IANA для группировки по датам, по умолчанию UTC"
 // @Success 200 {object} dto.AnalyticsResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -45,7 +61,7 @@ func (ctrl *AnalyticsController) GetAnalytics(c *gin.Context) {
 	travelIDStr := c.Query("travel_id")
 	travelIDUint64, err := strconv.ParseUint(travelIDStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid travel_id"})
+		respondError(c, http.StatusBadRequest, "invalid travel_id")
 		return
 	}
 	travelID := uint(travelIDUint64)
@@ -57,24 +73,303 @@ func (ctrl *AnalyticsController) GetAnalytics(c *gin.Context) {
 	if fromStr != "" {
 		from, err = time.Parse("2006-01-02", fromStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+			respondError(c, http.StatusBadRequest, "invalid from date")
 			return
 		}
 	}
 	if toStr != "" {
 		to, err = time.Parse("2006-01-02", toStr)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+			respondError(c, http.StatusBadRequest, "invalid to date")
 			return
 		}
 	}
 	ctx := c.Request.Context()
-	resp, err := ctrl.analyticsService.Aggregate(ctx, user.ID, travelID, from, to)
+	resp, err := ctrl.analyticsService.Aggregate(ctx, user.ID, travelID, from, to, c.Query("granularity"), c.Query("tz"), i18n.FromContext(c))
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidGranularity):
+			respondError(c, http.StatusBadRequest, "invalid granularity")
+			return
+		case errors.Is(err, services.ErrInvalidTimezone):
+			respondError(c, http.StatusBadRequest, "invalid timezone")
+			return
+		case errors.Is(err, services.ErrTooManyBuckets):
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		log.Printf("Analytics aggregation error: %v\n", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
 	c.JSON(http.StatusOK, resp)
 }
+
+// CompareTravels godoc
+// @Summary Сравнение поездок
+// @Description Возвращает для каждой поездки траты в день, на человека и в день на человека, доли категорий и отличие от средней по поездкам пользователя. Без travel_ids сравниваются все поездки
+// @Tags analytics
+// @Produce json
+// @Param travel_ids query string false "ID поездок через запятую"
+// @Success 200 {object} dto.TravelComparisonResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/analytics/compare [get]
+func (ctrl *AnalyticsController) CompareTravels(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var travelIDs []uint
+	if raw := c.Query("travel_ids"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseUint(strings.TrimSpace(part), 10, 64)
+			if err != nil {
+				respondError(c, http.StatusBadRequest, "invalid travel ID")
+				return
+			}
+			travelIDs = append(travelIDs, uint(id))
+		}
+	}
+
+	resp, err := ctrl.analyticsService.CompareTravels(c.Request.Context(), user.ID, travelIDs, i18n.FromContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrTravelNotFound) {
+			respondError(c, http.StatusNotFound, "travel not found")
+			return
+		}
+		log.Printf("Failed to compare travels for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetForecast godoc
+// @Summary Прогноз трат поездки
+// @Description Прогнозирует итоговые чистые траты по уже потраченному и дневным ставкам категорий, даёт 90% интервал и сумму, которую можно тратить в день, чтобы уложиться в бюджет поездки. Без бюджета вместо сравнения с ним возвращается оценка трат по средним тратам в прошлых поездках (history_estimate)
+// @Tags analytics
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Success 200 {object} dto.ForecastResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/forecast [get]
+func (ctrl *AnalyticsController) GetForecast(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	resp, err := ctrl.forecastService.Forecast(c.Request.Context(), travel, time.Now(), i18n.FromContext(c))
+	if err != nil {
+		log.Printf("Failed to forecast travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetStatistics godoc
+// @Summary Описательная статистика расходов
+// @Description Возвращает самые крупные расходы, среднее, медиану и 90-й перцентиль размера расхода по категориям, распределение трат по дням недели и часам, самый дорогой день и число дней без трат. Без travel_id — по всем поездкам пользователя
+// @Tags analytics
+// @Produce json
+// @Param travel_id query int false "ID путешествия"
+// @Param top query int false "Сколько крупнейших расходов вернуть, 1–100, по умолчанию 10"
+// @Param tz query string false "Часовой пояс IANA для дней недели и часов, по умолчанию UTC"
+// @Success 200 {object} dto.StatisticsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/analytics/statistics [get]
+func (ctrl *AnalyticsController) GetStatistics(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var travelID *uint
+	if s := c.Query("travel_id"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid travel_id")
+			return
+		}
+		v := uint(id)
+		travelID = &v
+	}
+	top := 0
+	if s := c.Query("top"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, services.ErrInvalidTop.Error())
+			return
+		}
+		top = n
+	}
+
+	resp, err := ctrl.statisticsService.Statistics(c.Request.Context(), user.ID, travelID, top, c.Query("tz"), time.Now(), i18n.FromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTop):
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, services.ErrInvalidTimezone):
+			respondError(c, http.StatusBadRequest, "invalid timezone")
+			return
+		case errors.Is(err, services.ErrTravelNotFound):
+			respondError(c, http.StatusNotFound, "travel not found")
+			return
+		}
+		log.Printf("Failed to get statistics for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetReview godoc
+// @Summary Итоги года
+// @Description Подводит итоги трат по всем поездкам за календарный год или произвольный период: сумма, дни в поездках, страны, главные категории, самая дорогая и самая дешёвая поездки, суммы по месяцам
+// @Tags analytics
+// @Produce json
+// @Param year query int false "Календарный год, по умолчанию текущий"
+// @Param from query string false "Начало периода вместо года, формат YYYY-MM-DD"
+// @Param to query string false "Конец периода включительно, формат YYYY-MM-DD"
+// @Success 200 {object} dto.ReviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/analytics/review [get]
+func (ctrl *AnalyticsController) GetReview(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	year := time.Now().Year()
+	if s := c.Query("year"); s != "" {
+		y, err := strconv.Atoi(s)
+		if err != nil || y < 1 || y > 9999 {
+			respondError(c, http.StatusBadRequest, "invalid year")
+			return
+		}
+		year = y
+	}
+	from := time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	var err error
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse("2006-01-02", s); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid from date")
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse("2006-01-02", s); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid to date")
+			return
+		}
+	}
+
+	resp, err := ctrl.reviewService.Review(c.Request.Context(), user.ID, from, to, i18n.FromContext(c))
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidRange) {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		log.Printf("Failed to build review for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// GetChart godoc
+// @Summary График аналитики поездки
+// @Description Рисует по данным аналитики SVG или PNG: categories — круговая диаграмма по категориям, daily — столбцы по дням, cumulative — нарастающий итог с линией бюджета, если он задан
+// @Tags analytics
+// @Produce image/svg+xml
+// @Produce image/png
+// @Param id path int true "ID путешествия"
+// @Param kind path string true "Вид графика: categories, daily или cumulative"
+// @Param format query string false "svg (по умолчанию) или png"
+// @Param width query int false "Ширина, 200–2000, по умолчанию 640"
+// @Param height query int false "Высота, 150–2000, по умолчанию 400"
+// @Param theme query string false "light (по умолчанию) или dark"
+// @Param style query string false "Для categories: donut (по умолчанию) или pie"
+// @Param granularity query string false "Для daily и cumulative: day (по умолчанию), week или month"
+// @Param from query string false "Дата начала, формат YYYY-MM-DD"
+// @Param to query string false "Дата окончания, формат YYYY-MM-DD"
+// @Param tz query string false "Часовой пояс IANA, по умолчанию UTC"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/charts/{kind} [get]
+func (ctrl *AnalyticsController) GetChart(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	var req dto.ChartRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid query params")
+		return
+	}
+	var from, to time.Time
+	var err error
+	if s := c.Query("from"); s != "" {
+		if from, err = time.Parse("2006-01-02", s); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid from date")
+			return
+		}
+	}
+	if s := c.Query("to"); s != "" {
+		if to, err = time.Parse("2006-01-02", s); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid to date")
+			return
+		}
+	}
+
+	image, contentType, err := ctrl.chartService.Render(c.Request.Context(), travel, c.Param("kind"), req, from, to, i18n.FromContext(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidChart):
+			respondError(c, http.StatusNotFound, err.Error())
+			return
+		case errors.Is(err, services.ErrInvalidChartFormat),
+			errors.Is(err, services.ErrInvalidChartSize),
+			errors.Is(err, services.ErrInvalidChartTheme),
+			errors.Is(err, services.ErrTooManyBuckets):
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, services.ErrInvalidGranularity):
+			respondError(c, http.StatusBadRequest, "invalid granularity")
+			return
+		case errors.Is(err, services.ErrInvalidTimezone):
+			respondError(c, http.StatusBadRequest, "invalid timezone")
+			return
+		}
+		log.Printf("Failed to render chart for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.Data(http.StatusOK, contentType, image)
+}
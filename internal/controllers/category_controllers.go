@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"strconv"
 	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/repository"
 	"wanderwallet/internal/services"
@@ -28,10 +29,11 @@ func NewCategoryController(categoryService *services.CategoryService, expenseSer
 
 // GetCategoriesByUserID godoc
 // @Summary Получить категории пользователя
-// @Description Возвращает список категорий расходов для текущего пользователя
+// @Description Возвращает список категорий расходов для текущего пользователя в его порядке. Архивные категории возвращаются только с include_archived=true
 // @Tags categories
 // @Accept json
 // @Produce json
+// @Param include_archived query bool false "Включить архивные категории"
 // @Success 200 {array} dto.CategoryResponse
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -40,20 +42,26 @@ func NewCategoryController(categoryService *services.CategoryService, expenseSer
 func (ctrl *CategoryController) GetCategoriesByUserID(c *gin.Context) {
 	user := c.MustGet("user").(models.User)
 	ctx := c.Request.Context()
-	categories, err := ctrl.categoryService.GetAllCategories(ctx, user.ID)
+	includeArchived := false
+	if v := c.Query("include_archived"); v != "" {
+		parsed, err := strconv.ParseBool(v)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid query params")
+			return
+		}
+		includeArchived = parsed
+	}
+	categories, err := ctrl.categoryService.GetAllCategories(ctx, user.ID, includeArchived)
 	if err != nil {
 		log.Printf("Failed to get categories for user %d: %v\n", user.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
+	loc := i18n.FromContext(c)
 	categoryResponses := make([]dto.CategoryResponse, 0, len(categories))
 	for _, cat := range categories {
-		categoryResponses = append(categoryResponses, dto.CategoryResponse{
-			ID:      fmt.Sprintf("%v", cat.ID),
-			Name:    cat.Name,
-			Builtin: cat.Builtin,
-		})
+		categoryResponses = append(categoryResponses, toCategoryResponse(loc, cat))
 	}
 	c.JSON(http.StatusOK, categoryResponses)
 }
@@ -74,7 +82,7 @@ func (ctrl *CategoryController) GetCategoriesByUserID(c *gin.Context) {
 func (ctrl *CategoryController) CreateCategory(c *gin.Context) {
 	var req dto.CreateCategoryRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		respondError(c, http.StatusBadRequest, "invalid request")
 		return
 	}
 
@@ -82,18 +90,33 @@ func (ctrl *CategoryController) CreateCategory(c *gin.Context) {
 	ctx := c.Request.Context()
 
 	category := &models.Category{
-		UserID:  &user.ID,
-		Name:    req.Name,
-		Builtin: false,
+		UserID:   &user.ID,
+		Name:     req.Name,
+		Builtin:  false,
+		ParentID: req.ParentID,
+		Icon:     req.Icon,
+		Color:    req.Color,
 	}
 
 	if err := ctrl.categoryService.CreateCategory(ctx, category); err != nil {
 		if errors.Is(err, repository.ErrCategoryExists) {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "category already exists"})
+			respondError(c, http.StatusBadRequest, "category already exists")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidParentCategory) {
+			respondError(c, http.StatusBadRequest, "invalid parent category")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCategoryIcon) {
+			respondError(c, http.StatusBadRequest, "invalid category icon")
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCategoryColor) {
+			respondError(c, http.StatusBadRequest, "invalid category color")
 			return
 		}
 		log.Printf("Failed to create category for user %d: %v\n", user.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -102,18 +125,97 @@ func (ctrl *CategoryController) CreateCategory(c *gin.Context) {
 	})
 }
 
+// UpdateCategoryByID godoc
+// @Summary Изменить категорию
+// @Description Переименовывает пользовательскую категорию, меняет иконку и цвет и при необходимости переносит её под другую. Имя должно быть уникальным среди встроенных и собственных категорий пользователя.
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "ID категории"
+// @Param category body dto.UpdateCategoryRequest true "Новое имя"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/categories/{id} [put]
+func (ctrl *CategoryController) UpdateCategoryByID(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	var req dto.UpdateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+	category, err := ctrl.categoryService.GetCategoryByID(ctx, uint(categoryID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "category not found")
+		return
+	}
+
+	if category.Builtin {
+		respondError(c, http.StatusBadRequest, "cannot rename builtin category")
+		return
+	}
+
+	if category.UserID == nil || *category.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "cannot rename another user's category")
+		return
+	}
+
+	if req.Icon != nil {
+		category.Icon = *req.Icon
+	}
+	if req.Color != nil {
+		category.Color = *req.Color
+	}
+	if err := ctrl.categoryService.UpdateCategory(ctx, category, req.Name, req.ParentID); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCategoryIcon):
+			respondError(c, http.StatusBadRequest, "invalid category icon")
+		case errors.Is(err, services.ErrInvalidCategoryColor):
+			respondError(c, http.StatusBadRequest, "invalid category color")
+		case errors.Is(err, services.ErrInvalidCategoryName):
+			respondError(c, http.StatusBadRequest, "invalid category name")
+		case errors.Is(err, services.ErrInvalidParentCategory):
+			respondError(c, http.StatusBadRequest, "invalid parent category")
+		case errors.Is(err, repository.ErrCategoryExists):
+			respondError(c, http.StatusConflict, "category already exists")
+		default:
+			log.Printf("Failed to rename category %d: %v\n", categoryID, err)
+			respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, toCategoryResponse(i18n.FromContext(c), *category))
+}
+
 // DeleteCategoryByID godoc
 // @Summary Удалить категорию
-// @Description Удаляет пользовательскую категорию по ID, если она не системная и не используется
+// @Description Удаляет пользовательскую категорию по ID, если она не системная и не используется. Если у категории есть подкатегории, параметр children задаёт, что с ними сделать.
 // @Tags categories
 // @Accept json
 // @Produce json
 // @Param id path int true "ID категории"
+// @Param children query string false "lift — перенести подкатегории к родителю, delete — удалить вместе с подкатегориями"
+// @Param reassign_to query int false "ID категории, в которую перенести расходы, правила трат и подкатегории"
 // @Success 200 {object} map[string]string
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /api/categories/{id} [delete]
@@ -121,7 +223,7 @@ func (ctrl *CategoryController) DeleteCategoryByID(c *gin.Context) {
 	idStr := c.Param("id")
 	categoryID, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid category ID"})
+		respondError(c, http.StatusBadRequest, "invalid category ID")
 		return
 	}
 
@@ -129,34 +231,56 @@ func (ctrl *CategoryController) DeleteCategoryByID(c *gin.Context) {
 	ctx := c.Request.Context()
 	category, err := ctrl.categoryService.GetCategoryByID(ctx, uint(categoryID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+		respondError(c, http.StatusNotFound, "category not found")
 		return
 	}
 
 	if category.Builtin {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "cannot delete builtin category"})
+		respondError(c, http.StatusBadRequest, "cannot delete builtin category")
 		return
 	}
 
 	if category.UserID == nil || *category.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "cannot delete another user's category"})
+		respondError(c, http.StatusForbidden, "cannot delete another user's category")
+		return
+	}
+
+	if reassignTo := c.Query("reassign_to"); reassignTo != "" {
+		targetID, err := strconv.ParseUint(reassignTo, 10, 64)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid reassign_to")
+			return
+		}
+		if target, ok := ctrl.mergeCategory(c, category, uint(targetID)); ok {
+			c.JSON(http.StatusOK, gin.H{
+				"message": fmt.Sprintf("Category %s deleted, expenses moved to %s", category.Name, target.Name),
+			})
+		}
 		return
 	}
 
 	inUse, err := ctrl.expenseService.ExistsByCategoryID(ctx, uint(categoryID))
 	if err != nil {
 		log.Printf("Failed to check category usage for category %d: %v\n", categoryID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 	if inUse {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "category is used in expenses"})
+		respondError(c, http.StatusBadRequest, "category is used in expenses")
 		return
 	}
 
-	if err := ctrl.categoryService.DeleteCategory(ctx, uint(categoryID)); err != nil {
+	if err := ctrl.categoryService.DeleteCategory(ctx, uint(categoryID), c.Query("children")); err != nil {
+		if errors.Is(err, services.ErrCategoryHasChildren) {
+			respondError(c, http.StatusConflict, "category has subcategories: pass children=lift or children=delete")
+			return
+		}
+		if errors.Is(err, services.ErrCategoryHasLinkedExpenses) {
+			respondError(c, http.StatusBadRequest, "subcategory is used in expenses")
+			return
+		}
 		log.Printf("Failed to delete category %d: %v\n", categoryID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -164,3 +288,188 @@ func (ctrl *CategoryController) DeleteCategoryByID(c *gin.Context) {
 		"message": fmt.Sprintf("Category %s deleted successfully", category.Name),
 	})
 }
+
+// MergeCategory godoc
+// @Summary Объединить категории
+// @Description Переносит расходы, правила трат и подкатегории в другую категорию одной транзакцией и удаляет исходную категорию
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param id path int true "ID исходной категории"
+// @Param request body dto.MergeCategoryRequest true "Категория, в которую выполняется перенос"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/categories/{id}/merge [post]
+func (ctrl *CategoryController) MergeCategory(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	var req dto.MergeCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	category, err := ctrl.categoryService.GetCategoryByID(c.Request.Context(), uint(categoryID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "category not found")
+		return
+	}
+
+	if category.Builtin {
+		respondError(c, http.StatusBadRequest, "cannot merge builtin category")
+		return
+	}
+
+	if category.UserID == nil || *category.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "cannot merge another user's category")
+		return
+	}
+
+	if target, ok := ctrl.mergeCategory(c, category, req.TargetID); ok {
+		c.JSON(http.StatusOK, toCategoryResponse(i18n.FromContext(c), *target))
+	}
+}
+
+// ReorderCategories godoc
+// @Summary Изменить порядок категорий
+// @Description Перечисленные категории встают в начало списка в указанном порядке, остальные идут следом. Порядок встроенных категорий хранится для каждого пользователя отдельно
+// @Tags categories
+// @Accept json
+// @Produce json
+// @Param request body dto.ReorderCategoriesRequest true "ID категорий в новом порядке"
+// @Success 200 {array} dto.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/categories/order [put]
+func (ctrl *CategoryController) ReorderCategories(c *gin.Context) {
+	var req dto.ReorderCategoriesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+
+	categories, err := ctrl.categoryService.ReorderCategories(c.Request.Context(), user.ID, req.CategoryIDs)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCategoryOrder) {
+			respondError(c, http.StatusBadRequest, "invalid category order")
+			return
+		}
+		log.Printf("Failed to reorder categories for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	loc := i18n.FromContext(c)
+	resp := make([]dto.CategoryResponse, 0, len(categories))
+	for _, cat := range categories {
+		resp = append(resp, toCategoryResponse(loc, cat))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ArchiveCategory godoc
+// @Summary Архивировать категорию
+// @Description Скрывает категорию из выбора. Расходы в ней остаются в истории и аналитике. Встроенная категория архивируется только для текущего пользователя
+// @Tags categories
+// @Produce json
+// @Param id path int true "ID категории"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/categories/{id}/archive [post]
+func (ctrl *CategoryController) ArchiveCategory(c *gin.Context) {
+	ctrl.setArchived(c, true)
+}
+
+// UnarchiveCategory godoc
+// @Summary Вернуть категорию из архива
+// @Tags categories
+// @Produce json
+// @Param id path int true "ID категории"
+// @Success 200 {object} dto.CategoryResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/categories/{id}/unarchive [post]
+func (ctrl *CategoryController) UnarchiveCategory(c *gin.Context) {
+	ctrl.setArchived(c, false)
+}
+
+func (ctrl *CategoryController) setArchived(c *gin.Context, archived bool) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+
+	category, err := ctrl.categoryService.GetCategoryByID(ctx, uint(categoryID))
+	if err != nil || (category.UserID != nil && *category.UserID != user.ID) {
+		respondError(c, http.StatusNotFound, "category not found")
+		return
+	}
+
+	if err := ctrl.categoryService.SetArchived(ctx, user.ID, category, archived); err != nil {
+		log.Printf("Failed to archive category %d: %v\n", category.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, toCategoryResponse(i18n.FromContext(c), *category))
+}
+
+// mergeCategory выполняет слияние; при ошибке ответ уже отправлен и возвращается false
+func (ctrl *CategoryController) mergeCategory(c *gin.Context, category *models.Category, targetID uint) (*models.Category, bool) {
+	target, err := ctrl.categoryService.MergeCategory(c.Request.Context(), category, targetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidMergeTarget):
+			respondError(c, http.StatusBadRequest, "invalid target category")
+			return nil, false
+		case errors.Is(err, services.ErrExpenseLocked):
+			respondError(c, http.StatusConflict, err.Error())
+			return nil, false
+		}
+		log.Printf("Failed to merge category %d into %d: %v\n", category.ID, targetID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return nil, false
+	}
+	return target, true
+}
+
+func toCategoryResponse(loc i18n.Locale, cat models.Category) dto.CategoryResponse {
+	resp := dto.CategoryResponse{
+		ID:        fmt.Sprintf("%v", cat.ID),
+		Name:      i18n.CategoryName(loc, cat.Key, cat.Name),
+		Key:       cat.Key,
+		Builtin:   cat.Builtin,
+		Icon:      cat.Icon,
+		Color:     cat.Color,
+		SortOrder: cat.SortOrder,
+		Archived:  cat.Archived,
+	}
+	if cat.ParentID != nil {
+		resp.ParentID = fmt.Sprintf("%v", *cat.ParentID)
+	}
+	return resp
+}
@@ -0,0 +1,12 @@
+package controllers
+
+import (
+	"wanderwallet/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError отвечает {"error": message}, переведя сообщение на язык клиента
+func respondError(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{"error": i18n.T(i18n.FromContext(c), message)})
+}
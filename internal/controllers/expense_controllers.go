@@ -1,17 +1,20 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"strconv"
 	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/services"
 
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type ExpenseController struct {
@@ -30,7 +33,7 @@ func NewExpenseController(expenseService *services.ExpenseService, categoryServi
 
 // CreateExpense godoc
 // @Summary Создать расход
-// @Description Добавляет новый расход для авторизованного пользователя
+// @Description Добавляет новый расход для авторизованного пользователя. Категория задаётся category_id или, если он не указан, названием
 // @Tags expenses
 // @Accept json
 // @Produce json
@@ -40,33 +43,37 @@ func NewExpenseController(expenseService *services.ExpenseService, categoryServi
 // @Failure 401 {object} map[string]string
 // @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]any "Найдены возможные дубли; повторите запрос с force=true"
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /api/expenses [post]
 func (ctrl *ExpenseController) CreateExpense(c *gin.Context) {
 	var req dto.CreateExpenseRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+	if err := c.ShouldBindJSON(&req); err != nil || (req.CategoryID == nil && req.Category == "") {
+		respondError(c, http.StatusBadRequest, "invalid request")
 		return
 	}
 	user := c.MustGet("user").(models.User)
 	ctx := c.Request.Context()
 
-	category, err := ctrl.categoryService.GetCategoryByName(ctx, req.Category)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "category not found or unavailable"})
+	category, ok := ctrl.resolveCategory(c, user, req.CategoryID, req.Category)
+	if !ok {
 		return
 	}
 
 	travel, err := ctrl.travelService.GetTravelByID(ctx, req.TravelID)
 	if err != nil || travel.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "invalid travel"})
+		respondError(c, http.StatusForbidden, "invalid travel")
 		return
 	}
 
-	date, err := time.Parse("2006-01-02", req.Date)
+	date, hasTime, err := parseExpenseDate(req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date"})
+		respondError(c, http.StatusBadRequest, "invalid date")
+		return
+	}
+	if !isClientKind(models.ExpenseKind(req.Kind)) {
+		respondError(c, http.StatusBadRequest, "per-diem and mileage entries are created by the allowance endpoints")
 		return
 	}
 
@@ -76,12 +83,31 @@ func (ctrl *ExpenseController) CreateExpense(c *gin.Context) {
 		TravelID:    req.TravelID,
 		Amount:      req.Amount,
 		CreatedAt:   date,
+		HasTime:     hasTime,
 		Description: req.Comment,
+		Kind:        models.ExpenseKind(req.Kind),
+		RefundOfID:  req.RefundOfID,
 	}
 
-	if err := ctrl.expenseService.CreateExpense(ctx, expense); err != nil {
+	duplicateIDs, err := ctrl.expenseService.CreateExpense(ctx, expense, req.Force)
+	if err != nil {
+		if isEntryValidationError(err) {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrPossibleDuplicate) {
+			ids := make([]string, 0, len(duplicateIDs))
+			for _, id := range duplicateIDs {
+				ids = append(ids, fmt.Sprintf("%v", id))
+			}
+			c.JSON(http.StatusConflict, gin.H{
+				"error":         i18n.T(i18n.FromContext(c), "possible duplicate expense"),
+				"duplicate_ids": ids,
+			})
+			return
+		}
 		log.Printf("Failed to create expense for user %d: %v\n", user.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -96,7 +122,8 @@ func (ctrl *ExpenseController) CreateExpense(c *gin.Context) {
 // @Tags expenses
 // @Accept json
 // @Produce json
-// @Param category query string false "Категория"
+// @Param category_id query int false "ID категории"
+// @Param category query string false "Название категории, если category_id не задан"
 // @Param from query string false "Дата начала, формат YYYY-MM-DD"
 // @Param to query string false "Дата окончания, формат YYYY-MM-DD"
 // @Success 200 {array} dto.ExpenseResponse
@@ -109,7 +136,7 @@ func (ctrl *ExpenseController) CreateExpense(c *gin.Context) {
 func (ctrl *ExpenseController) GetExpensesByUserID(c *gin.Context) {
 	var req dto.GetUsersExpenseRequest
 	if err := c.ShouldBindQuery(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid query params"})
+		respondError(c, http.StatusBadRequest, "invalid query params")
 		return
 	}
 	user := c.MustGet("user").(models.User)
@@ -119,7 +146,7 @@ func (ctrl *ExpenseController) GetExpensesByUserID(c *gin.Context) {
 	if req.From != "" {
 		t, err := time.Parse("2006-01-02", req.From)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from date"})
+			respondError(c, http.StatusBadRequest, "invalid from date")
 			return
 		}
 		fromDate = &t
@@ -127,17 +154,16 @@ func (ctrl *ExpenseController) GetExpensesByUserID(c *gin.Context) {
 	if req.To != "" {
 		t, err := time.Parse("2006-01-02", req.To)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid to date"})
+			respondError(c, http.StatusBadRequest, "invalid to date")
 			return
 		}
 		toDate = &t
 	}
 
 	var categoryID *uint
-	if req.Category != "" {
-		cat, err := ctrl.categoryService.GetCategoryByName(ctx, req.Category)
-		if err != nil {
-			c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
+	if req.CategoryID != nil || req.Category != "" {
+		cat, ok := ctrl.resolveCategory(c, user, req.CategoryID, req.Category)
+		if !ok {
 			return
 		}
 		categoryID = &cat.ID
@@ -146,20 +172,16 @@ func (ctrl *ExpenseController) GetExpensesByUserID(c *gin.Context) {
 	expenses, err := ctrl.expenseService.GetExpensesByUserTimeAndCategory(ctx, user.ID, fromDate, toDate, categoryID)
 	if err != nil {
 		log.Printf("Failed to get expenses for user %d: %v\n", user.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
+	loc := i18n.FromContext(c)
 	expenseResponses := make([]dto.ExpenseResponse, 0, len(expenses))
 	for _, e := range expenses {
 		category, _ := ctrl.categoryService.GetCategoryByID(ctx, e.CategoryID)
-		expenseResponses = append(expenseResponses, dto.ExpenseResponse{
-			ID:       fmt.Sprintf("%v", e.ID),
-			Category: category.Name,
-			Amount:   e.Amount,
-			Date:     e.CreatedAt.Format("2006-01-02"),
-			Comment:  e.Description,
-		})
+		e.Category = *category
+		expenseResponses = append(expenseResponses, toExpenseResponse(loc, e))
 	}
 	c.JSON(http.StatusOK, expenseResponses)
 }
@@ -179,18 +201,23 @@ func (ctrl *ExpenseController) GetExpensesByUserID(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
+// @Failure 409 {object} map[string]string "Расход входит в неотклонённый отчёт"
 // @Router /api/expenses/{id} [put]
 func (ctrl *ExpenseController) UpdateExpenseByUserID(c *gin.Context) {
 	idStr := c.Param("id")
 	expenseID, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		respondError(c, http.StatusBadRequest, "invalid expense ID")
 		return
 	}
 
 	var req dto.UpdateExpenseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request"})
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+	if !isClientKind(models.ExpenseKind(req.Kind)) {
+		respondError(c, http.StatusBadRequest, "per-diem and mileage entries are created by the allowance endpoints")
 		return
 	}
 
@@ -198,35 +225,53 @@ func (ctrl *ExpenseController) UpdateExpenseByUserID(c *gin.Context) {
 	ctx := c.Request.Context()
 	expense, err := ctrl.expenseService.GetExpenseByID(ctx, uint(expenseID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "expense not found"})
+		respondError(c, http.StatusNotFound, "expense not found")
 		return
 	}
 
 	if expense.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "cannot edit another user's expense"})
+		respondError(c, http.StatusForbidden, "cannot edit another user's expense")
 		return
 	}
 
-	category, err := ctrl.categoryService.GetCategoryByName(ctx, req.Category)
-	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "category not found"})
-		return
+	if req.CategoryID != nil || req.Category != "" {
+		category, ok := ctrl.resolveCategory(c, user, req.CategoryID, req.Category)
+		if !ok {
+			return
+		}
+		expense.CategoryID = category.ID
 	}
 
-	expenseDate, err := time.Parse("2006-01-02", req.Date)
+	expenseDate, hasTime, err := parseExpenseDate(req.Date)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid date"})
+		respondError(c, http.StatusBadRequest, "invalid date")
 		return
 	}
 
-	expense.CategoryID = category.ID
 	expense.Amount = req.Amount
 	expense.CreatedAt = expenseDate
+	expense.HasTime = hasTime
 	expense.Description = req.Comment
+	if req.Kind != "" {
+		expense.Kind = models.ExpenseKind(req.Kind)
+	}
+	expense.RefundOfID = req.RefundOfID
 
 	if err := ctrl.expenseService.UpdateExpense(ctx, expense); err != nil {
+		if isEntryValidationError(err) {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrExpenseLocked) {
+			respondError(c, http.StatusConflict, "expense is locked by an approved report")
+			return
+		}
+		if errors.Is(err, services.ErrExpenseReported) {
+			respondError(c, http.StatusConflict, "expense is included in a report")
+			return
+		}
 		log.Printf("Failed to update expense %d: %v\n", expense.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -249,12 +294,13 @@ func (ctrl *ExpenseController) UpdateExpenseByUserID(c *gin.Context) {
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
+// @Failure 409 {object} map[string]string "Расход входит в неотклонённый отчёт"
 // @Router /api/expenses/{id} [delete]
 func (ctrl *ExpenseController) DeleteExpenseByID(c *gin.Context) {
 	idStr := c.Param("id")
 	expenseID, err := strconv.ParseUint(idStr, 10, 64)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expense ID"})
+		respondError(c, http.StatusBadRequest, "invalid expense ID")
 		return
 	}
 
@@ -263,18 +309,26 @@ func (ctrl *ExpenseController) DeleteExpenseByID(c *gin.Context) {
 
 	expense, err := ctrl.expenseService.GetExpenseByID(ctx, uint(expenseID))
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "expense not found"})
+		respondError(c, http.StatusNotFound, "expense not found")
 		return
 	}
 
 	if expense.UserID != user.ID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "cannot delete another user's expense"})
+		respondError(c, http.StatusForbidden, "cannot delete another user's expense")
 		return
 	}
 
 	if err := ctrl.expenseService.DeleteExpense(ctx, uint(expenseID)); err != nil {
+		if errors.Is(err, services.ErrExpenseLocked) {
+			respondError(c, http.StatusConflict, "expense is locked by an approved report")
+			return
+		}
+		if errors.Is(err, services.ErrExpenseReported) {
+			respondError(c, http.StatusConflict, "expense is included in a report")
+			return
+		}
 		log.Printf("Failed to delete expense %d: %v\n", expense.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -282,3 +336,106 @@ func (ctrl *ExpenseController) DeleteExpenseByID(c *gin.Context) {
 		"message": "Expense deleted successfully",
 	})
 }
+
+// ScanDuplicates godoc
+// @Summary Поиск дублей в поездке
+// @Description Группирует расходы поездки с одинаковой суммой, близкой датой и похожим комментарием
+// @Tags expenses
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Success 200 {array} dto.DuplicateGroupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/duplicates [get]
+func (ctrl *ExpenseController) ScanDuplicates(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	groups, err := ctrl.expenseService.ScanDuplicates(c.Request.Context(), user.ID, travel.ID)
+	if err != nil {
+		log.Printf("Failed to scan duplicates for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	loc := i18n.FromContext(c)
+	resp := make([]dto.DuplicateGroupResponse, 0, len(groups))
+	for _, group := range groups {
+		expenses := make([]dto.ExpenseResponse, 0, len(group))
+		for _, e := range group {
+			expenses = append(expenses, toExpenseResponse(loc, e))
+		}
+		resp = append(resp, dto.DuplicateGroupResponse{Expenses: expenses})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// resolveCategory находит категорию по ID или названию среди доступных пользователю;
+// при ошибке ответ уже отправлен и возвращается false
+func (ctrl *ExpenseController) resolveCategory(c *gin.Context, user models.User, categoryID *uint, name string) (*models.Category, bool) {
+	category, err := ctrl.categoryService.ResolveCategory(c.Request.Context(), user.ID, categoryID, name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			respondError(c, http.StatusNotFound, "category not found or unavailable")
+			return nil, false
+		}
+		log.Printf("Failed to resolve category for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return nil, false
+	}
+	return category, true
+}
+
+// toExpenseResponse ожидает, что категория расхода уже загружена (Preload("Category"))
+func toExpenseResponse(loc i18n.Locale, e models.Expense) dto.ExpenseResponse {
+	resp := dto.ExpenseResponse{
+		ID:         fmt.Sprintf("%v", e.ID),
+		CategoryID: fmt.Sprintf("%v", e.CategoryID),
+		Category:   i18n.CategoryName(loc, e.Category.Key, e.Category.Name),
+		Amount:     e.Amount,
+		Date:       e.CreatedAt.Format("2006-01-02"),
+		Comment:    e.Description,
+		Kind:       string(e.Kind),
+		Distance:   e.Distance,
+	}
+	if e.HasTime {
+		resp.DateTime = e.CreatedAt.Format(time.RFC3339)
+	}
+	if e.RefundOfID != nil {
+		resp.RefundOfID = fmt.Sprintf("%v", *e.RefundOfID)
+	}
+	for _, v := range e.Violations {
+		resp.Violations = append(resp.Violations, toPolicyViolationResponse(v))
+	}
+	return resp
+}
+
+// parseExpenseDate принимает дату (YYYY-MM-DD) или дату со временем в формате RFC 3339;
+// hasTime сообщает, было ли указано время
+func parseExpenseDate(s string) (date time.Time, hasTime bool, err error) {
+	if date, err := time.Parse("2006-01-02", s); err == nil {
+		return date, false, nil
+	}
+	date, err = time.Parse(time.RFC3339, s)
+	return date, err == nil, err
+}
+
+// isClientKind — вид записи, который клиент задаёт сам (пустой — по умолчанию). Суточные
+// и пробег начисляются только по ставкам через AllowanceController.
+func isClientKind(kind models.ExpenseKind) bool {
+	return !kind.IsAllowance()
+}
+
+func isEntryValidationError(err error) bool {
+	return errors.Is(err, services.ErrInvalidAmount) ||
+		errors.Is(err, services.ErrInvalidKind) ||
+		errors.Is(err, services.ErrInvalidRefundLink) ||
+		errors.Is(err, services.ErrInvalidDistance)
+}
@@ -0,0 +1,382 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExpenseReportController struct {
+	reportService *services.ExpenseReportService
+	travelService *services.TravelService
+}
+
+func NewExpenseReportController(reportService *services.ExpenseReportService, travelService *services.TravelService) *ExpenseReportController {
+	return &ExpenseReportController{
+		reportService: reportService,
+		travelService: travelService,
+	}
+}
+
+// CreateReport godoc
+// @Summary Создать отчёт по командировке
+// @Description Создаёт черновик отчёта из возмещаемых расходов поездки
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param report body dto.CreateExpenseReportRequest true "Данные отчёта"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports [post]
+func (ctrl *ExpenseReportController) CreateReport(c *gin.Context) {
+	var req dto.CreateExpenseReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+
+	travel, err := ctrl.travelService.GetTravelByID(ctx, req.TravelID)
+	if err != nil || travel.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "invalid travel")
+		return
+	}
+
+	report, err := ctrl.reportService.CreateReport(ctx, user.ID, travel.ID, req.Title, req.ExpenseIDs)
+	if err != nil {
+		writeReportError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, toExpenseReportResponse(i18n.FromContext(c), *report))
+}
+
+// GetReports godoc
+// @Summary Отчёты пользователя
+// @Description Возвращает отчёты по командировкам текущего пользователя
+// @Tags reports
+// @Produce json
+// @Success 200 {array} dto.ExpenseReportResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports [get]
+func (ctrl *ExpenseReportController) GetReports(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	reports, err := ctrl.reportService.GetReportsByUserID(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Printf("Failed to get reports for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.JSON(http.StatusOK, toExpenseReportResponses(i18n.FromContext(c), reports))
+}
+
+// GetPendingReports godoc
+// @Summary Отчёты на утверждение
+// @Description Возвращает отправленные отчёты, ожидающие решения; доступно только утверждающим
+// @Tags reports
+// @Produce json
+// @Success 200 {array} dto.ExpenseReportResponse
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/pending [get]
+func (ctrl *ExpenseReportController) GetPendingReports(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	reports, err := ctrl.reportService.GetPendingReports(c.Request.Context(), user)
+	if err != nil {
+		writeReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toExpenseReportResponses(i18n.FromContext(c), reports))
+}
+
+// GetReport godoc
+// @Summary Отчёт по командировке
+// @Description Возвращает отчёт со строками и историей статусов
+// @Tags reports
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id} [get]
+func (ctrl *ExpenseReportController) GetReport(c *gin.Context) {
+	report, ok := ctrl.reportFromParam(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, toExpenseReportResponse(i18n.FromContext(c), *report))
+}
+
+// SubmitReport godoc
+// @Summary Отправить отчёт на утверждение
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Param action body dto.ReportActionRequest false "Комментарий"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id}/submit [post]
+func (ctrl *ExpenseReportController) SubmitReport(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.reportService.Submit)
+}
+
+// ApproveReport godoc
+// @Summary Утвердить отчёт
+// @Description Утверждает отчёт; расходы отчёта блокируются от изменения и удаления
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Param action body dto.ReportActionRequest false "Комментарий"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id}/approve [post]
+func (ctrl *ExpenseReportController) ApproveReport(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.reportService.Approve)
+}
+
+// RejectReport godoc
+// @Summary Отклонить отчёт
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Param action body dto.ReportActionRequest false "Причина отклонения"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id}/reject [post]
+func (ctrl *ExpenseReportController) RejectReport(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.reportService.Reject)
+}
+
+// PayReport godoc
+// @Summary Отметить отчёт оплаченным
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Param action body dto.ReportActionRequest false "Комментарий"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id}/pay [post]
+func (ctrl *ExpenseReportController) PayReport(c *gin.Context) {
+	ctrl.changeStatus(c, ctrl.reportService.MarkPaid)
+}
+
+// CommentReportLine godoc
+// @Summary Комментарий к строке отчёта
+// @Description Владелец комментирует строки черновика, утверждающий — строки отчёта на рассмотрении
+// @Tags reports
+// @Accept json
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Param line_id path int true "ID строки"
+// @Param action body dto.ReportActionRequest true "Комментарий"
+// @Success 200 {object} dto.ExpenseReportResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id}/lines/{line_id} [put]
+func (ctrl *ExpenseReportController) CommentReportLine(c *gin.Context) {
+	lineID, err := strconv.ParseUint(c.Param("line_id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid line ID")
+		return
+	}
+	var req dto.ReportActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	report, ok := ctrl.reportFromParam(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	if err := ctrl.reportService.CommentLine(c.Request.Context(), report, uint(lineID), user, req.Comment); err != nil {
+		writeReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toExpenseReportResponse(i18n.FromContext(c), *report))
+}
+
+// DeleteReport godoc
+// @Summary Удалить черновик отчёта
+// @Tags reports
+// @Produce json
+// @Param id path int true "ID отчёта"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reports/{id} [delete]
+func (ctrl *ExpenseReportController) DeleteReport(c *gin.Context) {
+	report, ok := ctrl.reportFromParam(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	if err := ctrl.reportService.DeleteReport(c.Request.Context(), report, user); err != nil {
+		writeReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Report %s deleted successfully", report.Title),
+	})
+}
+
+type reportAction func(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error
+
+func (ctrl *ExpenseReportController) changeStatus(c *gin.Context, action reportAction) {
+	var req dto.ReportActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid request")
+			return
+		}
+	}
+
+	report, ok := ctrl.reportFromParam(c)
+	if !ok {
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	if err := action(c.Request.Context(), report, user, req.Comment); err != nil {
+		writeReportError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, toExpenseReportResponse(i18n.FromContext(c), *report))
+}
+
+// reportFromParam загружает отчёт из пути; видеть отчёт могут владелец и утверждающие
+func (ctrl *ExpenseReportController) reportFromParam(c *gin.Context) (*models.ExpenseReport, bool) {
+	reportID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid report ID")
+		return nil, false
+	}
+
+	user := c.MustGet("user").(models.User)
+	report, err := ctrl.reportService.GetReportByID(c.Request.Context(), uint(reportID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "report not found")
+		return nil, false
+	}
+	if report.UserID != user.ID && !user.IsApprover() {
+		respondError(c, http.StatusForbidden, "cannot access another user's report")
+		return nil, false
+	}
+	return report, true
+}
+
+func writeReportError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, services.ErrEmptyReport):
+		respondError(c, http.StatusBadRequest, "no reimbursable expenses to report")
+	case errors.Is(err, services.ErrReportLineNotFound):
+		respondError(c, http.StatusNotFound, "report line not found")
+	case errors.Is(err, services.ErrNotApprover), errors.Is(err, services.ErrNotReportOwner):
+		respondError(c, http.StatusForbidden, err.Error())
+	case errors.Is(err, services.ErrExpenseAlreadyReported),
+		errors.Is(err, services.ErrInvalidTransition),
+		errors.Is(err, services.ErrReportNotEditable):
+		respondError(c, http.StatusConflict, err.Error())
+	default:
+		log.Printf("Expense report error: %v\n", err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+	}
+}
+
+func toExpenseReportResponse(loc i18n.Locale, report models.ExpenseReport) dto.ExpenseReportResponse {
+	resp := dto.ExpenseReportResponse{
+		ID:       fmt.Sprintf("%v", report.ID),
+		TravelID: fmt.Sprintf("%v", report.TravelID),
+		Title:    report.Title,
+		Status:   string(report.Status),
+		Lines:    make([]dto.ExpenseReportLineResponse, 0, len(report.Lines)),
+	}
+	for _, l := range report.Lines {
+		resp.Total += l.Expense.Amount
+		resp.Lines = append(resp.Lines, dto.ExpenseReportLineResponse{
+			ID:              fmt.Sprintf("%v", l.ID),
+			Expense:         toExpenseResponse(loc, l.Expense),
+			Comment:         l.Comment,
+			ApproverComment: l.ApproverComment,
+		})
+	}
+	for _, h := range report.History {
+		resp.History = append(resp.History, dto.ReportTransitionResponse{
+			From:    string(h.FromStatus),
+			To:      string(h.ToStatus),
+			ActorID: fmt.Sprintf("%v", h.ActorID),
+			Comment: h.Comment,
+			At:      h.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp
+}
+
+func toExpenseReportResponses(loc i18n.Locale, reports []models.ExpenseReport) []dto.ExpenseReportResponse {
+	resp := make([]dto.ExpenseReportResponse, 0, len(reports))
+	for _, r := range reports {
+		resp = append(resp, toExpenseReportResponse(loc, r))
+	}
+	return resp
+}
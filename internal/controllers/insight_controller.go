@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type InsightController struct {
+	insightService *services.InsightService
+}
+
+func NewInsightController(insightService *services.InsightService) *InsightController {
+	return &InsightController{insightService: insightService}
+}
+
+// GetInsights godoc
+// @Summary Лента наблюдений о тратах
+// @Description Возвращает необычные траты пользователя: дорогие записи, дни с тратами намного выше обычных и смещение долей категорий относительно прошлых поездок. Лента обновляется при создании, изменении и удалении расходов
+// @Tags insights
+// @Produce json
+// @Param travel_id query int false "ID путешествия; без него — по всем поездкам"
+// @Success 200 {array} dto.InsightResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/insights [get]
+func (ctrl *InsightController) GetInsights(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+
+	var travelID *uint
+	if s := c.Query("travel_id"); s != "" {
+		id, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid travel_id")
+			return
+		}
+		v := uint(id)
+		travelID = &v
+	}
+
+	insights, err := ctrl.insightService.GetInsights(c.Request.Context(), user.ID, travelID)
+	if err != nil {
+		log.Printf("Failed to get insights for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	loc := i18n.FromContext(c)
+	resp := make([]dto.InsightResponse, 0, len(insights))
+	for _, ins := range insights {
+		resp = append(resp, toInsightResponse(loc, ins))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+func toInsightResponse(loc i18n.Locale, ins models.Insight) dto.InsightResponse {
+	resp := dto.InsightResponse{
+		ID:        fmt.Sprintf("%v", ins.ID),
+		TravelID:  fmt.Sprintf("%v", ins.TravelID),
+		Type:      string(ins.Type),
+		Severity:  string(ins.Severity),
+		Value:     ins.Value,
+		Baseline:  ins.Baseline,
+		Ratio:     ins.Ratio,
+		CreatedAt: ins.CreatedAt.Format(time.RFC3339),
+	}
+	if ins.ExpenseID != nil {
+		resp.ExpenseID = fmt.Sprintf("%v", *ins.ExpenseID)
+	}
+	if ins.CategoryID != nil {
+		resp.CategoryID = fmt.Sprintf("%v", *ins.CategoryID)
+	}
+	if ins.Category != nil {
+		resp.Category = i18n.CategoryName(loc, ins.Category.Key, ins.Category.Name)
+	}
+	if ins.Day != nil {
+		resp.Date = ins.Day.Format("2006-01-02")
+	}
+
+	switch {
+	case ins.Type == models.InsightOutlierExpense:
+		resp.Message = fmt.Sprintf(i18n.T(loc, "expense of %.2f is %.1f× your usual %.2f in %s"),
+			ins.Value, ins.Ratio, ins.Baseline, resp.Category)
+	case ins.Type == models.InsightUnusualDay && ins.CategoryID != nil:
+		resp.Message = fmt.Sprintf(i18n.T(loc, "on %s you spent %.1f× your usual on %s: %.2f instead of about %.2f"),
+			resp.Date, ins.Ratio, resp.Category, ins.Value, ins.Baseline)
+	case ins.Type == models.InsightUnusualDay:
+		resp.Message = fmt.Sprintf(i18n.T(loc, "on %s you spent %.1f× your usual: %.2f instead of about %.2f"),
+			resp.Date, ins.Ratio, ins.Value, ins.Baseline)
+	case ins.Type == models.InsightCategoryShift:
+		resp.Message = fmt.Sprintf(i18n.T(loc, "%s is %.0f%% of this trip's spending vs %.0f%% on previous trips"),
+			resp.Category, ins.Value*100, ins.Baseline*100)
+	}
+	return resp
+}
@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PolicyController struct {
+	policyService   *services.PolicyService
+	travelService   *services.TravelService
+	categoryService *services.CategoryService
+}
+
+func NewPolicyController(policyService *services.PolicyService, travelService *services.TravelService, categoryService *services.CategoryService) *PolicyController {
+	return &PolicyController{
+		policyService:   policyService,
+		travelService:   travelService,
+		categoryService: categoryService,
+	}
+}
+
+// CreatePolicy godoc
+// @Summary Создать правило трат
+// @Description Добавляет правило для всех поездок пользователя или для одной поездки и перепроверяет расходы
+// @Tags policies
+// @Accept json
+// @Produce json
+// @Param policy body dto.CreatePolicyRequest true "Правило"
+// @Success 200 {object} dto.PolicyResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/policies [post]
+func (ctrl *PolicyController) CreatePolicy(c *gin.Context) {
+	var req dto.CreatePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+
+	if req.TravelID != nil {
+		travel, err := ctrl.travelService.GetTravelByID(ctx, *req.TravelID)
+		if err != nil || travel.UserID != user.ID {
+			respondError(c, http.StatusForbidden, "invalid travel")
+			return
+		}
+	}
+	if req.CategoryID != nil {
+		if _, err := ctrl.categoryService.ResolveCategory(ctx, user.ID, req.CategoryID, ""); err != nil {
+			respondError(c, http.StatusBadRequest, "category not found")
+			return
+		}
+	}
+
+	policy := &models.SpendingPolicy{
+		UserID:       user.ID,
+		TravelID:     req.TravelID,
+		Name:         req.Name,
+		RuleType:     models.PolicyRuleType(req.RuleType),
+		CategoryID:   req.CategoryID,
+		Limit:        req.Limit,
+		RequiresNote: req.RequiresNote,
+		Severity:     models.Severity(req.Severity),
+	}
+
+	if err := ctrl.policyService.CreatePolicy(ctx, policy); err != nil {
+		if errors.Is(err, services.ErrInvalidPolicy) {
+			respondError(c, http.StatusBadRequest, "invalid policy")
+			return
+		}
+		log.Printf("Failed to create policy for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, toPolicyResponse(*policy))
+}
+
+// GetPolicies godoc
+// @Summary Правила трат пользователя
+// @Tags policies
+// @Produce json
+// @Success 200 {array} dto.PolicyResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/policies [get]
+func (ctrl *PolicyController) GetPolicies(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	policies, err := ctrl.policyService.GetPoliciesByUserID(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Printf("Failed to get policies for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	resp := make([]dto.PolicyResponse, 0, len(policies))
+	for _, p := range policies {
+		resp = append(resp, toPolicyResponse(p))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// DeletePolicy godoc
+// @Summary Удалить правило трат
+// @Description Удаляет правило и его нарушения
+// @Tags policies
+// @Produce json
+// @Param id path int true "ID правила"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/policies/{id} [delete]
+func (ctrl *PolicyController) DeletePolicy(c *gin.Context) {
+	policyID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid policy ID")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+
+	policy, err := ctrl.policyService.GetPolicyByID(ctx, uint(policyID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "policy not found")
+		return
+	}
+	if policy.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "cannot delete another user's policy")
+		return
+	}
+
+	if err := ctrl.policyService.DeletePolicy(ctx, policy); err != nil {
+		log.Printf("Failed to delete policy %d: %v\n", policy.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Policy %s deleted successfully", policy.Name),
+	})
+}
+
+// GetCompliance godoc
+// @Summary Соответствие поездки правилам трат
+// @Description Возвращает сводку нарушений правил трат по поездке
+// @Tags policies
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Success 200 {object} dto.ComplianceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/compliance [get]
+func (ctrl *PolicyController) GetCompliance(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	violations, checked, err := ctrl.policyService.GetCompliance(c.Request.Context(), user.ID, travel.ID)
+	if err != nil {
+		log.Printf("Failed to get compliance for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	resp := dto.ComplianceResponse{
+		ExpensesChecked: checked,
+		BySeverity:      make(map[string]int),
+		Violations:      make([]dto.PolicyViolationResponse, 0, len(violations)),
+	}
+	violating := make(map[uint]bool)
+	for _, v := range violations {
+		violating[v.ExpenseID] = true
+		resp.BySeverity[string(v.Severity)]++
+		resp.Violations = append(resp.Violations, toPolicyViolationResponse(v))
+	}
+	resp.ViolatingExpenses = len(violating)
+	resp.Compliant = len(violations) == 0
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func toPolicyResponse(p models.SpendingPolicy) dto.PolicyResponse {
+	resp := dto.PolicyResponse{
+		ID:           fmt.Sprintf("%v", p.ID),
+		Name:         p.Name,
+		RuleType:     string(p.RuleType),
+		Limit:        p.Limit,
+		RequiresNote: p.RequiresNote,
+		Severity:     string(p.Severity),
+	}
+	if p.TravelID != nil {
+		resp.TravelID = fmt.Sprintf("%v", *p.TravelID)
+	}
+	if p.CategoryID != nil {
+		resp.CategoryID = fmt.Sprintf("%v", *p.CategoryID)
+	}
+	return resp
+}
+
+func toPolicyViolationResponse(v models.PolicyViolation) dto.PolicyViolationResponse {
+	return dto.PolicyViolationResponse{
+		ExpenseID: fmt.Sprintf("%v", v.ExpenseID),
+		PolicyID:  fmt.Sprintf("%v", v.PolicyID),
+		Severity:  string(v.Severity),
+		Message:   v.Message,
+	}
+}
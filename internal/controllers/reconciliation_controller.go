@@ -0,0 +1,321 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReconciliationController struct {
+	reconciliationService *services.ReconciliationService
+	travelService         *services.TravelService
+}
+
+func NewReconciliationController(reconciliationService *services.ReconciliationService, travelService *services.TravelService) *ReconciliationController {
+	return &ReconciliationController{
+		reconciliationService: reconciliationService,
+		travelService:         travelService,
+	}
+}
+
+// ImportTransactions godoc
+// @Summary Импорт банковских операций
+// @Description Загружает строки банковской выписки для сверки с расходами поездки. Положительная сумма — списание, отрицательная — поступление (возврат, компенсация или доход); нулевая сумма не принимается
+// @Tags reconciliation
+// @Accept json
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Param transactions body dto.ImportTransactionsRequest true "Банковские операции"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/transactions [post]
+func (ctrl *ReconciliationController) ImportTransactions(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	var req dto.ImportTransactionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request")
+		return
+	}
+
+	transactions := make([]models.BankTransaction, 0, len(req.Transactions))
+	for _, t := range req.Transactions {
+		date, err := time.Parse("2006-01-02", t.Date)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, "invalid date")
+			return
+		}
+		transactions = append(transactions, models.BankTransaction{
+			UserID:      user.ID,
+			TravelID:    travel.ID,
+			Amount:      t.Amount,
+			Date:        date,
+			Description: t.Description,
+		})
+	}
+
+	ctx := c.Request.Context()
+	if err := ctrl.reconciliationService.ImportTransactions(ctx, transactions); err != nil {
+		if errors.Is(err, services.ErrInvalidAmount) {
+			respondError(c, http.StatusBadRequest, "invalid amount")
+			return
+		}
+		log.Printf("Failed to import transactions for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("%d transactions imported", len(transactions)),
+	})
+}
+
+// GetMatches godoc
+// @Summary Пары сверки поездки
+// @Description Возвращает предложенные, подтверждённые и отклонённые пары "операция ↔ расход"
+// @Tags reconciliation
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Success 200 {array} dto.ReconciliationMatchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/reconciliation [get]
+func (ctrl *ReconciliationController) GetMatches(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	matches, err := ctrl.reconciliationService.GetMatches(c.Request.Context(), user.ID, travel.ID)
+	if err != nil {
+		log.Printf("Failed to get matches for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, toMatchResponses(i18n.FromContext(c), matches))
+}
+
+// ProposeMatches godoc
+// @Summary Подобрать пары для сверки
+// @Description Сопоставляет несверенные операции с расходами по сумме, дате и описанию и сохраняет предложения
+// @Tags reconciliation
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Success 200 {array} dto.ReconciliationMatchResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/reconciliation [post]
+func (ctrl *ReconciliationController) ProposeMatches(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	matches, err := ctrl.reconciliationService.ProposeMatches(c.Request.Context(), user.ID, travel.ID)
+	if err != nil {
+		log.Printf("Failed to propose matches for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, toMatchResponses(i18n.FromContext(c), matches))
+}
+
+// GetUnreconciled godoc
+// @Summary Несверенные записи поездки
+// @Description Возвращает банковские операции без расхода и расходы без банковской операции
+// @Tags reconciliation
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Success 200 {object} dto.UnreconciledResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/reconciliation/unreconciled [get]
+func (ctrl *ReconciliationController) GetUnreconciled(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	transactions, expenses, err := ctrl.reconciliationService.GetUnreconciled(c.Request.Context(), user.ID, travel.ID)
+	if err != nil {
+		log.Printf("Failed to build unreconciled report for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	resp := dto.UnreconciledResponse{
+		Transactions: make([]dto.BankTransactionResponse, 0, len(transactions)),
+		Expenses:     make([]dto.ExpenseResponse, 0, len(expenses)),
+	}
+	for _, t := range transactions {
+		resp.Transactions = append(resp.Transactions, toBankTransactionResponse(t))
+	}
+	loc := i18n.FromContext(c)
+	for _, e := range expenses {
+		resp.Expenses = append(resp.Expenses, toExpenseResponse(loc, e))
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// ConfirmMatch godoc
+// @Summary Подтвердить пару
+// @Description Привязывает банковскую операцию к расходу без изменения расхода
+// @Tags reconciliation
+// @Produce json
+// @Param id path int true "ID пары"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reconciliation/{id}/confirm [post]
+func (ctrl *ReconciliationController) ConfirmMatch(c *gin.Context) {
+	ctrl.resolveMatch(c, ctrl.reconciliationService.ConfirmMatch, "Match confirmed")
+}
+
+// RejectMatch godoc
+// @Summary Отклонить пару
+// @Description Помечает пару как ошибочную; она больше не будет предлагаться
+// @Tags reconciliation
+// @Produce json
+// @Param id path int true "ID пары"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/reconciliation/{id}/reject [post]
+func (ctrl *ReconciliationController) RejectMatch(c *gin.Context) {
+	ctrl.resolveMatch(c, ctrl.reconciliationService.RejectMatch, "Match rejected")
+}
+
+// MergeMatch godoc
+// @Summary Объединить пару
+// @Description Подтверждает пару и переносит в расход сумму и дату из банковской операции
+// @Tags reconciliation
+// @Produce json
+// @Param id path int true "ID пары"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Failure 409 {object} map[string]string "Расход входит в неотклонённый отчёт"
+// @Router /api/reconciliation/{id}/merge [post]
+func (ctrl *ReconciliationController) MergeMatch(c *gin.Context) {
+	ctrl.resolveMatch(c, ctrl.reconciliationService.MergeMatch, "Match merged")
+}
+
+func (ctrl *ReconciliationController) resolveMatch(c *gin.Context, action func(ctx context.Context, match *models.ReconciliationMatch) error, message string) {
+	matchID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid match ID")
+		return
+	}
+
+	user := c.MustGet("user").(models.User)
+	ctx := c.Request.Context()
+	match, err := ctrl.reconciliationService.GetMatchByID(ctx, uint(matchID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "match not found")
+		return
+	}
+
+	if match.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "cannot change another user's match")
+		return
+	}
+
+	if err := action(ctx, match); err != nil {
+		if errors.Is(err, services.ErrMatchNotProposed) {
+			respondError(c, http.StatusBadRequest, "match is already resolved")
+			return
+		}
+		if errors.Is(err, services.ErrMatchDirection) || isEntryValidationError(err) {
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.Is(err, services.ErrExpenseLocked) {
+			respondError(c, http.StatusConflict, "expense is locked by an approved report")
+			return
+		}
+		if errors.Is(err, services.ErrExpenseReported) {
+			respondError(c, http.StatusConflict, "expense is included in a report")
+			return
+		}
+		log.Printf("Failed to resolve match %d: %v\n", match.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func toBankTransactionResponse(t models.BankTransaction) dto.BankTransactionResponse {
+	resp := dto.BankTransactionResponse{
+		ID:          fmt.Sprintf("%v", t.ID),
+		Amount:      t.Amount,
+		Date:        t.Date.Format("2006-01-02"),
+		Description: t.Description,
+	}
+	if t.ExpenseID != nil {
+		resp.ExpenseID = fmt.Sprintf("%v", *t.ExpenseID)
+	}
+	return resp
+}
+
+func toMatchResponses(loc i18n.Locale, matches []models.ReconciliationMatch) []dto.ReconciliationMatchResponse {
+	resp := make([]dto.ReconciliationMatchResponse, 0, len(matches))
+	for _, m := range matches {
+		resp = append(resp, dto.ReconciliationMatchResponse{
+			ID:          fmt.Sprintf("%v", m.ID),
+			Score:       m.Score,
+			Status:      string(m.Status),
+			Transaction: toBankTransactionResponse(m.Transaction),
+			Expense:     toExpenseResponse(loc, m.Expense),
+		})
+	}
+	return resp
+}
@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 	"wanderwallet/internal/dto"
 	"wanderwallet/internal/models"
@@ -38,9 +40,7 @@ func NewTravelController(travelService *services.TravelService) *TravelControlle
 func (ctrl *TravelController) CreateTravel(c *gin.Context) {
 	var req dto.CreateTravelRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request format",
-		})
+		respondError(c, http.StatusBadRequest, "invalid request format")
 		return
 	}
 	user := c.MustGet("user").(models.User)
@@ -48,20 +48,28 @@ func (ctrl *TravelController) CreateTravel(c *gin.Context) {
 
 	startDate, err := time.Parse("2006-01-02", req.StartDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid start date"})
+		respondError(c, http.StatusBadRequest, "invalid start date")
 		return
 	}
 
 	endDate, err := time.Parse("2006-01-02", req.EndDate)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid end date"})
+		respondError(c, http.StatusBadRequest, "invalid end date")
 		return
 	}
 
-	travel, err := ctrl.travelService.CreateTravel(ctx, user.ID, req.Title, startDate, endDate)
+	travel, err := ctrl.travelService.CreateTravel(ctx, user.ID, req.Title, startDate, endDate, req.Participants, req.Budget, req.Country)
 	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidBudget):
+			respondError(c, http.StatusBadRequest, "invalid budget")
+			return
+		case errors.Is(err, services.ErrInvalidCountry):
+			respondError(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		log.Printf("Failed to create travel for user %d: %v\n", user.ID, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": http.StatusText(http.StatusInternalServerError)})
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
 		return
 	}
 
@@ -69,3 +77,67 @@ func (ctrl *TravelController) CreateTravel(c *gin.Context) {
 		"message": fmt.Sprintf("Travel %s created", travel.Title),
 	})
 }
+
+// SetBudget godoc
+// @Summary Задать бюджет путешествия
+// @Description Задаёт бюджет на чистые траты поездки; null снимает бюджет
+// @Tags travel
+// @Accept json
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Param budget body dto.SetBudgetRequest true "Бюджет"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/budget [put]
+func (ctrl *TravelController) SetBudget(c *gin.Context) {
+	var req dto.SetBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request format")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	if err := ctrl.travelService.SetBudget(c.Request.Context(), travel, req.Budget); err != nil {
+		if errors.Is(err, services.ErrInvalidBudget) {
+			respondError(c, http.StatusBadRequest, "invalid budget")
+			return
+		}
+		log.Printf("Failed to set budget for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Budget of travel %s updated", travel.Title),
+	})
+}
+
+// ownTravelFromParam читает ID поездки из пути и проверяет, что она принадлежит пользователю.
+// При ошибке ответ уже отправлен и возвращается false.
+func ownTravelFromParam(c *gin.Context, travelService *services.TravelService, user models.User) (*models.Travel, bool) {
+	travelID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid travel ID")
+		return nil, false
+	}
+
+	travel, err := travelService.GetTravelByID(c.Request.Context(), uint(travelID))
+	if err != nil {
+		respondError(c, http.StatusNotFound, "travel not found")
+		return nil, false
+	}
+	if travel.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "invalid travel")
+		return nil, false
+	}
+	return travel, true
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TripReportController struct {
+	tripReportService *services.TripReportService
+	travelService     *services.TravelService
+}
+
+func NewTripReportController(tripReportService *services.TripReportService, travelService *services.TravelService) *TripReportController {
+	return &TripReportController{
+		tripReportService: tripReportService,
+		travelService:     travelService,
+	}
+}
+
+// GetTripReport godoc
+// @Summary PDF-отчёт по поездке
+// @Description Строит PDF: шапка поездки, итоги, таблица категорий, графики и расходы по дням с комментариями. Если записей больше 300 или передан async=true, отчёт строится в фоне: возвращается 202 с заданием, состояние которого можно запрашивать по status_url. Текст выводится встроенным шрифтом Open Sans; поддерживаются латиница и кириллица, прочие символы заменяются на «?»
+// @Tags reports
+// @Produce application/pdf
+// @Produce json
+// @Param id path int true "ID путешествия"
+// @Param async query bool false "Строить отчёт в фоне независимо от размера"
+// @Success 200 {file} file
+// @Success 202 {object} dto.TripReportJobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/travel/{id}/report.pdf [get]
+func (ctrl *TripReportController) GetTripReport(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	travel, ok := ownTravelFromParam(c, ctrl.travelService, user)
+	if !ok {
+		return
+	}
+
+	async := false
+	if s := c.Query("async"); s != "" {
+		var err error
+		if async, err = strconv.ParseBool(s); err != nil {
+			respondError(c, http.StatusBadRequest, "invalid query params")
+			return
+		}
+	}
+
+	file, job, err := ctrl.tripReportService.Report(c.Request.Context(), travel, async, time.Now(), i18n.FromContext(c))
+	if err != nil {
+		log.Printf("Failed to build trip report for travel %d: %v\n", travel.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	if job != nil {
+		resp := toTripReportJobResponse(job)
+		c.Header("Location", resp.StatusURL)
+		c.JSON(http.StatusAccepted, resp)
+		return
+	}
+	sendTripReport(c, travel.ID, file)
+}
+
+// GetTripReportJob godoc
+// @Summary Состояние фонового PDF-отчёта
+// @Description Возвращает состояние задания; когда status = done, файл доступен по download_url. Задания и файлы хранятся сутки
+// @Tags reports
+// @Produce json
+// @Param id path int true "ID задания"
+// @Success 200 {object} dto.TripReportJobResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/report-jobs/{id} [get]
+func (ctrl *TripReportController) GetTripReportJob(c *gin.Context) {
+	job, ok := ctrl.ownJobFromParam(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, toTripReportJobResponse(job))
+}
+
+// DownloadTripReport godoc
+// @Summary Скачать готовый PDF-отчёт
+// @Tags reports
+// @Produce application/pdf
+// @Param id path int true "ID задания"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/report-jobs/{id}/file [get]
+func (ctrl *TripReportController) DownloadTripReport(c *gin.Context) {
+	job, ok := ctrl.ownJobFromParam(c)
+	if !ok {
+		return
+	}
+
+	file, err := ctrl.tripReportService.JobFile(c.Request.Context(), job)
+	if err != nil {
+		if errors.Is(err, services.ErrTripReportNotReady) || errors.Is(err, services.ErrTripReportFailed) {
+			respondError(c, http.StatusConflict, err.Error())
+			return
+		}
+		log.Printf("Failed to load trip report job %d: %v\n", job.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	sendTripReport(c, job.TravelID, file)
+}
+
+func (ctrl *TripReportController) ownJobFromParam(c *gin.Context) (*models.TripReportJob, bool) {
+	user := c.MustGet("user").(models.User)
+	jobID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid job ID")
+		return nil, false
+	}
+
+	job, err := ctrl.tripReportService.GetJob(c.Request.Context(), uint(jobID), time.Now())
+	if err != nil {
+		respondError(c, http.StatusNotFound, "report job not found")
+		return nil, false
+	}
+	if job.UserID != user.ID {
+		respondError(c, http.StatusForbidden, "cannot access another user's report")
+		return nil, false
+	}
+	return job, true
+}
+
+func sendTripReport(c *gin.Context, travelID uint, file []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="travel-%d-report.pdf"`, travelID))
+	c.Data(http.StatusOK, "application/pdf", file)
+}
+
+func toTripReportJobResponse(job *models.TripReportJob) dto.TripReportJobResponse {
+	resp := dto.TripReportJobResponse{
+		ID:        fmt.Sprintf("%v", job.ID),
+		TravelID:  fmt.Sprintf("%v", job.TravelID),
+		Status:    string(job.Status),
+		Error:     job.Error,
+		StatusURL: fmt.Sprintf("/api/report-jobs/%d", job.ID),
+		CreatedAt: job.CreatedAt.Format(time.RFC3339),
+	}
+	if job.Status == models.TripReportDone {
+		resp.DownloadURL = resp.StatusURL + "/file"
+	}
+	if job.FinishedAt != nil {
+		resp.FinishedAt = job.FinishedAt.Format(time.RFC3339)
+	}
+	return resp
+}
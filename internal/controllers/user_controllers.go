@@ -1,9 +1,15 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 	"wanderwallet/internal/dto"
+	"wanderwallet/internal/models"
 	"wanderwallet/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -26,7 +32,7 @@ func NewUserController(userService *services.UserService) *UserController {
 // @Accept json
 // @Produce json
 // @Param user body dto.UserRequest true "Данные пользователя"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} dto.TokenResponse
 // @Failure 400 {object} map[string]string
 // @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -34,34 +40,23 @@ func NewUserController(userService *services.UserService) *UserController {
 func (ctrl *UserController) Register(c *gin.Context) {
 	var req dto.UserRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request format",
-		})
+		respondError(c, http.StatusBadRequest, "invalid request format")
 		return
 	}
 	ctx := c.Request.Context()
-	response, err := ctrl.userService.Register(ctx, req.Login, req.Password)
+	response, err := ctrl.userService.Register(ctx, req.Login, req.Password, deviceOf(c))
 	if err != nil {
 		switch err {
 		case services.ErrUserAlreadyExists:
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "login already exists",
-			})
+			respondError(c, http.StatusConflict, "login already exists")
 		default:
 			log.Printf("Registration error: %v\n", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "internal server error",
-			})
+			respondError(c, http.StatusInternalServerError, "internal server error")
 		}
 		return
 	}
 
-	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", response.Token, 3600*24*30, "", "", false, true)
-
-	c.JSON(http.StatusOK, gin.H{
-		"message": "user registered and authenticated successfully",
-	})
+	respondTokens(c, response.Tokens, "user registered and authenticated successfully")
 }
 
 // Login godoc
@@ -71,7 +66,7 @@ func (ctrl *UserController) Register(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param user body dto.UserRequest true "Данные пользователя для входа"
-// @Success 200 {object} map[string]string
+// @Success 200 {object} dto.TokenResponse
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -80,32 +75,371 @@ func (ctrl *UserController) Login(c *gin.Context) {
 	var req dto.UserRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "invalid request format",
-		})
+		respondError(c, http.StatusBadRequest, "invalid request format")
 		return
 	}
 	ctx := c.Request.Context()
-	response, err := ctrl.userService.Login(ctx, req.Login, req.Password)
+	response, err := ctrl.userService.Login(ctx, req.Login, req.Password, deviceOf(c))
 	if err != nil {
 		switch err {
 		case services.ErrUserNotFound, services.ErrInvalidPassword:
-			c.JSON(http.StatusUnauthorized, gin.H{
-				"error": "invalid login or password",
-			})
+			respondError(c, http.StatusUnauthorized, "invalid login or password")
 		default:
 			log.Printf("Login error: %v\n", err)
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"error": "internal server error",
-			})
+			respondError(c, http.StatusInternalServerError, "internal server error")
 		}
 		return
 	}
 
+	respondTokens(c, response.Tokens, "user authenticated successfully")
+}
+
+func deviceOf(c *gin.Context) dto.Device {
+	return dto.Device{UserAgent: c.Request.UserAgent(), IP: c.ClientIP()}
+}
+
+// refreshCookiePath — куку с токеном обновления браузер отправляет только в /api/auth
+const refreshCookiePath = "/api/auth"
+
+// respondTokens устанавливает куки авторизации и отвечает парой токенов
+func respondTokens(c *gin.Context, tokens dto.Tokens, message string) {
 	c.SetSameSite(http.SameSiteLaxMode)
-	c.SetCookie("Authorization", response.Token, 3600*24*30, "", "", false, true)
+	c.SetCookie("Authorization", tokens.Token, int(services.AccessTokenTTL.Seconds()), "", "", false, true)
+	c.SetCookie("RefreshToken", tokens.RefreshToken, int(services.RefreshTokenTTL.Seconds()), refreshCookiePath, "", false, true)
+
+	c.JSON(http.StatusOK, dto.TokenResponse{
+		Message:      message,
+		Token:        tokens.Token,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    int(services.AccessTokenTTL.Seconds()),
+	})
+}
+
+// Refresh godoc
+// @Summary Обновление токенов
+// @Description Обменивает токен обновления на новую пару токенов. Токен обновления одноразовый: повторное использование отзывает всю сессию
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param token body dto.RefreshRequest false "Токен обновления; по умолчанию берётся из куки RefreshToken"
+// @Success 200 {object} dto.TokenResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/refresh [post]
+func (ctrl *UserController) Refresh(c *gin.Context) {
+	var req dto.RefreshRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		respondError(c, http.StatusBadRequest, "invalid request format")
+		return
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken, _ = c.Cookie("RefreshToken")
+	}
+	if req.RefreshToken == "" {
+		respondError(c, http.StatusUnauthorized, "invalid refresh token")
+		return
+	}
 
+	tokens, err := ctrl.userService.Refresh(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRefreshToken), errors.Is(err, services.ErrRefreshTokenReused):
+			respondError(c, http.StatusUnauthorized, "invalid refresh token")
+		default:
+			log.Printf("Failed to refresh tokens: %v\n", err)
+			respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		}
+		return
+	}
+	respondTokens(c, *tokens, "tokens refreshed successfully")
+}
+
+// Logout godoc
+// @Summary Выход
+// @Description Отзывает текущую сессию: её токены доступа и обновления перестают приниматься
+// @Tags auth
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/logout [post]
+func (ctrl *UserController) Logout(c *gin.Context) {
+	sessionID := c.MustGet("session_id").(uint)
+	if err := ctrl.userService.Logout(c.Request.Context(), sessionID); err != nil {
+		log.Printf("Failed to revoke session %d: %v\n", sessionID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie("Authorization", "", -1, "", "", false, true)
+	c.SetCookie("RefreshToken", "", -1, refreshCookiePath, "", false, true)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "logged out successfully",
+	})
+}
+
+// GetSessions godoc
+// @Summary Активные сессии
+// @Description Возвращает устройства, на которых выполнен вход, начиная с последнего активного
+// @Tags auth
+// @Produce json
+// @Success 200 {array} dto.SessionResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/sessions [get]
+func (ctrl *UserController) GetSessions(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	currentID := c.MustGet("session_id").(uint)
+
+	sessions, err := ctrl.userService.GetSessions(c.Request.Context(), user.ID)
+	if err != nil {
+		log.Printf("Failed to get sessions for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+
+	resp := make([]dto.SessionResponse, 0, len(sessions))
+	for _, s := range sessions {
+		lastSeen := s.CreatedAt
+		if s.LastSeenAt != nil {
+			lastSeen = *s.LastSeenAt
+		}
+		resp = append(resp, dto.SessionResponse{
+			ID:         fmt.Sprintf("%v", s.ID),
+			UserAgent:  s.UserAgent,
+			IP:         s.IP,
+			CreatedAt:  s.CreatedAt.Format(time.RFC3339),
+			LastSeenAt: lastSeen.Format(time.RFC3339),
+			Current:    s.ID == currentID,
+		})
+	}
+	c.JSON(http.StatusOK, resp)
+}
+
+// RevokeSession godoc
+// @Summary Завершение сессии
+// @Description Выходит на выбранном устройстве: токены сессии перестают приниматься
+// @Tags auth
+// @Produce json
+// @Param id path string true "ID сессии"
+// @Success 204
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/sessions/{id} [delete]
+func (ctrl *UserController) RevokeSession(c *gin.Context) {
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "invalid session ID")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+
+	if err := ctrl.userService.RevokeSession(c.Request.Context(), user.ID, uint(sessionID)); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			respondError(c, http.StatusNotFound, "session not found")
+			return
+		}
+		log.Printf("Failed to revoke session %d: %v\n", sessionID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions godoc
+// @Summary Выход на всех других устройствах
+// @Description Завершает все сессии пользователя, кроме текущей
+// @Tags auth
+// @Produce json
+// @Success 200 {object} dto.RevokeSessionsResponse
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/sessions [delete]
+func (ctrl *UserController) RevokeOtherSessions(c *gin.Context) {
+	user := c.MustGet("user").(models.User)
+	currentID := c.MustGet("session_id").(uint)
+
+	revoked, err := ctrl.userService.RevokeOtherSessions(c.Request.Context(), user.ID, currentID)
+	if err != nil {
+		log.Printf("Failed to revoke other sessions for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.JSON(http.StatusOK, dto.RevokeSessionsResponse{Revoked: revoked})
+}
+
+// SetLocale godoc
+// @Summary Язык пользователя
+// @Description Сохраняет язык названий встроенных категорий и сообщений об ошибках. Пустое значение возвращает выбор по заголовку Accept-Language
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param locale body dto.LocaleRequest true "Язык"
+// @Success 200 {object} dto.LocaleResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/locale [put]
+func (ctrl *UserController) SetLocale(c *gin.Context) {
+	var req dto.LocaleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request format")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+
+	locale, err := ctrl.userService.SetLocale(c.Request.Context(), user.ID, req.Locale)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidLocale) {
+			respondError(c, http.StatusBadRequest, "invalid locale")
+			return
+		}
+		log.Printf("Failed to set locale for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.JSON(http.StatusOK, dto.LocaleResponse{Locale: locale})
+}
+
+// SetEmail godoc
+// @Summary Почта пользователя
+// @Description Сохраняет адрес, на который приходит код сброса пароля. Пустое значение удаляет адрес
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param email body dto.EmailRequest true "Адрес почты"
+// @Success 200 {object} dto.EmailResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string "Адрес указан у другого пользователя"
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/email [put]
+func (ctrl *UserController) SetEmail(c *gin.Context) {
+	var req dto.EmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid email")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+
+	email, err := ctrl.userService.SetEmail(c.Request.Context(), user.ID, req.Email)
+	if err != nil {
+		if errors.Is(err, services.ErrEmailTaken) {
+			respondError(c, http.StatusConflict, "email already in use")
+			return
+		}
+		log.Printf("Failed to set email for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.JSON(http.StatusOK, dto.EmailResponse{Email: email})
+}
+
+// ChangePassword godoc
+// @Summary Смена пароля
+// @Description Меняет пароль после проверки текущего. Все сессии, кроме текущей, завершаются
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param password body dto.ChangePasswordRequest true "Текущий и новый пароль"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security ApiKeyAuth
+// @Router /api/auth/password [put]
+func (ctrl *UserController) ChangePassword(c *gin.Context) {
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request format")
+		return
+	}
+	user := c.MustGet("user").(models.User)
+	sessionID := c.MustGet("session_id").(uint)
+
+	err := ctrl.userService.ChangePassword(c.Request.Context(), &user, sessionID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPassword) {
+			respondError(c, http.StatusForbidden, "invalid current password")
+			return
+		}
+		log.Printf("Failed to change password for user %d: %v\n", user.ID, err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "password changed successfully",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary Запрос сброса пароля
+// @Description Отправляет код сброса пароля на почту пользователя. Код действует час. Ответ не зависит от того, найден ли пользователь
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Логин или адрес почты"
+// @Success 202 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/password/forgot [post]
+func (ctrl *UserController) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request format")
+		return
+	}
+
+	if err := ctrl.userService.RequestPasswordReset(c.Request.Context(), req.Login); err != nil {
+		log.Printf("Failed to request password reset: %v\n", err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"message": "if the account has an email, a reset code has been sent",
+	})
+}
+
+// ResetPassword godoc
+// @Summary Сброс пароля
+// @Description Устанавливает новый пароль по коду из письма. Код одноразовый; все сессии пользователя завершаются
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Код и новый пароль"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/auth/password/reset [post]
+func (ctrl *UserController) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, "invalid request format")
+		return
+	}
+
+	if err := ctrl.userService.ResetPassword(c.Request.Context(), req.Token, req.Password); err != nil {
+		if errors.Is(err, services.ErrInvalidResetToken) {
+			respondError(c, http.StatusBadRequest, "invalid or expired reset token")
+			return
+		}
+		log.Printf("Failed to reset password: %v\n", err)
+		respondError(c, http.StatusInternalServerError, http.StatusText(http.StatusInternalServerError))
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": "user authenticated successfully",
+		"message": "password reset successfully",
 	})
 }
@@ -0,0 +1,30 @@
+package dto
+
+type MealsProvidedRequest struct {
+	Date      string `json:"date" binding:"required"` // формат YYYY-MM-DD
+	Breakfast bool   `json:"breakfast"`
+	Lunch     bool   `json:"lunch"`
+	Dinner    bool   `json:"dinner"`
+}
+
+type GeneratePerDiemRequest struct {
+	Country string                 `json:"country" binding:"required"` // ISO 3166-1 alpha-2
+	City    string                 `json:"city"`
+	Meals   []MealsProvidedRequest `json:"meals" binding:"dive"` // дни, когда питание оплачено
+}
+
+type CreateMileageRequest struct {
+	Date       string  `json:"date" binding:"required"` // формат YYYY-MM-DD
+	DistanceKm float64 `json:"distance_km" binding:"required"`
+	Rate       float64 `json:"rate"` // ставка за км; если не задана, берётся из таблицы ставок
+	Country    string  `json:"country"`
+	City       string  `json:"city"`
+	Comment    string  `json:"comment"`
+}
+
+type PerDiemRateResponse struct {
+	Country     string  `json:"country"`
+	City        string  `json:"city,omitempty"`
+	DailyRate   float64 `json:"daily_rate"`
+	MileageRate float64 `json:"mileage_rate"`
+}
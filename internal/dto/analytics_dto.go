@@ -1,7 +1,219 @@
 package dto
 
+// AmountSummary — траты, возвраты, доходы и начисления по нормативу по отдельности.
+// Net = Gross - Refunds; доходы и суточные с пробегом в чистые траты не входят.
+type AmountSummary struct {
+	Gross      float64 `json:"gross"`
+	Refunds    float64 `json:"refunds"`
+	Income     float64 `json:"income"`
+	Allowances float64 `json:"allowances"`
+	Net        float64 `json:"net"`
+}
+
+// CategoryNode — узел дерева категорий: Own — записи самой категории,
+// Total — вместе со всеми подкатегориями
+type CategoryNode struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Own      AmountSummary  `json:"own"`
+	Total    AmountSummary  `json:"total"`
+	Children []CategoryNode `json:"children,omitempty"`
+}
+
+// TimeBucket — суммы за период с Start по End включительно (YYYY-MM-DD)
+type TimeBucket struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	AmountSummary
+}
+
 type AnalyticsResponse struct {
-	Total      float64            `json:"total"`
-	ByCategory map[string]float64 `json:"by_category"`
-	ByDay      map[string]float64 `json:"by_day"`
+	Total        AmountSummary            `json:"total"`
+	ByCategory   map[string]AmountSummary `json:"by_category"` // только записи самой категории
+	CategoryTree []CategoryNode           `json:"category_tree"`
+	Granularity  string                   `json:"granularity"` // day | week | month
+	Timezone     string                   `json:"timezone"`
+	Series       []TimeBucket             `json:"series"` // по возрастанию, пустые периоды с нулями
+}
+
+// TripCategoryMetrics — чистые траты поездки в одной категории
+type TripCategoryMetrics struct {
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Net        float64 `json:"net"`
+	PerDay     float64 `json:"per_day"`
+	Share      float64 `json:"share"` // доля от чистых трат поездки, 0..1
+}
+
+// TripDeviation — отличие поездки от средней по поездкам пользователя.
+// Проценты не заполняются, если среднее равно нулю.
+type TripDeviation struct {
+	Net                    float64            `json:"net"`
+	NetPercent             *float64           `json:"net_percent,omitempty"`
+	PerDay                 float64            `json:"per_day"`
+	PerDayPercent          *float64           `json:"per_day_percent,omitempty"`
+	PerPersonPerDay        float64            `json:"per_person_per_day"`
+	PerPersonPerDayPercent *float64           `json:"per_person_per_day_percent,omitempty"`
+	Days                   float64            `json:"days"`
+	CategoryShares         map[string]float64 `json:"category_shares"` // разница долей по категориям
+}
+
+// TripMetrics — нормированные показатели одной поездки
+type TripMetrics struct {
+	TravelID        string                `json:"travel_id"`
+	Title           string                `json:"title"`
+	StartDate       string                `json:"start_date"`
+	EndDate         string                `json:"end_date"`
+	Days            int                   `json:"days"`
+	Participants    int                   `json:"participants"`
+	Total           AmountSummary         `json:"total"`
+	PerDay          float64               `json:"per_day"`
+	PerPerson       float64               `json:"per_person"`
+	PerPersonPerDay float64               `json:"per_person_per_day"`
+	Categories      []TripCategoryMetrics `json:"categories"`
+	VsAverage       TripDeviation         `json:"vs_average"`
+}
+
+// TripAverage — средние показатели по поездкам пользователя, в которых есть записи
+type TripAverage struct {
+	Trips           int                `json:"trips"`
+	Net             float64            `json:"net"`
+	Days            float64            `json:"days"`
+	PerDay          float64            `json:"per_day"`
+	PerPersonPerDay float64            `json:"per_person_per_day"`
+	CategoryShares  map[string]float64 `json:"category_shares"`
+}
+
+type TravelComparisonResponse struct {
+	Trips   []TripMetrics `json:"trips"`
+	Average TripAverage   `json:"average"`
+}
+
+// ForecastCategory — прогноз по категории: потрачено плюс дневная ставка × оставшиеся дни
+type ForecastCategory struct {
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Spent      float64 `json:"spent"`
+	DailyRate  float64 `json:"daily_rate"`
+	Projected  float64 `json:"projected"`
+}
+
+// ForecastResponse — прогноз итоговых чистых трат поездки
+type ForecastResponse struct {
+	TravelID       string  `json:"travel_id"`
+	Status         string  `json:"status"` // not_started | active | finished
+	Days           int     `json:"days"`
+	ElapsedDays    int     `json:"elapsed_days"`
+	RemainingDays  int     `json:"remaining_days"`
+	Spent          float64 `json:"spent"`
+	BurnRate       float64 `json:"burn_rate"` // средние траты за прошедший день
+	ProjectedTotal float64 `json:"projected_total"`
+	ProjectedLow   float64 `json:"projected_low"`
+	ProjectedHigh  float64 `json:"projected_high"`
+	Confidence     float64 `json:"confidence"` // уровень доверия интервала
+
+	// Бюджет и сравнение с ним — только если бюджет задан у поездки
+	Budget              *float64 `json:"budget,omitempty"`
+	BudgetLeft          *float64 `json:"budget_left,omitempty"`
+	ProjectedOverBudget *float64 `json:"projected_over_budget,omitempty"` // отрицательное — запас
+	WillExceedBudget    bool     `json:"will_exceed_budget"`
+	SafeToSpendPerDay   *float64 `json:"safe_to_spend_per_day,omitempty"`
+	// Без бюджета: сколько обычно уходит на поездку такой длительности
+	// (средние траты в день по прошлым поездкам × дни)
+	HistoryEstimate *float64 `json:"history_estimate,omitempty"`
+
+	Categories []ForecastCategory `json:"categories"`
+}
+
+type TopExpense struct {
+	ID         string  `json:"id"`
+	TravelID   string  `json:"travel_id"`
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Amount     float64 `json:"amount"`
+	Date       string  `json:"date"`
+	Comment    string  `json:"comment"`
+}
+
+// CategoryStatistics — размер расхода в категории
+type CategoryStatistics struct {
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Count      int     `json:"count"`
+	Total      float64 `json:"total"`
+	Mean       float64 `json:"mean"`
+	Median     float64 `json:"median"`
+	P90        float64 `json:"p90"`
+}
+
+type WeekdaySpending struct {
+	Weekday string  `json:"weekday"` // monday … sunday
+	Count   int     `json:"count"`
+	Total   float64 `json:"total"`
+}
+
+type HourSpending struct {
+	Hour  int     `json:"hour"` // 0–23
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
+
+type DaySpending struct {
+	Date  string  `json:"date"`
+	Count int     `json:"count"`
+	Total float64 `json:"total"`
+}
+
+// StatisticsResponse — описательная статистика расходов поездки или всех поездок пользователя
+type StatisticsResponse struct {
+	TravelID         string               `json:"travel_id,omitempty"` // пусто — по всем поездкам
+	Timezone         string               `json:"timezone"`
+	Expenses         int                  `json:"expenses"`
+	Total            float64              `json:"total"`
+	TopExpenses      []TopExpense         `json:"top_expenses"`
+	Categories       []CategoryStatistics `json:"categories"`
+	ByWeekday        []WeekdaySpending    `json:"by_weekday"` // с понедельника
+	ByHour           []HourSpending       `json:"by_hour"`    // только записи с указанным временем
+	TimedExpenses    int                  `json:"timed_expenses"`
+	MostExpensiveDay *DaySpending         `json:"most_expensive_day,omitempty"`
+	TrackedDays      int                  `json:"tracked_days"` // дни поездок, уже наступившие
+	ExpenseFreeDays  int                  `json:"expense_free_days"`
+}
+
+type ReviewCategory struct {
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Net        float64 `json:"net"`
+	Share      float64 `json:"share"` // доля от чистых трат периода, 0..1
+}
+
+type ReviewTrip struct {
+	TravelID string  `json:"travel_id"`
+	Title    string  `json:"title"`
+	Net      float64 `json:"net"` // чистые траты поездки за период
+}
+
+// ReviewResponse — итоги трат по всем поездкам за период
+type ReviewResponse struct {
+	From               string           `json:"from"`
+	To                 string           `json:"to"`
+	Total              AmountSummary    `json:"total"`
+	Trips              int              `json:"trips"`          // поездки, пересекающиеся с периодом
+	DaysTravelled      int              `json:"days_travelled"` // дни периода в поездках
+	Countries          []string         `json:"countries"`      // ISO 3166-1 alpha-2, если указаны у поездок
+	TopCategories      []ReviewCategory `json:"top_categories"`
+	MostExpensiveTrip  *ReviewTrip      `json:"most_expensive_trip,omitempty"`
+	LeastExpensiveTrip *ReviewTrip      `json:"least_expensive_trip,omitempty"`
+	ByMonth            []TimeBucket     `json:"by_month"`
+}
+
+// ChartRequest — параметры изображения графика
+type ChartRequest struct {
+	Format      string `form:"format"`      // svg (по умолчанию) | png
+	Width       int    `form:"width"`       // 200–2000, по умолчанию 640
+	Height      int    `form:"height"`      // 150–2000, по умолчанию 400
+	Theme       string `form:"theme"`       // light (по умолчанию) | dark
+	Style       string `form:"style"`       // для categories: donut (по умолчанию) | pie
+	Granularity string `form:"granularity"` // для daily и cumulative: day | week | month
+	Tz          string `form:"tz"`
 }
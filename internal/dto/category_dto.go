@@ -1,11 +1,36 @@
 package dto
 
 type CreateCategoryRequest struct {
-	Name string `json:"name" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+	ParentID *uint  `json:"parent_id"` // встроенная или собственная категория
+	Icon     string `json:"icon"`      // ключ иконки на фронтенде
+	Color    string `json:"color"`     // #rrggbb
+}
+
+type UpdateCategoryRequest struct {
+	Name     string  `json:"name" binding:"required"`
+	ParentID *uint   `json:"parent_id"` // не задан — родитель не меняется, 0 — сделать корневой
+	Icon     *string `json:"icon"`      // не задан — не меняется, пустая строка — сбросить
+	Color    *string `json:"color"`     // не задан — не меняется, пустая строка — сбросить
+}
+
+// ReorderCategoriesRequest — новый порядок категорий; неперечисленные идут следом
+type ReorderCategoriesRequest struct {
+	CategoryIDs []uint `json:"category_ids" binding:"required"`
+}
+
+type MergeCategoryRequest struct {
+	TargetID uint `json:"target_id" binding:"required"`
 }
 
 type CategoryResponse struct {
-	ID      string `json:"id"` // string для id
-	Name    string `json:"name"`
-	Builtin bool   `json:"builtin"`
+	ID        string `json:"id"` // string для id
+	Name      string `json:"name"`
+	Key       string `json:"key,omitempty"` // ключ встроенной категории
+	Builtin   bool   `json:"builtin"`
+	ParentID  string `json:"parent_id,omitempty"`
+	Icon      string `json:"icon,omitempty"`
+	Color     string `json:"color,omitempty"`
+	SortOrder int    `json:"sort_order"`
+	Archived  bool   `json:"archived"`
 }
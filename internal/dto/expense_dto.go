@@ -1,30 +1,51 @@
 package dto
 
 type CreateExpenseRequest struct {
-	TravelID uint    `json:"travel_id" binding:"required"`
-	Category string  `json:"category" binding:"required"`
-	Amount   float64 `json:"amount" binding:"required"`
-	Date     string  `json:"date" binding:"required"`
-	Comment  string  `json:"comment"`
+	TravelID   uint    `json:"travel_id" binding:"required"`
+	CategoryID *uint   `json:"category_id"`
+	Category   string  `json:"category"` // название; используется, если category_id не задан
+	Amount     float64 `json:"amount" binding:"required"`
+	Date       string  `json:"date" binding:"required"` // YYYY-MM-DD или RFC 3339 со временем
+	Comment    string  `json:"comment"`
+	Force      bool    `json:"force"` // создать расход, даже если найдены возможные дубли
+	// expense | refund | reimbursement | income; по умолчанию expense, а при отрицательной
+	// сумме — refund. Суточные и пробег начисляются по ставкам отдельными запросами.
+	Kind       string `json:"kind"`
+	RefundOfID *uint  `json:"refund_of_id"` // исходный расход для возврата
 }
 
 type GetUsersExpenseRequest struct {
-	Category string `form:"category"`
-	From     string `form:"from"` // формат YYYY-MM-DD
-	To       string `form:"to"`
+	CategoryID *uint  `form:"category_id"`
+	Category   string `form:"category"` // название; используется, если category_id не задан
+	From       string `form:"from"`     // формат YYYY-MM-DD
+	To         string `form:"to"`
 }
 
 type ExpenseResponse struct {
-	ID       string  `json:"id"`
-	Category string  `json:"category"`
-	Amount   float64 `json:"amount"`
-	Date     string  `json:"date"`
-	Comment  string  `json:"comment"`
+	ID         string  `json:"id"`
+	CategoryID string  `json:"category_id"`
+	Category   string  `json:"category"`
+	Amount     float64 `json:"amount"`
+	Date       string  `json:"date"`
+	DateTime   string  `json:"datetime,omitempty"` // RFC 3339, если у записи указано время
+	Comment    string  `json:"comment"`
+	Kind       string  `json:"kind"`
+	RefundOfID string  `json:"refund_of_id,omitempty"`
+	Distance   float64 `json:"distance_km,omitempty"` // для пробега
+
+	Violations []PolicyViolationResponse `json:"violations,omitempty"`
 }
 
 type UpdateExpenseRequest struct {
-	Category string  `json:"category"`
-	Date     string  `json:"date"` // формат YYYY-MM-DD
-	Amount   float64 `json:"amount"`
-	Comment  string  `json:"comment"`
+	CategoryID *uint   `json:"category_id"`
+	Category   string  `json:"category"` // название; без category_id и названия категория не меняется
+	Date       string  `json:"date"`     // YYYY-MM-DD или RFC 3339 со временем
+	Amount     float64 `json:"amount"`
+	Comment    string  `json:"comment"`
+	Kind       string  `json:"kind"` // как в CreateExpenseRequest; пустое значение оставляет вид без изменений
+	RefundOfID *uint   `json:"refund_of_id"`
+}
+
+type DuplicateGroupResponse struct {
+	Expenses []ExpenseResponse `json:"expenses"`
 }
@@ -0,0 +1,36 @@
+package dto
+
+type CreateExpenseReportRequest struct {
+	TravelID   uint   `json:"travel_id" binding:"required"`
+	Title      string `json:"title" binding:"required"`
+	ExpenseIDs []uint `json:"expense_ids"` // пусто → все возмещаемые расходы поездки
+}
+
+type ReportActionRequest struct {
+	Comment string `json:"comment"`
+}
+
+type ExpenseReportLineResponse struct {
+	ID              string          `json:"id"`
+	Expense         ExpenseResponse `json:"expense"`
+	Comment         string          `json:"comment"`
+	ApproverComment string          `json:"approver_comment"`
+}
+
+type ReportTransitionResponse struct {
+	From    string `json:"from"`
+	To      string `json:"to"`
+	ActorID string `json:"actor_id"`
+	Comment string `json:"comment"`
+	At      string `json:"at"`
+}
+
+type ExpenseReportResponse struct {
+	ID       string                      `json:"id"`
+	TravelID string                      `json:"travel_id"`
+	Title    string                      `json:"title"`
+	Status   string                      `json:"status"`
+	Total    float64                     `json:"total"`
+	Lines    []ExpenseReportLineResponse `json:"lines"`
+	History  []ReportTransitionResponse  `json:"history,omitempty"`
+}
@@ -0,0 +1,17 @@
+package dto
+
+type InsightResponse struct {
+	ID         string  `json:"id"`
+	TravelID   string  `json:"travel_id"`
+	Type       string  `json:"type"`     // outlier_expense | unusual_day | category_shift
+	Severity   string  `json:"severity"` // info | warning | critical
+	ExpenseID  string  `json:"expense_id,omitempty"`
+	CategoryID string  `json:"category_id,omitempty"`
+	Category   string  `json:"category,omitempty"`
+	Date       string  `json:"date,omitempty"` // день для unusual_day
+	Value      float64 `json:"value"`          // сумма или доля категории (0..1)
+	Baseline   float64 `json:"baseline"`       // обычное значение
+	Ratio      float64 `json:"ratio"`          // во сколько раз выше обычного
+	Message    string  `json:"message"`
+	CreatedAt  string  `json:"created_at"`
+}
@@ -0,0 +1,38 @@
+package dto
+
+type CreatePolicyRequest struct {
+	Name string `json:"name" binding:"required"`
+	// max_per_expense | max_per_day | forbidden_category
+	RuleType     string  `json:"rule_type" binding:"required"`
+	TravelID     *uint   `json:"travel_id"`   // пусто → для всех поездок
+	CategoryID   *uint   `json:"category_id"` // пусто → любая категория
+	Limit        float64 `json:"limit"`
+	RequiresNote bool    `json:"requires_note"` // превышение допустимо при наличии комментария
+	Severity     string  `json:"severity"`      // info | warning | critical, по умолчанию warning
+}
+
+type PolicyResponse struct {
+	ID           string  `json:"id"`
+	Name         string  `json:"name"`
+	RuleType     string  `json:"rule_type"`
+	TravelID     string  `json:"travel_id,omitempty"`
+	CategoryID   string  `json:"category_id,omitempty"`
+	Limit        float64 `json:"limit"`
+	RequiresNote bool    `json:"requires_note"`
+	Severity     string  `json:"severity"`
+}
+
+type PolicyViolationResponse struct {
+	ExpenseID string `json:"expense_id"`
+	PolicyID  string `json:"policy_id"`
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+}
+
+type ComplianceResponse struct {
+	Compliant         bool                      `json:"compliant"`
+	ExpensesChecked   int                       `json:"expenses_checked"`
+	ViolatingExpenses int                       `json:"violating_expenses"`
+	BySeverity        map[string]int            `json:"by_severity"`
+	Violations        []PolicyViolationResponse `json:"violations"`
+}
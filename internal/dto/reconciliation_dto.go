@@ -0,0 +1,33 @@
+package dto
+
+type BankTransactionRequest struct {
+	// Положительная — списание, отрицательная — поступление
+	Amount      float64 `json:"amount" binding:"required"`
+	Date        string  `json:"date" binding:"required"` // формат YYYY-MM-DD
+	Description string  `json:"description"`
+}
+
+type ImportTransactionsRequest struct {
+	Transactions []BankTransactionRequest `json:"transactions" binding:"required,dive"`
+}
+
+type BankTransactionResponse struct {
+	ID          string  `json:"id"`
+	Amount      float64 `json:"amount"`
+	Date        string  `json:"date"`
+	Description string  `json:"description"`
+	ExpenseID   string  `json:"expense_id,omitempty"`
+}
+
+type ReconciliationMatchResponse struct {
+	ID          string                  `json:"id"`
+	Score       float64                 `json:"score"`
+	Status      string                  `json:"status"`
+	Transaction BankTransactionResponse `json:"transaction"`
+	Expense     ExpenseResponse         `json:"expense"`
+}
+
+type UnreconciledResponse struct {
+	Transactions []BankTransactionResponse `json:"transactions"` // операции без расхода
+	Expenses     []ExpenseResponse         `json:"expenses"`     // расходы без операции
+}
@@ -4,4 +4,15 @@ type CreateTravelRequest struct {
 	Title     string `json:"title"`
 	StartDate string `json:"start_date"` // формат YYYY-MM-DD
 	EndDate   string `json:"end_date"`
+	// Число путешественников; по умолчанию 1
+	Participants int `json:"participants" binding:"omitempty,min=1"`
+	// Бюджет на чистые траты; не задан — без бюджета
+	Budget *float64 `json:"budget" binding:"omitempty,min=0"`
+	// Страна назначения, ISO 3166-1 alpha-2; необязательно
+	Country string `json:"country"`
+}
+
+// SetBudgetRequest — новый бюджет поездки; null снимает бюджет
+type SetBudgetRequest struct {
+	Budget *float64 `json:"budget" binding:"omitempty,min=0"`
 }
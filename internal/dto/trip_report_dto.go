@@ -0,0 +1,13 @@
+package dto
+
+// TripReportJobResponse — состояние фонового построения PDF-отчёта по поездке
+type TripReportJobResponse struct {
+	ID          string `json:"id"`
+	TravelID    string `json:"travel_id"`
+	Status      string `json:"status"` // pending | running | done | failed
+	Error       string `json:"error,omitempty"`
+	StatusURL   string `json:"status_url"`
+	DownloadURL string `json:"download_url,omitempty"` // когда status = done
+	CreatedAt   string `json:"created_at"`
+	FinishedAt  string `json:"finished_at,omitempty"`
+}
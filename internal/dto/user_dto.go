@@ -1,15 +1,94 @@
 package dto
 
+import "wanderwallet/internal/models"
+
 type UserRequest struct {
 	Login    string `json:"login" binding:"required,min=3,max=50"`
 	Password string `json:"password" binding:"required,min=6"`
 }
 
+// Device — откуда выполнен вход; сохраняется в сессии, чтобы пользователь узнал её в списке
+type Device struct {
+	UserAgent string
+	IP        string
+}
+
+// Tokens — короткоживущий токен доступа и одноразовый токен обновления
+type Tokens struct {
+	Token        string
+	RefreshToken string
+}
+
 type RegisterResponse struct {
-	ID    uint   `json:"id"`
-	Login string `json:"login"`
+	User *models.User
+	Tokens
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
+	User *models.User
+	Tokens
+}
+
+// TokenResponse — пара токенов после входа или обновления. Те же токены
+// устанавливаются в куки Authorization и RefreshToken.
+type TokenResponse struct {
+	Message      string `json:"message"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+	// Срок жизни токена доступа в секундах
+	ExpiresIn int `json:"expires_in"`
+}
+
+// RefreshRequest — токен обновления; если не передан, берётся из куки RefreshToken
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LocaleRequest — предпочитаемый язык ("ru", "en"); пустая строка — по Accept-Language
+type LocaleRequest struct {
+	Locale string `json:"locale"`
+}
+
+type LocaleResponse struct {
+	Locale string `json:"locale"`
+}
+
+// SessionResponse — вход с устройства
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+	CreatedAt  string `json:"created_at"`
+	LastSeenAt string `json:"last_seen_at"`
+	// Сессия, с которой сделан запрос
+	Current bool `json:"current"`
+}
+
+type RevokeSessionsResponse struct {
+	Revoked int64 `json:"revoked"`
+}
+
+// EmailRequest — адрес для сброса пароля; пустая строка удаляет его
+type EmailRequest struct {
+	Email string `json:"email" binding:"omitempty,email,max=254"`
+}
+
+type EmailResponse struct {
+	Email string `json:"email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+// ForgotPasswordRequest — логин или адрес почты
+type ForgotPasswordRequest struct {
+	Login string `json:"login" binding:"required"`
+}
+
+// ResetPasswordRequest — код из письма и новый пароль
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
 }
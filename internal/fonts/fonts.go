@@ -0,0 +1,265 @@
+// Package fonts содержит встроенный шрифт Open Sans (обычный и полужирный, латиница
+// и кириллица; лицензия Apache 2.0, см. LICENSE.txt) и разбор таблиц TrueType, нужных
+// для вывода текста: кодировки символов, метрик и контуров глифов.
+package fonts
+
+import (
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+var (
+	//go:embed OpenSans-Regular.ttf
+	regularData []byte
+	//go:embed OpenSans-Bold.ttf
+	boldData []byte
+)
+
+var (
+	Regular = mustParse(regularData)
+	Bold    = mustParse(boldData)
+)
+
+// Face возвращает встроенный шрифт нужного начертания
+func Face(bold bool) *Font {
+	if bold {
+		return Bold
+	}
+	return Regular
+}
+
+var ErrInvalidFont = errors.New("invalid TrueType font")
+
+// Font — разобранный шрифт TrueType. Размеры в методах без кегля — в единицах шрифта.
+type Font struct {
+	data   []byte
+	tables map[string][]byte
+
+	UnitsPerEm int
+	Ascent     int // над базовой линией, положительное
+	Descent    int // под базовой линией, отрицательное
+	CapHeight  int
+	BBox       [4]int // xMin, yMin, xMax, yMax
+
+	numGlyphs int
+	longLoca  bool
+	advances  []int
+	glyphs    map[rune]uint16
+	fallback  uint16 // глиф «?» для символов, которых в шрифте нет
+}
+
+func mustParse(data []byte) *Font {
+	f, err := Parse(data)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// Parse разбирает шрифт TrueType (sfnt с контурами в таблице glyf)
+func Parse(data []byte) (*Font, error) {
+	if len(data) < 12 || binary.BigEndian.Uint32(data) != 0x00010000 {
+		return nil, ErrInvalidFont
+	}
+	f := &Font{data: data, tables: make(map[string][]byte)}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	if len(data) < 12+16*numTables {
+		return nil, ErrInvalidFont
+	}
+	for i := 0; i < numTables; i++ {
+		record := data[12+16*i:]
+		offset, length := binary.BigEndian.Uint32(record[8:]), binary.BigEndian.Uint32(record[12:])
+		if uint64(offset)+uint64(length) > uint64(len(data)) {
+			return nil, ErrInvalidFont
+		}
+		f.tables[string(record[:4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "cmap", "loca", "glyf"} {
+		if f.tables[tag] == nil {
+			return nil, fmt.Errorf("%w: no %s table", ErrInvalidFont, tag)
+		}
+	}
+
+	head, hhea := f.tables["head"], f.tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 || len(f.tables["maxp"]) < 6 {
+		return nil, ErrInvalidFont
+	}
+	f.UnitsPerEm = int(u16(head, 18))
+	for i := range f.BBox {
+		f.BBox[i] = int(i16(head, 36+2*i))
+	}
+	f.longLoca = i16(head, 50) == 1
+	f.Ascent, f.Descent = int(i16(hhea, 4)), int(i16(hhea, 6))
+	f.CapHeight = f.Ascent * 7 / 10
+	if os2 := f.tables["OS/2"]; len(os2) >= 90 && u16(os2, 0) >= 2 {
+		f.CapHeight = int(i16(os2, 88))
+	}
+	f.numGlyphs = int(u16(f.tables["maxp"], 4))
+
+	if err := f.parseAdvances(int(u16(hhea, 34))); err != nil {
+		return nil, err
+	}
+	if err := f.parseCmap(); err != nil {
+		return nil, err
+	}
+	f.fallback = f.glyphs['?']
+	return f, nil
+}
+
+func (f *Font) parseAdvances(numMetrics int) error {
+	hmtx := f.tables["hmtx"]
+	if numMetrics == 0 || numMetrics > f.numGlyphs || len(hmtx) < 4*numMetrics {
+		return ErrInvalidFont
+	}
+	f.advances = make([]int, f.numGlyphs)
+	for g := range f.advances {
+		if g < numMetrics {
+			f.advances[g] = int(u16(hmtx, 4*g))
+		} else {
+			f.advances[g] = f.advances[numMetrics-1]
+		}
+	}
+	return nil
+}
+
+// parseCmap читает юникодную таблицу кодировки: формат 12 (вся кодовая область)
+// или формат 4 (только BMP)
+func (f *Font) parseCmap() error {
+	cmap := f.tables["cmap"]
+	if len(cmap) < 4 {
+		return ErrInvalidFont
+	}
+	var best []byte
+	bestFormat := uint16(0)
+	for i := 0; i < int(u16(cmap, 2)); i++ {
+		if len(cmap) < 4+8*(i+1) {
+			return ErrInvalidFont
+		}
+		platform, encoding := u16(cmap, 4+8*i), u16(cmap, 6+8*i)
+		offset := int(binary.BigEndian.Uint32(cmap[8+8*i:]))
+		unicode := platform == 0 || (platform == 3 && (encoding == 1 || encoding == 10))
+		if !unicode || offset+2 > len(cmap) {
+			continue
+		}
+		if format := u16(cmap, offset); (format == 4 || format == 12) && format > bestFormat {
+			best, bestFormat = cmap[offset:], format
+		}
+	}
+
+	f.glyphs = make(map[rune]uint16)
+	switch bestFormat {
+	case 4:
+		return f.parseCmap4(best)
+	case 12:
+		return f.parseCmap12(best)
+	}
+	return fmt.Errorf("%w: no unicode cmap", ErrInvalidFont)
+}
+
+func (f *Font) parseCmap4(t []byte) error {
+	if len(t) < 14 {
+		return ErrInvalidFont
+	}
+	segments := int(u16(t, 6)) / 2
+	ends, starts, deltas, ranges := 14, 16+2*segments, 16+4*segments, 16+6*segments
+	if len(t) < ranges+2*segments {
+		return ErrInvalidFont
+	}
+	for i := 0; i < segments; i++ {
+		start, end := int(u16(t, starts+2*i)), int(u16(t, ends+2*i))
+		delta, rangeOffset := u16(t, deltas+2*i), int(u16(t, ranges+2*i))
+		for c := start; c <= end && c != 0xFFFF; c++ {
+			g := uint16(c) + delta
+			if rangeOffset != 0 {
+				at := ranges + 2*i + rangeOffset + 2*(c-start)
+				if at+2 > len(t) {
+					return ErrInvalidFont
+				}
+				if g = u16(t, at); g != 0 {
+					g += delta
+				}
+			}
+			if g != 0 && int(g) < f.numGlyphs {
+				f.glyphs[rune(c)] = g
+			}
+		}
+	}
+	return nil
+}
+
+func (f *Font) parseCmap12(t []byte) error {
+	if len(t) < 16 {
+		return ErrInvalidFont
+	}
+	groups := int(binary.BigEndian.Uint32(t[12:]))
+	if len(t) < 16+12*groups {
+		return ErrInvalidFont
+	}
+	for i := 0; i < groups; i++ {
+		group := t[16+12*i:]
+		start, end := binary.BigEndian.Uint32(group), binary.BigEndian.Uint32(group[4:])
+		glyph := binary.BigEndian.Uint32(group[8:])
+		for c := start; c <= end && c <= 0x10FFFF; c++ {
+			if g := glyph + c - start; g < uint32(f.numGlyphs) {
+				f.glyphs[rune(c)] = uint16(g)
+			}
+		}
+	}
+	return nil
+}
+
+// Data — исходный файл шрифта
+func (f *Font) Data() []byte {
+	return f.data
+}
+
+// Table — содержимое таблицы sfnt; nil, если таблицы нет
+func (f *Font) Table(tag string) []byte {
+	return f.tables[tag]
+}
+
+// NumGlyphs — число глифов в шрифте
+func (f *Font) NumGlyphs() int {
+	return f.numGlyphs
+}
+
+// Index возвращает глиф символа; символы, которых нет в шрифте, выводятся знаком «?»
+func (f *Font) Index(r rune) uint16 {
+	if g, ok := f.glyphs[r]; ok {
+		return g
+	}
+	return f.fallback
+}
+
+// Has — есть ли в шрифте глиф для символа
+func (f *Font) Has(r rune) bool {
+	_, ok := f.glyphs[r]
+	return ok
+}
+
+// Advance — ширина глифа
+func (f *Font) Advance(g uint16) int {
+	if int(g) >= len(f.advances) {
+		return 0
+	}
+	return f.advances[g]
+}
+
+// TextWidth — ширина строки при кегле size, в тех же единицах, что и size
+func (f *Font) TextWidth(text string, size float64) float64 {
+	total := 0
+	for _, r := range text {
+		total += f.Advance(f.Index(r))
+	}
+	return float64(total) * size / float64(f.UnitsPerEm)
+}
+
+func u16(b []byte, offset int) uint16 {
+	return binary.BigEndian.Uint16(b[offset:])
+}
+
+func i16(b []byte, offset int) int16 {
+	return int16(binary.BigEndian.Uint16(b[offset:]))
+}
@@ -0,0 +1,83 @@
+package fonts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEmbeddedFonts(t *testing.T) {
+	for _, f := range []*Font{Regular, Bold} {
+		assert.Equal(t, 2048, f.UnitsPerEm)
+		assert.Greater(t, f.Ascent, f.CapHeight)
+		assert.Less(t, f.Descent, 0)
+		for _, r := range "AZaz09 АЯаяЁё«»—–№€×•…°’“”" {
+			assert.True(t, f.Has(r), "%q", r)
+		}
+		assert.False(t, f.Has('☃'))
+		assert.Equal(t, f.Index('?'), f.Index('☃'))
+	}
+	assert.Greater(t, Bold.TextWidth("Итого", 12), Regular.TextWidth("Итого", 12))
+	assert.Equal(t, 0.0, Regular.TextWidth("", 12))
+}
+
+func TestOutline(t *testing.T) {
+	// «о» — два контура: внешний и внутренний
+	contours, err := Regular.Outline(Regular.Index('о'))
+	assert.NoError(t, err)
+	assert.Len(t, contours, 2)
+
+	// «Ё» собрана из «Е» и диерезиса
+	components, err := Regular.Components(Regular.Index('Ё'))
+	assert.NoError(t, err)
+	assert.Len(t, components, 2)
+	contours, err = Regular.Outline(Regular.Index('Ё'))
+	assert.NoError(t, err)
+	assert.Len(t, contours, 3)
+	for _, c := range contours {
+		for _, p := range c {
+			assert.LessOrEqual(t, p.Y, float64(Regular.Ascent))
+		}
+	}
+
+	contours, err = Regular.Outline(Regular.Index(' '))
+	assert.NoError(t, err)
+	assert.Empty(t, contours)
+
+	for g := 0; g < Regular.NumGlyphs(); g++ {
+		_, err := Regular.Outline(uint16(g))
+		assert.NoError(t, err)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	_, err := Parse([]byte("not a font"))
+	assert.ErrorIs(t, err, ErrInvalidFont)
+	_, err = Parse(Regular.Data()[:64])
+	assert.ErrorIs(t, err, ErrInvalidFont)
+}
+
+func TestSubset(t *testing.T) {
+	keep := Regular.Index('Ё')
+	data, err := Regular.Subset([]uint16{keep, Regular.Index('я')})
+	assert.NoError(t, err)
+	assert.Less(t, len(data), len(Regular.Data())/4)
+
+	sub, err := Parse(data)
+	assert.NoError(t, err)
+	assert.Equal(t, Regular.NumGlyphs(), sub.NumGlyphs())
+	assert.Equal(t, Regular.Index('я'), sub.Index('я'))
+
+	// Номера глифов сохраняются, у «Ё» остаются компоненты, остальные глифы пусты
+	components, _ := Regular.Components(keep)
+	for _, g := range append(components, keep, Regular.Index('я')) {
+		want, _ := Regular.Outline(g)
+		got, err := sub.Outline(g)
+		assert.NoError(t, err)
+		assert.Equal(t, want, got)
+	}
+	empty, err := sub.GlyphData(Regular.Index('Ж'))
+	assert.NoError(t, err)
+	assert.Empty(t, empty)
+	assert.Equal(t, uint32(0xB1B0AFBA), checksum(data))
+}
@@ -0,0 +1,250 @@
+package fonts
+
+import (
+	"encoding/binary"
+)
+
+// OutlinePoint — точка контура глифа в единицах шрифта, ось Y направлена вверх
+type OutlinePoint struct {
+	X, Y    float64
+	OnCurve bool
+}
+
+// Contour — замкнутый контур из отрезков и квадратичных кривых Безье: точка вне кривой
+// задаёт управляющую точку, а между двумя такими точками подразумевается точка на кривой
+type Contour []OutlinePoint
+
+// Флаги точек простого глифа
+const (
+	flagOnCurve  = 0x01
+	flagXShort   = 0x02
+	flagYShort   = 0x04
+	flagRepeat   = 0x08
+	flagXSame    = 0x10 // для короткой координаты — знак «плюс»
+	flagYSame    = 0x20
+	maxRecursion = 8 // глубина вложенности составных глифов
+)
+
+// Флаги компонентов составного глифа
+const (
+	componentWords    = 0x0001
+	componentXY       = 0x0002
+	componentScale    = 0x0008
+	componentMore     = 0x0020
+	componentXYScale  = 0x0040
+	componentTwoByTwo = 0x0080
+)
+
+// GlyphData — описание глифа в таблице glyf; пустое у глифов без контуров, например пробела
+func (f *Font) GlyphData(g uint16) ([]byte, error) {
+	loca, glyf := f.tables["loca"], f.tables["glyf"]
+	if int(g) >= f.numGlyphs {
+		return nil, ErrInvalidFont
+	}
+	var start, end int
+	if f.longLoca {
+		if len(loca) < 4*int(g)+8 {
+			return nil, ErrInvalidFont
+		}
+		start, end = int(binary.BigEndian.Uint32(loca[4*g:])), int(binary.BigEndian.Uint32(loca[4*g+4:]))
+	} else {
+		if len(loca) < 2*int(g)+4 {
+			return nil, ErrInvalidFont
+		}
+		start, end = 2*int(u16(loca, 2*int(g))), 2*int(u16(loca, 2*int(g)+2))
+	}
+	if start > end || end > len(glyf) {
+		return nil, ErrInvalidFont
+	}
+	return glyf[start:end], nil
+}
+
+// Components возвращает глифы, из которых собран составной глиф; у простого глифа их нет
+func (f *Font) Components(g uint16) ([]uint16, error) {
+	data, err := f.GlyphData(g)
+	if err != nil || len(data) < 10 || i16(data, 0) >= 0 {
+		return nil, err
+	}
+	var components []uint16
+	err = walkComponents(data[10:], func(glyph uint16, _ [6]float64) error {
+		components = append(components, glyph)
+		return nil
+	})
+	return components, err
+}
+
+// Outline возвращает контуры глифа; составные глифы раскрываются
+func (f *Font) Outline(g uint16) ([]Contour, error) {
+	return f.outline(g, 0)
+}
+
+func (f *Font) outline(g uint16, depth int) ([]Contour, error) {
+	if depth > maxRecursion {
+		return nil, ErrInvalidFont
+	}
+	data, err := f.GlyphData(g)
+	if err != nil || len(data) == 0 {
+		return nil, err
+	}
+	if len(data) < 10 {
+		return nil, ErrInvalidFont
+	}
+	if n := int(i16(data, 0)); n >= 0 {
+		return simpleOutline(data[10:], n)
+	}
+
+	var contours []Contour
+	err = walkComponents(data[10:], func(glyph uint16, m [6]float64) error {
+		parts, err := f.outline(glyph, depth+1)
+		if err != nil {
+			return err
+		}
+		for _, c := range parts {
+			for i, p := range c {
+				c[i].X, c[i].Y = m[0]*p.X+m[2]*p.Y+m[4], m[1]*p.X+m[3]*p.Y+m[5]
+			}
+			contours = append(contours, c)
+		}
+		return nil
+	})
+	return contours, err
+}
+
+func simpleOutline(data []byte, numContours int) ([]Contour, error) {
+	if len(data) < 2*numContours+2 {
+		return nil, ErrInvalidFont
+	}
+	ends := make([]int, numContours)
+	for i := range ends {
+		ends[i] = int(u16(data, 2*i))
+	}
+	numPoints := 0
+	if numContours > 0 {
+		numPoints = ends[numContours-1] + 1
+	}
+	pos := 2*numContours + 2 + int(u16(data, 2*numContours))
+
+	flags := make([]byte, 0, numPoints)
+	for len(flags) < numPoints {
+		if pos >= len(data) {
+			return nil, ErrInvalidFont
+		}
+		flag := data[pos]
+		pos++
+		flags = append(flags, flag)
+		if flag&flagRepeat != 0 {
+			if pos >= len(data) {
+				return nil, ErrInvalidFont
+			}
+			for n := data[pos]; n > 0 && len(flags) < numPoints; n-- {
+				flags = append(flags, flag)
+			}
+			pos++
+		}
+	}
+
+	points := make([]OutlinePoint, numPoints)
+	readCoords := func(short, same byte, set func(i int, v float64)) error {
+		v := 0
+		for i, flag := range flags {
+			switch {
+			case flag&short != 0:
+				if pos >= len(data) {
+					return ErrInvalidFont
+				}
+				d := int(data[pos])
+				pos++
+				if flag&same == 0 {
+					d = -d
+				}
+				v += d
+			case flag&same == 0:
+				if pos+2 > len(data) {
+					return ErrInvalidFont
+				}
+				v += int(i16(data, pos))
+				pos += 2
+			}
+			set(i, float64(v))
+		}
+		return nil
+	}
+	if err := readCoords(flagXShort, flagXSame, func(i int, v float64) { points[i].X = v }); err != nil {
+		return nil, err
+	}
+	if err := readCoords(flagYShort, flagYSame, func(i int, v float64) { points[i].Y = v }); err != nil {
+		return nil, err
+	}
+
+	contours := make([]Contour, 0, numContours)
+	start := 0
+	for _, end := range ends {
+		if end < start || end >= numPoints {
+			return nil, ErrInvalidFont
+		}
+		c := make(Contour, 0, end-start+1)
+		for i := start; i <= end; i++ {
+			points[i].OnCurve = flags[i]&flagOnCurve != 0
+			c = append(c, points[i])
+		}
+		contours = append(contours, c)
+		start = end + 1
+	}
+	return contours, nil
+}
+
+// walkComponents перебирает компоненты составного глифа с матрицами преобразования
+// [a b c d dx dy]: x' = a·x + c·y + dx, y' = b·x + d·y + dy
+func walkComponents(data []byte, visit func(glyph uint16, m [6]float64) error) error {
+	pos := 0
+	for {
+		if pos+4 > len(data) {
+			return ErrInvalidFont
+		}
+		flags, glyph := u16(data, pos), u16(data, pos+2)
+		pos += 4
+
+		m := [6]float64{1, 0, 0, 1, 0, 0}
+		if flags&componentWords != 0 {
+			if pos+4 > len(data) {
+				return ErrInvalidFont
+			}
+			m[4], m[5] = float64(i16(data, pos)), float64(i16(data, pos+2))
+			pos += 4
+		} else {
+			if pos+2 > len(data) {
+				return ErrInvalidFont
+			}
+			m[4], m[5] = float64(int8(data[pos])), float64(int8(data[pos+1]))
+			pos += 2
+		}
+		// аргументы-номера точек для совмещения компонентов не поддерживаются: сдвига нет
+		if flags&componentXY == 0 {
+			m[4], m[5] = 0, 0
+		}
+
+		f2dot14 := func() float64 {
+			v := float64(i16(data, pos)) / 16384
+			pos += 2
+			return v
+		}
+		switch {
+		case flags&componentScale != 0 && pos+2 <= len(data):
+			m[0] = f2dot14()
+			m[3] = m[0]
+		case flags&componentXYScale != 0 && pos+4 <= len(data):
+			m[0], m[3] = f2dot14(), f2dot14()
+		case flags&componentTwoByTwo != 0 && pos+8 <= len(data):
+			m[0], m[1], m[2], m[3] = f2dot14(), f2dot14(), f2dot14(), f2dot14()
+		case flags&(componentScale|componentXYScale|componentTwoByTwo) != 0:
+			return ErrInvalidFont
+		}
+
+		if err := visit(glyph, m); err != nil {
+			return err
+		}
+		if flags&componentMore == 0 {
+			return nil
+		}
+	}
+}
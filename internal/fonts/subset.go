@@ -0,0 +1,115 @@
+package fonts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+)
+
+// Таблицы, которые остаются в подмножестве. Для PDF хватило бы метрик, контуров и хинтинга,
+// но с cmap, OS/2, name и post подмножество остаётся самостоятельным шрифтом.
+var subsetTables = []string{"OS/2", "cmap", "cvt ", "fpgm", "glyf", "head", "hhea", "hmtx", "loca", "maxp", "name", "post", "prep"}
+
+// Subset возвращает шрифт, в котором описания есть только у глифов glyphs, их компонентов
+// и .notdef. Номера глифов сохраняются, поэтому текст, закодированный номерами глифов
+// исходного шрифта, выводится и подмножеством.
+func (f *Font) Subset(glyphs []uint16) ([]byte, error) {
+	keep := map[uint16]bool{0: true}
+	queue := append([]uint16(nil), glyphs...)
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		if keep[g] {
+			continue
+		}
+		keep[g] = true
+		components, err := f.Components(g)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, components...)
+	}
+
+	var glyf bytes.Buffer
+	loca := make([]byte, 4*(f.numGlyphs+1))
+	for g := 0; g < f.numGlyphs; g++ {
+		binary.BigEndian.PutUint32(loca[4*g:], uint32(glyf.Len()))
+		if !keep[uint16(g)] {
+			continue
+		}
+		data, err := f.GlyphData(uint16(g))
+		if err != nil {
+			return nil, err
+		}
+		glyf.Write(data)
+		for glyf.Len()%4 != 0 {
+			glyf.WriteByte(0)
+		}
+	}
+	binary.BigEndian.PutUint32(loca[4*f.numGlyphs:], uint32(glyf.Len()))
+
+	head := append([]byte(nil), f.tables["head"]...)
+	binary.BigEndian.PutUint32(head[8:], 0) // checkSumAdjustment пересчитывается ниже
+	binary.BigEndian.PutUint16(head[50:], 1)
+
+	tables := map[string][]byte{"glyf": glyf.Bytes(), "loca": loca, "head": head}
+	for _, tag := range subsetTables {
+		if tables[tag] == nil && f.tables[tag] != nil {
+			tables[tag] = f.tables[tag]
+		}
+	}
+	return buildSfnt(tables), nil
+}
+
+// buildSfnt собирает файл TrueType из таблиц с выравниванием по 4 байта и контрольными суммами
+func buildSfnt(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	searchRange, entrySelector := 1, 0
+	for searchRange*2 <= len(tags) {
+		searchRange *= 2
+		entrySelector++
+	}
+	header := make([]byte, 12+16*len(tags))
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(len(tags)))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange*16))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16((len(tags)-searchRange)*16))
+
+	out := bytes.NewBuffer(header)
+	headOffset := 0
+	for i, tag := range tags {
+		data := tables[tag]
+		record := header[12+16*i:]
+		copy(record, tag)
+		binary.BigEndian.PutUint32(record[4:], checksum(data))
+		binary.BigEndian.PutUint32(record[8:], uint32(out.Len()))
+		binary.BigEndian.PutUint32(record[12:], uint32(len(data)))
+		if tag == "head" {
+			headOffset = out.Len()
+		}
+		out.Write(data)
+		for out.Len()%4 != 0 {
+			out.WriteByte(0)
+		}
+	}
+	font := out.Bytes()
+	copy(font, header)
+	binary.BigEndian.PutUint32(font[headOffset+8:], 0xB1B0AFBA-checksum(font))
+	return font
+}
+
+func checksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
@@ -0,0 +1,55 @@
+package i18n
+
+import "strings"
+
+// BuiltinCategory — встроенная категория с языконезависимым ключом
+type BuiltinCategory struct {
+	Key   string
+	Names map[Locale]string
+}
+
+// Builtin — встроенные категории в порядке заведения
+var Builtin = []BuiltinCategory{
+	{Key: "transport", Names: map[Locale]string{RU: "Транспорт", EN: "Transport"}},
+	{Key: "food", Names: map[Locale]string{RU: "Питание", EN: "Food"}},
+	{Key: "lodging", Names: map[Locale]string{RU: "Жильё", EN: "Lodging"}},
+	{Key: "groceries", Names: map[Locale]string{RU: "Продукты", EN: "Groceries"}},
+	{Key: "shopping", Names: map[Locale]string{RU: "Шоппинг", EN: "Shopping"}},
+	{Key: "sights", Names: map[Locale]string{RU: "Достопримечательности", EN: "Sights"}},
+	{Key: "entertainment", Names: map[Locale]string{RU: "Развлечения", EN: "Entertainment"}},
+	{Key: "general", Names: map[Locale]string{RU: "Общие", EN: "General"}},
+	{Key: "fees", Names: map[Locale]string{RU: "Дополнительные сборы и платежи", EN: "Fees and charges"}},
+	{Key: "per_diem", Names: map[Locale]string{RU: "Суточные", EN: "Per diem"}},
+	{Key: "mileage", Names: map[Locale]string{RU: "Пробег", EN: "Mileage"}},
+}
+
+// CategoryName возвращает название встроенной категории на нужном языке.
+// Для пользовательских категорий (пустой key) и неизвестных ключей — fallback.
+func CategoryName(loc Locale, key string, fallback string) string {
+	for _, b := range Builtin {
+		if b.Key == key {
+			if name, ok := b.Names[loc]; ok {
+				return name
+			}
+			return b.Names[Default]
+		}
+	}
+	return fallback
+}
+
+// CategoryKey находит ключ встроенной категории по ключу или по названию
+// на любом из поддерживаемых языков без учёта регистра.
+func CategoryKey(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for _, b := range Builtin {
+		if strings.EqualFold(b.Key, name) {
+			return b.Key, true
+		}
+		for _, n := range b.Names {
+			if strings.EqualFold(n, name) {
+				return b.Key, true
+			}
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,100 @@
+// Package i18n переводит названия встроенных категорий и сообщения API.
+package i18n
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type Locale string
+
+const (
+	RU Locale = "ru"
+	EN Locale = "en"
+
+	// Default — язык, на котором хранятся названия встроенных категорий
+	Default = RU
+)
+
+// ContextKey — ключ gin.Context, под которым хранится язык, выбранный пользователем
+const ContextKey = "locale"
+
+// Parse приводит тег языка ("en-US", "ru") к поддерживаемой локали
+func Parse(tag string) (Locale, bool) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if i := strings.IndexAny(tag, "-_"); i >= 0 {
+		tag = tag[:i]
+	}
+	switch Locale(tag) {
+	case RU, EN:
+		return Locale(tag), true
+	}
+	return "", false
+}
+
+// Negotiate выбирает первую поддерживаемую локаль из заголовка Accept-Language
+// с учётом весов q. Если подходящей нет, возвращается Default.
+func Negotiate(acceptLanguage string) Locale {
+	best, bestQ := Default, -1.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		loc, ok := Parse(fields[0])
+		if !ok {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				q = parseQ(f[2:])
+			}
+		}
+		if q > bestQ {
+			best, bestQ = loc, q
+		}
+	}
+	return best
+}
+
+// FromContext возвращает язык ответа: сохранённый в профиле пользователя,
+// иначе из заголовка Accept-Language.
+func FromContext(c *gin.Context) Locale {
+	if v, ok := c.Get(ContextKey); ok {
+		if loc, ok := Parse(v.(string)); ok {
+			return loc
+		}
+	}
+	return Negotiate(c.GetHeader("Accept-Language"))
+}
+
+// T переводит сообщение API. Ключом служит английский текст; для неизвестных
+// сообщений возвращается исходный текст.
+func T(loc Locale, message string) string {
+	if loc == EN {
+		return message
+	}
+	if translated, ok := messages[message][loc]; ok {
+		return translated
+	}
+	return message
+}
+
+func parseQ(s string) float64 {
+	q := 0.0
+	frac := 0.0
+	for i, r := range s {
+		switch {
+		case r == '.':
+			frac = 0.1
+		case r >= '0' && r <= '9' && frac == 0 && i == 0:
+			q = float64(r - '0')
+		case r >= '0' && r <= '9' && frac > 0:
+			q += float64(r-'0') * frac
+			frac /= 10
+		default:
+			return 0
+		}
+	}
+	return q
+}
@@ -0,0 +1,36 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate(t *testing.T) {
+	assert.Equal(t, RU, Negotiate(""))
+	assert.Equal(t, EN, Negotiate("en-US,en;q=0.9"))
+	assert.Equal(t, RU, Negotiate("de-DE, ru;q=0.8, en;q=0.5"))
+	assert.Equal(t, EN, Negotiate("ru;q=0.3, en-GB;q=0.7"))
+	assert.Equal(t, RU, Negotiate("fr, de"))
+}
+
+func TestT(t *testing.T) {
+	assert.Equal(t, "категория не найдена", T(RU, "category not found"))
+	assert.Equal(t, "category not found", T(EN, "category not found"))
+	assert.Equal(t, "something new", T(RU, "something new"))
+}
+
+func TestCategories(t *testing.T) {
+	assert.Equal(t, "Food", CategoryName(EN, "food", "Питание"))
+	assert.Equal(t, "Питание", CategoryName(RU, "food", "Питание"))
+	assert.Equal(t, "Кофе", CategoryName(EN, "", "Кофе"))
+
+	key, ok := CategoryKey("lodging")
+	assert.True(t, ok)
+	assert.Equal(t, "lodging", key)
+	key, ok = CategoryKey("жильё")
+	assert.True(t, ok)
+	assert.Equal(t, "lodging", key)
+	_, ok = CategoryKey("Кофе")
+	assert.False(t, ok)
+}
@@ -0,0 +1,153 @@
+package i18n
+
+// messages — переводы сообщений об ошибках API. Ключ — английский текст,
+// который отдают контроллеры и сервисы.
+var messages = map[string]map[Locale]string{
+	"Internal Server Error":                               {RU: "Внутренняя ошибка сервера"},
+	"internal server error":                               {RU: "внутренняя ошибка сервера"},
+	"invalid request":                                     {RU: "некорректный запрос"},
+	"invalid request format":                              {RU: "некорректный формат запроса"},
+	"invalid query params":                                {RU: "некорректные параметры запроса"},
+	"invalid login or password":                           {RU: "неверный логин или пароль"},
+	"login already exists":                                {RU: "логин уже занят"},
+	"email already in use":                                {RU: "адрес уже указан у другого пользователя"},
+	"invalid refresh token":                               {RU: "недействительный токен обновления"},
+	"invalid session ID":                                  {RU: "некорректный ID сессии"},
+	"session not found":                                   {RU: "сессия не найдена"},
+	"invalid locale":                                      {RU: "неподдерживаемый язык"},
+	"invalid date":                                        {RU: "некорректная дата"},
+	"invalid year":                                        {RU: "некорректный год"},
+	"invalid from date":                                   {RU: "некорректная дата начала периода"},
+	"invalid to date":                                     {RU: "некорректная дата конца периода"},
+	"invalid start date":                                  {RU: "некорректная дата начала"},
+	"invalid end date":                                    {RU: "некорректная дата окончания"},
+	"invalid travel":                                      {RU: "некорректное путешествие"},
+	"invalid travel ID":                                   {RU: "некорректный ID путешествия"},
+	"invalid travel_id":                                   {RU: "некорректный travel_id"},
+	"country must be a two-letter ISO code":               {RU: "страна должна быть двухбуквенным кодом ISO"},
+	"invalid budget":                                      {RU: "некорректный бюджет"},
+	"travel not found":                                    {RU: "путешествие не найдено"},
+	"invalid expense ID":                                  {RU: "некорректный ID расхода"},
+	"expense not found":                                   {RU: "расход не найден"},
+	"cannot edit another user's expense":                  {RU: "нельзя изменить чужой расход"},
+	"cannot delete another user's expense":                {RU: "нельзя удалить чужой расход"},
+	"possible duplicate expense":                          {RU: "возможно, это дубль расхода"},
+	"expense is locked by an approved report":             {RU: "расход заблокирован утверждённым отчётом"},
+	"expense is included in a report":                     {RU: "расход входит в отчёт"},
+	"amount must be positive":                             {RU: "сумма должна быть положительной"},
+	"unknown expense kind":                                {RU: "неизвестный вид записи"},
+	"refund must reference an expense of the same travel": {RU: "возврат должен ссылаться на расход того же путешествия"},
+	"mileage distance must be positive":                   {RU: "пробег должен быть положительным"},
+	"invalid category ID":                                 {RU: "некорректный ID категории"},
+	"invalid category name":                               {RU: "некорректное название категории"},
+	"invalid parent category":                             {RU: "некорректная родительская категория"},
+	"invalid category icon":                               {RU: "некорректная иконка категории"},
+	"invalid category color":                              {RU: "некорректный цвет категории"},
+	"invalid category order":                              {RU: "некорректный порядок категорий"},
+	"invalid target category":                             {RU: "некорректная целевая категория"},
+	"invalid reassign_to":                                 {RU: "некорректный reassign_to"},
+	"category not found":                                  {RU: "категория не найдена"},
+	"category not found or unavailable":                   {RU: "категория не найдена или недоступна"},
+	"category already exists":                             {RU: "категория уже существует"},
+	"category is used in expenses":                        {RU: "категория используется в расходах"},
+	"subcategory is used in expenses":                     {RU: "подкатегория используется в расходах"},
+	"category has subcategories: pass children=lift or children=delete": {RU: "у категории есть подкатегории: укажите children=lift или children=delete"},
+	"cannot delete builtin category":                                    {RU: "нельзя удалить встроенную категорию"},
+	"cannot rename builtin category":                                    {RU: "нельзя переименовать встроенную категорию"},
+	"cannot merge builtin category":                                     {RU: "нельзя объединить встроенную категорию"},
+	"cannot delete another user's category":                             {RU: "нельзя удалить чужую категорию"},
+	"cannot rename another user's category":                             {RU: "нельзя переименовать чужую категорию"},
+	"cannot merge another user's category":                              {RU: "нельзя объединить чужую категорию"},
+	"invalid match ID":                                                  {RU: "некорректный ID сопоставления"},
+	"match not found":                                                   {RU: "сопоставление не найдено"},
+	"match is already resolved":                                         {RU: "сопоставление уже обработано"},
+	"transaction direction does not match the entry kind":               {RU: "направление операции не совпадает с видом записи"},
+	"invalid amount":                                                    {RU: "некорректная сумма"},
+	"cannot change another user's match":                                {RU: "нельзя изменить чужое сопоставление"},
+	"invalid report ID":                                                 {RU: "некорректный ID отчёта"},
+	"invalid line ID":                                                   {RU: "некорректный ID строки отчёта"},
+	"report not found":                                                  {RU: "отчёт не найден"},
+	"report line not found":                                             {RU: "строка отчёта не найдена"},
+	"cannot access another user's report":                               {RU: "нет доступа к чужому отчёту"},
+	"no reimbursable expenses to report":                                {RU: "нет расходов для возмещения"},
+	"only an approver can perform this action":                          {RU: "действие доступно только согласующему"},
+	"only the report owner can perform this action":                     {RU: "действие доступно только автору отчёта"},
+	"report can only be changed in draft state":                         {RU: "отчёт можно менять только в черновике"},
+	"report status transition is not allowed":                           {RU: "недопустимый переход статуса отчёта"},
+	"expense is already included in another report":                     {RU: "расход уже включён в другой отчёт"},
+	"invalid policy":                                                    {RU: "некорректное правило"},
+	"invalid policy ID":                                                 {RU: "некорректный ID правила"},
+	"policy not found":                                                  {RU: "правило не найдено"},
+	"cannot delete another user's policy":                               {RU: "нельзя удалить чужое правило"},
+	"no allowance rate for this location":                               {RU: "нет ставки для этого места"},
+	"per-diem entries are included in a report":                         {RU: "суточные уже включены в отчёт"},
+	"match is not in proposed state":                                    {RU: "сопоставление не ожидает подтверждения"},
+	"report has no reimbursable expenses":                               {RU: "в отчёте нет расходов для возмещения"},
+	"travel_id is required":                                             {RU: "не указан travel_id"},
+	"invalid granularity":                                               {RU: "шаг должен быть day, week или month"},
+	"invalid timezone":                                                  {RU: "неизвестный часовой пояс"},
+	"too many buckets: use a coarser granularity or a shorter period":   {RU: "слишком много периодов: выберите шаг крупнее или период короче"},
+	"from date must be before to date":                                  {RU: "дата начала должна быть раньше даты окончания"},
+	"travel end date is before start date":                              {RU: "дата окончания путешествия раньше даты начала"},
+	"top must be between 1 and 100":                                     {RU: "top должен быть от 1 до 100"},
+
+	// Графики
+	"chart must be categories, daily or cumulative": {RU: "график должен быть categories, daily или cumulative"},
+	"format must be svg or png":                     {RU: "формат должен быть svg или png"},
+	"chart size is out of range":                    {RU: "недопустимый размер графика"},
+	"theme must be light or dark":                   {RU: "тема должна быть light или dark"},
+	"Spending by category":                          {RU: "Траты по категориям"},
+	"Spending by period":                            {RU: "Траты по периодам"},
+	"Cumulative spending":                           {RU: "Траты нарастающим итогом"},
+	"Budget":                                        {RU: "Бюджет"},
+	"Other":                                         {RU: "Прочее"},
+	"No data":                                       {RU: "Нет данных"},
+
+	// PDF-отчёт по поездке
+	"report is not ready yet":  {RU: "отчёт ещё не готов"},
+	"report generation failed": {RU: "не удалось построить отчёт"},
+	"invalid job ID":           {RU: "некорректный ID задания"},
+	"report job not found":     {RU: "задание на построение отчёта не найдено"},
+	"Generated on %s":          {RU: "Сформирован %s"},
+	"Page %d of %d":            {RU: "Страница %d из %d"},
+	"Summary":                  {RU: "Итоги"},
+	"Spent":                    {RU: "Потрачено"},
+	"Refunds":                  {RU: "Возвраты"},
+	"Net spending":             {RU: "Чистые траты"},
+	"Net":                      {RU: "Чистые"},
+	"Income":                   {RU: "Доходы"},
+	"Allowances":               {RU: "Начисления по нормативу"},
+	"Budget left":              {RU: "Остаток бюджета"},
+	"Days":                     {RU: "Дней"},
+	"Per day":                  {RU: "В день"},
+	"Participants":             {RU: "Участников"},
+	"Per person per day":       {RU: "На человека в день"},
+	"Category":                 {RU: "Категория"},
+	"Share":                    {RU: "Доля"},
+	"Total":                    {RU: "Итого"},
+	"Expenses by day":          {RU: "Расходы по дням"},
+	"No expenses":              {RU: "Расходов нет"},
+	"refund":                   {RU: "возврат"},
+	"reimbursement":            {RU: "компенсация"},
+	"income":                   {RU: "доход"},
+	"per diem":                 {RU: "суточные"},
+	"mileage":                  {RU: "пробег"},
+
+	// Начисления по нормативу
+	"Per diem: %s": {RU: "Суточные: %s"},
+	"per-diem and mileage entries are created by the allowance endpoints": {RU: "суточные и пробег начисляются только по ставкам"},
+
+	// Лента наблюдений; аргументы подставляются в том же порядке
+	"expense of %.2f is %.1f× your usual %.2f in %s":                     {RU: "расход %.2f в %.1f раза больше обычного (%.2f) в категории «%s»"},
+	"on %s you spent %.1f× your usual on %s: %.2f instead of about %.2f": {RU: "%s вы потратили в %.1f раза больше обычного на «%s»: %.2f вместо примерно %.2f"},
+	"on %s you spent %.1f× your usual: %.2f instead of about %.2f":       {RU: "%s вы потратили в %.1f раза больше обычного: %.2f вместо примерно %.2f"},
+	"%s is %.0f%% of this trip's spending vs %.0f%% on previous trips":   {RU: "«%s» — %.0f%% трат поездки против %.0f%% в прошлых поездках"},
+
+	// Пароль и его сброс
+	"invalid email":                  {RU: "некорректный адрес почты"},
+	"invalid current password":       {RU: "неверный текущий пароль"},
+	"invalid or expired reset token": {RU: "код сброса пароля недействителен или истёк"},
+	"WanderWallet password reset":    {RU: "Сброс пароля WanderWallet"},
+	"To reset the password of your account %s, use this code within an hour:": {RU: "Чтобы сбросить пароль учётной записи %s, используйте этот код в течение часа:"},
+	"If you did not request a password reset, ignore this email.":             {RU: "Если вы не запрашивали сброс пароля, просто проигнорируйте это письмо."},
+}
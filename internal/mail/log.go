@@ -0,0 +1,39 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// LogMailer для разработки: пишет письма в журнал, а если задан Dir — ещё и в файлы
+// .eml, которые открываются почтовым клиентом.
+type LogMailer struct {
+	From string
+	Dir  string
+}
+
+func NewLogMailer(from, dir string) *LogMailer {
+	return &LogMailer{From: from, Dir: dir}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("Mail to %s: %s\n%s\n", msg.To, msg.Subject, msg.Body)
+	if m.Dir == "" {
+		return nil
+	}
+
+	now := time.Now()
+	data, err := render(m.From, msg, now)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%d.eml", now.Format("20060102-150405"), now.UnixNano()%1e9)
+	return os.WriteFile(filepath.Join(m.Dir, name), data, 0o600)
+}
@@ -0,0 +1,128 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	netmail "net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// smtpSink — минимальный SMTP-сервер, принимающий одно письмо
+type smtpSink struct {
+	addr string
+	from string
+	rcpt string
+	data chan string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	s := &smtpSink{addr: ln.Addr().String(), data: make(chan string, 1)}
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+		reply("220 sink ready")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.TrimRight(line, "\r\n")
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 sink")
+			case strings.HasPrefix(cmd, "MAIL FROM:"):
+				s.from = strings.Trim(cmd[len("MAIL FROM:"):], "<>")
+				reply("250 ok")
+			case strings.HasPrefix(cmd, "RCPT TO:"):
+				s.rcpt = strings.Trim(cmd[len("RCPT TO:"):], "<>")
+				reply("250 ok")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				var body strings.Builder
+				for {
+					l, err := r.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					body.WriteString(l)
+				}
+				s.data <- body.String()
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("502 not implemented")
+			}
+		}
+	}()
+	return s
+}
+
+func readMessage(t *testing.T, raw string) (subject, body string) {
+	msg, err := netmail.ReadMessage(strings.NewReader(raw))
+	require.NoError(t, err)
+	subject, err = new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	decoded, err := io.ReadAll(quotedprintable.NewReader(msg.Body))
+	require.NoError(t, err)
+	return subject, string(decoded)
+}
+
+func TestSMTPMailer(t *testing.T) {
+	sink := newSMTPSink(t)
+	mailer := NewSMTPMailer(sink.addr, "noreply@wanderwallet.local", "", "")
+
+	err := mailer.Send(context.Background(), Message{
+		To:      "anna@example.com",
+		Subject: "Сброс пароля",
+		Body:    "Код для сброса пароля: abc\nОн действует один час.",
+	})
+	require.NoError(t, err)
+
+	subject, body := readMessage(t, <-sink.data)
+	assert.Equal(t, "noreply@wanderwallet.local", sink.from)
+	assert.Equal(t, "anna@example.com", sink.rcpt)
+	assert.Equal(t, "Сброс пароля", subject)
+	// Клиент SMTP завершает данные переводом строки
+	assert.Equal(t, "Код для сброса пароля: abc\r\nОн действует один час.\r\n", body)
+}
+
+func TestLogMailer(t *testing.T) {
+	dir := t.TempDir()
+	mailer := NewLogMailer("noreply@wanderwallet.local", dir)
+
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "anna@example.com", Subject: "Password reset", Body: "code"}))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	raw, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	subject, body := readMessage(t, string(raw))
+	assert.Equal(t, "Password reset", subject)
+	assert.Equal(t, "code", body)
+}
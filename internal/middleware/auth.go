@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"log"
 	"net/http"
 	"os"
 	"wanderwallet/initializers"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
 
 	"time"
@@ -12,7 +14,26 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 )
 
+// lastSeenInterval — время последнего запроса сессии записывается не чаще этого интервала,
+// чтобы не делать запись в БД на каждый запрос
+const lastSeenInterval = 5 * time.Minute
+
+// publicPaths — маршруты, доступные без токена доступа: вход, регистрация, обновление
+// токенов (к этому моменту токен доступа обычно уже истёк) и сброс забытого пароля
+var publicPaths = map[string]bool{
+	"/api/auth/register":        true,
+	"/api/auth/login":           true,
+	"/api/auth/refresh":         true,
+	"/api/auth/password/forgot": true,
+	"/api/auth/password/reset":  true,
+}
+
 func AuthMiddleware(c *gin.Context) {
+	if publicPaths[c.Request.URL.Path] {
+		c.Next()
+		return
+	}
+
 	// Пытаемся получить токен из заголовка Authorization
 	authHeader := c.GetHeader("Authorization")
 	if authHeader == "" {
@@ -53,13 +74,40 @@ func AuthMiddleware(c *gin.Context) {
 			return
 		}
 
+		// Токен без сессии выдан до появления отзыва токенов и не может быть отозван
+		sid, ok := claims["sid"].(string)
+		if !ok {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
 		var user models.User
 		if err := initializers.DB.First(&user, sub).Error; err != nil {
 			c.AbortWithStatus(http.StatusUnauthorized)
 			return
 		}
 
+		var session models.Session
+		if err := initializers.DB.
+			Where("id = ? AND user_id = ? AND revoked_at IS NULL", sid, user.ID).
+			First(&session).Error; err != nil {
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+
+		if now := time.Now(); session.LastSeenAt == nil || now.Sub(*session.LastSeenAt) > lastSeenInterval {
+			if err := initializers.DB.Model(&models.Session{}).
+				Where("id = ?", session.ID).
+				UpdateColumn("last_seen_at", now).Error; err != nil {
+				log.Printf("Failed to update last seen of session %d: %v\n", session.ID, err)
+			}
+		}
+
 		c.Set("user", user)
+		c.Set("session_id", session.ID)
+		if user.Locale != "" {
+			c.Set(i18n.ContextKey, user.Locale)
+		}
 		c.Next()
 	} else {
 		c.AbortWithStatus(http.StatusUnauthorized)
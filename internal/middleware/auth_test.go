@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthMiddleware_PublicPaths(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(AuthMiddleware)
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/api/auth/password/forgot", ok)
+	r.POST("/api/auth/password/reset", ok)
+	r.PUT("/api/auth/password", ok)
+
+	// Забывший пароль пользователь не вошёл в систему, поэтому сброс доступен без токена
+	for _, path := range []string{"/api/auth/password/forgot", "/api/auth/password/reset"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		assert.Equal(t, http.StatusOK, w.Code, path)
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/auth/password", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
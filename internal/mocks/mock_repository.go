@@ -37,6 +37,34 @@ func (m *MockUserRepositoryInterface) EXPECT() *MockUserRepositoryInterfaceMockR
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserRepositoryInterface) ChangePassword(ctx context.Context, userID uint, hash string, keepSessionID uint, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, userID, hash, keepSessionID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserRepositoryInterfaceMockRecorder) ChangePassword(ctx, userID, hash, keepSessionID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ChangePassword), ctx, userID, hash, keepSessionID, now)
+}
+
+// CreateResetToken mocks base method.
+func (m *MockUserRepositoryInterface) CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateResetToken", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateResetToken indicates an expected call of CreateResetToken.
+func (mr *MockUserRepositoryInterfaceMockRecorder) CreateResetToken(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateResetToken", reflect.TypeOf((*MockUserRepositoryInterface)(nil).CreateResetToken), ctx, token)
+}
+
 // CreateUser mocks base method.
 func (m *MockUserRepositoryInterface) CreateUser(ctx context.Context, user *models.User) error {
 	m.ctrl.T.Helper()
@@ -81,6 +109,36 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) GetByLogin(ctx, login interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLogin", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByLogin), ctx, login)
 }
 
+// GetByLoginOrEmail mocks base method.
+func (m *MockUserRepositoryInterface) GetByLoginOrEmail(ctx context.Context, value string) (*models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByLoginOrEmail", ctx, value)
+	ret0, _ := ret[0].(*models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByLoginOrEmail indicates an expected call of GetByLoginOrEmail.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetByLoginOrEmail(ctx, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByLoginOrEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetByLoginOrEmail), ctx, value)
+}
+
+// GetResetToken mocks base method.
+func (m *MockUserRepositoryInterface) GetResetToken(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResetToken", ctx, hash)
+	ret0, _ := ret[0].(*models.PasswordResetToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResetToken indicates an expected call of GetResetToken.
+func (mr *MockUserRepositoryInterfaceMockRecorder) GetResetToken(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResetToken", reflect.TypeOf((*MockUserRepositoryInterface)(nil).GetResetToken), ctx, hash)
+}
+
 // IsLoginExists mocks base method.
 func (m *MockUserRepositoryInterface) IsLoginExists(ctx context.Context, login string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -96,6 +154,49 @@ func (mr *MockUserRepositoryInterfaceMockRecorder) IsLoginExists(ctx, login inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLoginExists", reflect.TypeOf((*MockUserRepositoryInterface)(nil).IsLoginExists), ctx, login)
 }
 
+// ResetPassword mocks base method.
+func (m *MockUserRepositoryInterface) ResetPassword(ctx context.Context, token *models.PasswordResetToken, hash string, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, hash, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserRepositoryInterfaceMockRecorder) ResetPassword(ctx, token, hash, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserRepositoryInterface)(nil).ResetPassword), ctx, token, hash, now)
+}
+
+// UpdateEmail mocks base method.
+func (m *MockUserRepositoryInterface) UpdateEmail(ctx context.Context, userID uint, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEmail", ctx, userID, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEmail indicates an expected call of UpdateEmail.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateEmail(ctx, userID, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEmail", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateEmail), ctx, userID, email)
+}
+
+// UpdateLocale mocks base method.
+func (m *MockUserRepositoryInterface) UpdateLocale(ctx context.Context, userID uint, locale string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLocale", ctx, userID, locale)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLocale indicates an expected call of UpdateLocale.
+func (mr *MockUserRepositoryInterfaceMockRecorder) UpdateLocale(ctx, userID, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLocale", reflect.TypeOf((*MockUserRepositoryInterface)(nil).UpdateLocale), ctx, userID, locale)
+}
+
 // MockTravelRepositoryInterface is a mock of TravelRepositoryInterface interface.
 type MockTravelRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -119,6 +220,21 @@ func (m *MockTravelRepositoryInterface) EXPECT() *MockTravelRepositoryInterfaceM
 	return m.recorder
 }
 
+// CountTravelDays mocks base method.
+func (m *MockTravelRepositoryInterface) CountTravelDays(ctx context.Context, userID uint, from, to time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTravelDays", ctx, userID, from, to)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTravelDays indicates an expected call of CountTravelDays.
+func (mr *MockTravelRepositoryInterfaceMockRecorder) CountTravelDays(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTravelDays", reflect.TypeOf((*MockTravelRepositoryInterface)(nil).CountTravelDays), ctx, userID, from, to)
+}
+
 // CreateTravel mocks base method.
 func (m *MockTravelRepositoryInterface) CreateTravel(ctx context.Context, travel *models.Travel) error {
 	m.ctrl.T.Helper()
@@ -148,6 +264,50 @@ func (mr *MockTravelRepositoryInterfaceMockRecorder) GetTravelByID(ctx, travelID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTravelByID", reflect.TypeOf((*MockTravelRepositoryInterface)(nil).GetTravelByID), ctx, travelID)
 }
 
+// GetTravelsByUserID mocks base method.
+func (m *MockTravelRepositoryInterface) GetTravelsByUserID(ctx context.Context, userID uint) ([]models.Travel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTravelsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.Travel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTravelsByUserID indicates an expected call of GetTravelsByUserID.
+func (mr *MockTravelRepositoryInterfaceMockRecorder) GetTravelsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTravelsByUserID", reflect.TypeOf((*MockTravelRepositoryInterface)(nil).GetTravelsByUserID), ctx, userID)
+}
+
+// GetTravelsInRange mocks base method.
+func (m *MockTravelRepositoryInterface) GetTravelsInRange(ctx context.Context, userID uint, from, to time.Time) ([]models.Travel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTravelsInRange", ctx, userID, from, to)
+	ret0, _ := ret[0].([]models.Travel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTravelsInRange indicates an expected call of GetTravelsInRange.
+func (mr *MockTravelRepositoryInterfaceMockRecorder) GetTravelsInRange(ctx, userID, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTravelsInRange", reflect.TypeOf((*MockTravelRepositoryInterface)(nil).GetTravelsInRange), ctx, userID, from, to)
+}
+
+// UpdateBudget mocks base method.
+func (m *MockTravelRepositoryInterface) UpdateBudget(ctx context.Context, travelID uint, budget *float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBudget", ctx, travelID, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBudget indicates an expected call of UpdateBudget.
+func (mr *MockTravelRepositoryInterfaceMockRecorder) UpdateBudget(ctx, travelID, budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBudget", reflect.TypeOf((*MockTravelRepositoryInterface)(nil).UpdateBudget), ctx, travelID, budget)
+}
+
 // MockExpenseRepositoryInterface is a mock of ExpenseRepositoryInterface interface.
 type MockExpenseRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -214,6 +374,21 @@ func (mr *MockExpenseRepositoryInterfaceMockRecorder) ExistsByCategoryID(ctx, ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistsByCategoryID", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).ExistsByCategoryID), ctx, categoryID)
 }
 
+// FindSameAmountExpenses mocks base method.
+func (m *MockExpenseRepositoryInterface) FindSameAmountExpenses(ctx context.Context, userID, travelID uint, amount float64, from, to time.Time) ([]models.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindSameAmountExpenses", ctx, userID, travelID, amount, from, to)
+	ret0, _ := ret[0].([]models.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindSameAmountExpenses indicates an expected call of FindSameAmountExpenses.
+func (mr *MockExpenseRepositoryInterfaceMockRecorder) FindSameAmountExpenses(ctx, userID, travelID, amount, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindSameAmountExpenses", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).FindSameAmountExpenses), ctx, userID, travelID, amount, from, to)
+}
+
 // GetExpenseByID mocks base method.
 func (m *MockExpenseRepositoryInterface) GetExpenseByID(ctx context.Context, expenseID uint) (*models.Expense, error) {
 	m.ctrl.T.Helper()
@@ -229,6 +404,21 @@ func (mr *MockExpenseRepositoryInterfaceMockRecorder) GetExpenseByID(ctx, expens
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpenseByID", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).GetExpenseByID), ctx, expenseID)
 }
 
+// GetExpensesByTravelID mocks base method.
+func (m *MockExpenseRepositoryInterface) GetExpensesByTravelID(ctx context.Context, userID, travelID uint) ([]models.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpensesByTravelID", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpensesByTravelID indicates an expected call of GetExpensesByTravelID.
+func (mr *MockExpenseRepositoryInterfaceMockRecorder) GetExpensesByTravelID(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesByTravelID", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).GetExpensesByTravelID), ctx, userID, travelID)
+}
+
 // GetExpensesByUserID mocks base method.
 func (m *MockExpenseRepositoryInterface) GetExpensesByUserID(ctx context.Context, id uint) ([]models.Expense, error) {
 	m.ctrl.T.Helper()
@@ -259,49 +449,63 @@ func (mr *MockExpenseRepositoryInterfaceMockRecorder) GetExpensesByUserTimeAndCa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesByUserTimeAndCategory", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).GetExpensesByUserTimeAndCategory), ctx, filter)
 }
 
-// SumByCategory mocks base method.
-func (m *MockExpenseRepositoryInterface) SumByCategory(ctx context.Context, userID, travelID uint, from, to *time.Time) (map[string]float64, error) {
+// GetReportStatuses mocks base method.
+func (m *MockExpenseRepositoryInterface) GetReportStatuses(ctx context.Context, expenseID uint) ([]models.ReportStatus, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SumByCategory", ctx, userID, travelID, from, to)
-	ret0, _ := ret[0].(map[string]float64)
+	ret := m.ctrl.Call(m, "GetReportStatuses", ctx, expenseID)
+	ret0, _ := ret[0].([]models.ReportStatus)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SumByCategory indicates an expected call of SumByCategory.
-func (mr *MockExpenseRepositoryInterfaceMockRecorder) SumByCategory(ctx, userID, travelID, from, to interface{}) *gomock.Call {
+// GetReportStatuses indicates an expected call of GetReportStatuses.
+func (mr *MockExpenseRepositoryInterfaceMockRecorder) GetReportStatuses(ctx, expenseID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportStatuses", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).GetReportStatuses), ctx, expenseID)
+}
+
+// ReplacePerDiem mocks base method.
+func (m *MockExpenseRepositoryInterface) ReplacePerDiem(ctx context.Context, userID, travelID uint, entries []models.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePerDiem", ctx, userID, travelID, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplacePerDiem indicates an expected call of ReplacePerDiem.
+func (mr *MockExpenseRepositoryInterfaceMockRecorder) ReplacePerDiem(ctx, userID, travelID, entries interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByCategory", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).SumByCategory), ctx, userID, travelID, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePerDiem", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).ReplacePerDiem), ctx, userID, travelID, entries)
 }
 
-// SumByDay mocks base method.
-func (m *MockExpenseRepositoryInterface) SumByDay(ctx context.Context, userID, travelID uint, from, to *time.Time) (map[string]float64, error) {
+// SumByTravelAndCategory mocks base method.
+func (m *MockExpenseRepositoryInterface) SumByTravelAndCategory(ctx context.Context, userID uint) ([]repository.TravelCategorySummary, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SumByDay", ctx, userID, travelID, from, to)
-	ret0, _ := ret[0].(map[string]float64)
+	ret := m.ctrl.Call(m, "SumByTravelAndCategory", ctx, userID)
+	ret0, _ := ret[0].([]repository.TravelCategorySummary)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// SumByDay indicates an expected call of SumByDay.
-func (mr *MockExpenseRepositoryInterfaceMockRecorder) SumByDay(ctx, userID, travelID, from, to interface{}) *gomock.Call {
+// SumByTravelAndCategory indicates an expected call of SumByTravelAndCategory.
+func (mr *MockExpenseRepositoryInterfaceMockRecorder) SumByTravelAndCategory(ctx, userID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByDay", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).SumByDay), ctx, userID, travelID, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByTravelAndCategory", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).SumByTravelAndCategory), ctx, userID)
 }
 
-// TotalSum mocks base method.
-func (m *MockExpenseRepositoryInterface) TotalSum(ctx context.Context, userID, travelID uint, from, to *time.Time) (float64, error) {
+// Summarize mocks base method.
+func (m *MockExpenseRepositoryInterface) Summarize(ctx context.Context, q repository.SummaryQuery) (*repository.AnalyticsSums, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "TotalSum", ctx, userID, travelID, from, to)
-	ret0, _ := ret[0].(float64)
+	ret := m.ctrl.Call(m, "Summarize", ctx, q)
+	ret0, _ := ret[0].(*repository.AnalyticsSums)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// TotalSum indicates an expected call of TotalSum.
-func (mr *MockExpenseRepositoryInterfaceMockRecorder) TotalSum(ctx, userID, travelID, from, to interface{}) *gomock.Call {
+// Summarize indicates an expected call of Summarize.
+func (mr *MockExpenseRepositoryInterfaceMockRecorder) Summarize(ctx, q interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TotalSum", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).TotalSum), ctx, userID, travelID, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summarize", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).Summarize), ctx, q)
 }
 
 // UpdateExpense mocks base method.
@@ -318,6 +522,59 @@ func (mr *MockExpenseRepositoryInterfaceMockRecorder) UpdateExpense(ctx, expense
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateExpense", reflect.TypeOf((*MockExpenseRepositoryInterface)(nil).UpdateExpense), ctx, expense)
 }
 
+// MockAnalyticsRepositoryInterface is a mock of AnalyticsRepositoryInterface interface.
+type MockAnalyticsRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAnalyticsRepositoryInterfaceMockRecorder
+}
+
+// MockAnalyticsRepositoryInterfaceMockRecorder is the mock recorder for MockAnalyticsRepositoryInterface.
+type MockAnalyticsRepositoryInterfaceMockRecorder struct {
+	mock *MockAnalyticsRepositoryInterface
+}
+
+// NewMockAnalyticsRepositoryInterface creates a new mock instance.
+func NewMockAnalyticsRepositoryInterface(ctrl *gomock.Controller) *MockAnalyticsRepositoryInterface {
+	mock := &MockAnalyticsRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAnalyticsRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAnalyticsRepositoryInterface) EXPECT() *MockAnalyticsRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// SumByTravelAndCategory mocks base method.
+func (m *MockAnalyticsRepositoryInterface) SumByTravelAndCategory(ctx context.Context, userID uint) ([]repository.TravelCategorySummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumByTravelAndCategory", ctx, userID)
+	ret0, _ := ret[0].([]repository.TravelCategorySummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumByTravelAndCategory indicates an expected call of SumByTravelAndCategory.
+func (mr *MockAnalyticsRepositoryInterfaceMockRecorder) SumByTravelAndCategory(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumByTravelAndCategory", reflect.TypeOf((*MockAnalyticsRepositoryInterface)(nil).SumByTravelAndCategory), ctx, userID)
+}
+
+// Summarize mocks base method.
+func (m *MockAnalyticsRepositoryInterface) Summarize(ctx context.Context, q repository.SummaryQuery) (*repository.AnalyticsSums, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summarize", ctx, q)
+	ret0, _ := ret[0].(*repository.AnalyticsSums)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summarize indicates an expected call of Summarize.
+func (mr *MockAnalyticsRepositoryInterfaceMockRecorder) Summarize(ctx, q interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summarize", reflect.TypeOf((*MockAnalyticsRepositoryInterface)(nil).Summarize), ctx, q)
+}
+
 // MockCategoryRepositoryInterface is a mock of CategoryRepositoryInterface interface.
 type MockCategoryRepositoryInterface struct {
 	ctrl     *gomock.Controller
@@ -355,6 +612,20 @@ func (mr *MockCategoryRepositoryInterfaceMockRecorder) CreateCategory(ctx, categ
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).CreateCategory), ctx, category)
 }
 
+// DeleteCategories mocks base method.
+func (m *MockCategoryRepositoryInterface) DeleteCategories(ctx context.Context, categoryIDs []uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCategories", ctx, categoryIDs)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCategories indicates an expected call of DeleteCategories.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) DeleteCategories(ctx, categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategories", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).DeleteCategories), ctx, categoryIDs)
+}
+
 // DeleteCategory mocks base method.
 func (m *MockCategoryRepositoryInterface) DeleteCategory(ctx context.Context, categoryID uint) error {
 	m.ctrl.T.Helper()
@@ -399,17 +670,845 @@ func (mr *MockCategoryRepositoryInterfaceMockRecorder) GetCategoryByID(ctx, id i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByID", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).GetCategoryByID), ctx, id)
 }
 
+// GetCategoryByKey mocks base method.
+func (m *MockCategoryRepositoryInterface) GetCategoryByKey(ctx context.Context, key string) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryByKey", ctx, key)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryByKey indicates an expected call of GetCategoryByKey.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) GetCategoryByKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByKey", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).GetCategoryByKey), ctx, key)
+}
+
 // GetCategoryByName mocks base method.
-func (m *MockCategoryRepositoryInterface) GetCategoryByName(ctx context.Context, name string) (*models.Category, error) {
+func (m *MockCategoryRepositoryInterface) GetCategoryByName(ctx context.Context, userID uint, name string) (*models.Category, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategoryByName", ctx, name)
+	ret := m.ctrl.Call(m, "GetCategoryByName", ctx, userID, name)
 	ret0, _ := ret[0].(*models.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCategoryByName indicates an expected call of GetCategoryByName.
-func (mr *MockCategoryRepositoryInterfaceMockRecorder) GetCategoryByName(ctx, name interface{}) *gomock.Call {
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) GetCategoryByName(ctx, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByName", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).GetCategoryByName), ctx, userID, name)
+}
+
+// GetChildCategories mocks base method.
+func (m *MockCategoryRepositoryInterface) GetChildCategories(ctx context.Context, parentID uint) ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChildCategories", ctx, parentID)
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChildCategories indicates an expected call of GetChildCategories.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) GetChildCategories(ctx, parentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChildCategories", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).GetChildCategories), ctx, parentID)
+}
+
+// MergeCategory mocks base method.
+func (m *MockCategoryRepositoryInterface) MergeCategory(ctx context.Context, source, target *models.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCategory", ctx, source, target)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeCategory indicates an expected call of MergeCategory.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) MergeCategory(ctx, source, target interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCategory", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).MergeCategory), ctx, source, target)
+}
+
+// SetCategoryArchived mocks base method.
+func (m *MockCategoryRepositoryInterface) SetCategoryArchived(ctx context.Context, userID uint, category *models.Category, archived bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCategoryArchived", ctx, userID, category, archived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCategoryArchived indicates an expected call of SetCategoryArchived.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) SetCategoryArchived(ctx, userID, category, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCategoryArchived", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).SetCategoryArchived), ctx, userID, category, archived)
+}
+
+// SetCategoryOrder mocks base method.
+func (m *MockCategoryRepositoryInterface) SetCategoryOrder(ctx context.Context, userID uint, categories []models.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetCategoryOrder", ctx, userID, categories)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetCategoryOrder indicates an expected call of SetCategoryOrder.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) SetCategoryOrder(ctx, userID, categories interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetCategoryOrder", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).SetCategoryOrder), ctx, userID, categories)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockCategoryRepositoryInterface) UpdateCategory(ctx context.Context, category *models.Category) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", ctx, category)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockCategoryRepositoryInterfaceMockRecorder) UpdateCategory(ctx, category interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).UpdateCategory), ctx, category)
+}
+
+// MockReconciliationRepositoryInterface is a mock of ReconciliationRepositoryInterface interface.
+type MockReconciliationRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationRepositoryInterfaceMockRecorder
+}
+
+// MockReconciliationRepositoryInterfaceMockRecorder is the mock recorder for MockReconciliationRepositoryInterface.
+type MockReconciliationRepositoryInterfaceMockRecorder struct {
+	mock *MockReconciliationRepositoryInterface
+}
+
+// NewMockReconciliationRepositoryInterface creates a new mock instance.
+func NewMockReconciliationRepositoryInterface(ctrl *gomock.Controller) *MockReconciliationRepositoryInterface {
+	mock := &MockReconciliationRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockReconciliationRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationRepositoryInterface) EXPECT() *MockReconciliationRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmMatch mocks base method.
+func (m *MockReconciliationRepositoryInterface) ConfirmMatch(ctx context.Context, match *models.ReconciliationMatch, expense *models.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMatch", ctx, match, expense)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmMatch indicates an expected call of ConfirmMatch.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) ConfirmMatch(ctx, match, expense interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMatch", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).ConfirmMatch), ctx, match, expense)
+}
+
+// CreateTransactions mocks base method.
+func (m *MockReconciliationRepositoryInterface) CreateTransactions(ctx context.Context, transactions []models.BankTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransactions", ctx, transactions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransactions indicates an expected call of CreateTransactions.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) CreateTransactions(ctx, transactions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransactions", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).CreateTransactions), ctx, transactions)
+}
+
+// GetMatchByID mocks base method.
+func (m *MockReconciliationRepositoryInterface) GetMatchByID(ctx context.Context, matchID uint) (*models.ReconciliationMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMatchByID", ctx, matchID)
+	ret0, _ := ret[0].(*models.ReconciliationMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMatchByID indicates an expected call of GetMatchByID.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) GetMatchByID(ctx, matchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMatchByID", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).GetMatchByID), ctx, matchID)
+}
+
+// GetMatchesByTravelID mocks base method.
+func (m *MockReconciliationRepositoryInterface) GetMatchesByTravelID(ctx context.Context, userID, travelID uint) ([]models.ReconciliationMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMatchesByTravelID", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.ReconciliationMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMatchesByTravelID indicates an expected call of GetMatchesByTravelID.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) GetMatchesByTravelID(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMatchesByTravelID", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).GetMatchesByTravelID), ctx, userID, travelID)
+}
+
+// GetTransactionsByTravelID mocks base method.
+func (m *MockReconciliationRepositoryInterface) GetTransactionsByTravelID(ctx context.Context, userID, travelID uint) ([]models.BankTransaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionsByTravelID", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.BankTransaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionsByTravelID indicates an expected call of GetTransactionsByTravelID.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) GetTransactionsByTravelID(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionsByTravelID", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).GetTransactionsByTravelID), ctx, userID, travelID)
+}
+
+// ReplaceProposedMatches mocks base method.
+func (m *MockReconciliationRepositoryInterface) ReplaceProposedMatches(ctx context.Context, userID, travelID uint, matches []models.ReconciliationMatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceProposedMatches", ctx, userID, travelID, matches)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceProposedMatches indicates an expected call of ReplaceProposedMatches.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) ReplaceProposedMatches(ctx, userID, travelID, matches interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceProposedMatches", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).ReplaceProposedMatches), ctx, userID, travelID, matches)
+}
+
+// UpdateMatchStatus mocks base method.
+func (m *MockReconciliationRepositoryInterface) UpdateMatchStatus(ctx context.Context, matchID uint, status models.MatchStatus) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMatchStatus", ctx, matchID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMatchStatus indicates an expected call of UpdateMatchStatus.
+func (mr *MockReconciliationRepositoryInterfaceMockRecorder) UpdateMatchStatus(ctx, matchID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMatchStatus", reflect.TypeOf((*MockReconciliationRepositoryInterface)(nil).UpdateMatchStatus), ctx, matchID, status)
+}
+
+// MockExpenseReportRepositoryInterface is a mock of ExpenseReportRepositoryInterface interface.
+type MockExpenseReportRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenseReportRepositoryInterfaceMockRecorder
+}
+
+// MockExpenseReportRepositoryInterfaceMockRecorder is the mock recorder for MockExpenseReportRepositoryInterface.
+type MockExpenseReportRepositoryInterfaceMockRecorder struct {
+	mock *MockExpenseReportRepositoryInterface
+}
+
+// NewMockExpenseReportRepositoryInterface creates a new mock instance.
+func NewMockExpenseReportRepositoryInterface(ctrl *gomock.Controller) *MockExpenseReportRepositoryInterface {
+	mock := &MockExpenseReportRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockExpenseReportRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseReportRepositoryInterface) EXPECT() *MockExpenseReportRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateReport mocks base method.
+func (m *MockExpenseReportRepositoryInterface) CreateReport(ctx context.Context, report *models.ExpenseReport) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReport", ctx, report)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReport indicates an expected call of CreateReport.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) CreateReport(ctx, report interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).CreateReport), ctx, report)
+}
+
+// DeleteReport mocks base method.
+func (m *MockExpenseReportRepositoryInterface) DeleteReport(ctx context.Context, reportID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReport", ctx, reportID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReport indicates an expected call of DeleteReport.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) DeleteReport(ctx, reportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReport", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).DeleteReport), ctx, reportID)
+}
+
+// GetReportByID mocks base method.
+func (m *MockExpenseReportRepositoryInterface) GetReportByID(ctx context.Context, reportID uint) (*models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportByID", ctx, reportID)
+	ret0, _ := ret[0].(*models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportByID indicates an expected call of GetReportByID.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) GetReportByID(ctx, reportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportByID", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).GetReportByID), ctx, reportID)
+}
+
+// GetReportedExpenseIDs mocks base method.
+func (m *MockExpenseReportRepositoryInterface) GetReportedExpenseIDs(ctx context.Context, travelID uint) ([]uint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportedExpenseIDs", ctx, travelID)
+	ret0, _ := ret[0].([]uint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportedExpenseIDs indicates an expected call of GetReportedExpenseIDs.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) GetReportedExpenseIDs(ctx, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportedExpenseIDs", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).GetReportedExpenseIDs), ctx, travelID)
+}
+
+// GetReportsByStatus mocks base method.
+func (m *MockExpenseReportRepositoryInterface) GetReportsByStatus(ctx context.Context, status models.ReportStatus) ([]models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsByStatus", ctx, status)
+	ret0, _ := ret[0].([]models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsByStatus indicates an expected call of GetReportsByStatus.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) GetReportsByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsByStatus", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).GetReportsByStatus), ctx, status)
+}
+
+// GetReportsByUserID mocks base method.
+func (m *MockExpenseReportRepositoryInterface) GetReportsByUserID(ctx context.Context, userID uint) ([]models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsByUserID indicates an expected call of GetReportsByUserID.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) GetReportsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsByUserID", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).GetReportsByUserID), ctx, userID)
+}
+
+// UpdateLine mocks base method.
+func (m *MockExpenseReportRepositoryInterface) UpdateLine(ctx context.Context, line *models.ExpenseReportLine) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLine", ctx, line)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateLine indicates an expected call of UpdateLine.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) UpdateLine(ctx, line interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLine", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).UpdateLine), ctx, line)
+}
+
+// UpdateStatus mocks base method.
+func (m *MockExpenseReportRepositoryInterface) UpdateStatus(ctx context.Context, reportID uint, transition *models.ExpenseReportTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, reportID, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockExpenseReportRepositoryInterfaceMockRecorder) UpdateStatus(ctx, reportID, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockExpenseReportRepositoryInterface)(nil).UpdateStatus), ctx, reportID, transition)
+}
+
+// MockPolicyRepositoryInterface is a mock of PolicyRepositoryInterface interface.
+type MockPolicyRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyRepositoryInterfaceMockRecorder
+}
+
+// MockPolicyRepositoryInterfaceMockRecorder is the mock recorder for MockPolicyRepositoryInterface.
+type MockPolicyRepositoryInterfaceMockRecorder struct {
+	mock *MockPolicyRepositoryInterface
+}
+
+// NewMockPolicyRepositoryInterface creates a new mock instance.
+func NewMockPolicyRepositoryInterface(ctrl *gomock.Controller) *MockPolicyRepositoryInterface {
+	mock := &MockPolicyRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockPolicyRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyRepositoryInterface) EXPECT() *MockPolicyRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreatePolicy mocks base method.
+func (m *MockPolicyRepositoryInterface) CreatePolicy(ctx context.Context, policy *models.SpendingPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePolicy", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePolicy indicates an expected call of CreatePolicy.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) CreatePolicy(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePolicy", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).CreatePolicy), ctx, policy)
+}
+
+// DeletePolicy mocks base method.
+func (m *MockPolicyRepositoryInterface) DeletePolicy(ctx context.Context, policyID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", ctx, policyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) DeletePolicy(ctx, policyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).DeletePolicy), ctx, policyID)
+}
+
+// GetPoliciesByUserID mocks base method.
+func (m *MockPolicyRepositoryInterface) GetPoliciesByUserID(ctx context.Context, userID uint) ([]models.SpendingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoliciesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.SpendingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoliciesByUserID indicates an expected call of GetPoliciesByUserID.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) GetPoliciesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoliciesByUserID", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).GetPoliciesByUserID), ctx, userID)
+}
+
+// GetPoliciesForTravel mocks base method.
+func (m *MockPolicyRepositoryInterface) GetPoliciesForTravel(ctx context.Context, userID, travelID uint) ([]models.SpendingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoliciesForTravel", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.SpendingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoliciesForTravel indicates an expected call of GetPoliciesForTravel.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) GetPoliciesForTravel(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoliciesForTravel", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).GetPoliciesForTravel), ctx, userID, travelID)
+}
+
+// GetPolicyByID mocks base method.
+func (m *MockPolicyRepositoryInterface) GetPolicyByID(ctx context.Context, policyID uint) (*models.SpendingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyByID", ctx, policyID)
+	ret0, _ := ret[0].(*models.SpendingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicyByID indicates an expected call of GetPolicyByID.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) GetPolicyByID(ctx, policyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyByID", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).GetPolicyByID), ctx, policyID)
+}
+
+// GetViolationsByTravelID mocks base method.
+func (m *MockPolicyRepositoryInterface) GetViolationsByTravelID(ctx context.Context, userID, travelID uint) ([]models.PolicyViolation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetViolationsByTravelID", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.PolicyViolation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetViolationsByTravelID indicates an expected call of GetViolationsByTravelID.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) GetViolationsByTravelID(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetViolationsByTravelID", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).GetViolationsByTravelID), ctx, userID, travelID)
+}
+
+// ReplaceViolations mocks base method.
+func (m *MockPolicyRepositoryInterface) ReplaceViolations(ctx context.Context, userID, travelID uint, violations []models.PolicyViolation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceViolations", ctx, userID, travelID, violations)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceViolations indicates an expected call of ReplaceViolations.
+func (mr *MockPolicyRepositoryInterfaceMockRecorder) ReplaceViolations(ctx, userID, travelID, violations interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceViolations", reflect.TypeOf((*MockPolicyRepositoryInterface)(nil).ReplaceViolations), ctx, userID, travelID, violations)
+}
+
+// MockAllowanceRepositoryInterface is a mock of AllowanceRepositoryInterface interface.
+type MockAllowanceRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAllowanceRepositoryInterfaceMockRecorder
+}
+
+// MockAllowanceRepositoryInterfaceMockRecorder is the mock recorder for MockAllowanceRepositoryInterface.
+type MockAllowanceRepositoryInterfaceMockRecorder struct {
+	mock *MockAllowanceRepositoryInterface
+}
+
+// NewMockAllowanceRepositoryInterface creates a new mock instance.
+func NewMockAllowanceRepositoryInterface(ctrl *gomock.Controller) *MockAllowanceRepositoryInterface {
+	mock := &MockAllowanceRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockAllowanceRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAllowanceRepositoryInterface) EXPECT() *MockAllowanceRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// FindRate mocks base method.
+func (m *MockAllowanceRepositoryInterface) FindRate(ctx context.Context, country, city string) (*models.PerDiemRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindRate", ctx, country, city)
+	ret0, _ := ret[0].(*models.PerDiemRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindRate indicates an expected call of FindRate.
+func (mr *MockAllowanceRepositoryInterfaceMockRecorder) FindRate(ctx, country, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindRate", reflect.TypeOf((*MockAllowanceRepositoryInterface)(nil).FindRate), ctx, country, city)
+}
+
+// GetRates mocks base method.
+func (m *MockAllowanceRepositoryInterface) GetRates(ctx context.Context, country string) ([]models.PerDiemRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates", ctx, country)
+	ret0, _ := ret[0].([]models.PerDiemRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockAllowanceRepositoryInterfaceMockRecorder) GetRates(ctx, country interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockAllowanceRepositoryInterface)(nil).GetRates), ctx, country)
+}
+
+// UpsertRates mocks base method.
+func (m *MockAllowanceRepositoryInterface) UpsertRates(ctx context.Context, rates []models.PerDiemRate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertRates", ctx, rates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpsertRates indicates an expected call of UpsertRates.
+func (mr *MockAllowanceRepositoryInterfaceMockRecorder) UpsertRates(ctx, rates interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertRates", reflect.TypeOf((*MockAllowanceRepositoryInterface)(nil).UpsertRates), ctx, rates)
+}
+
+// MockInsightRepositoryInterface is a mock of InsightRepositoryInterface interface.
+type MockInsightRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInsightRepositoryInterfaceMockRecorder
+}
+
+// MockInsightRepositoryInterfaceMockRecorder is the mock recorder for MockInsightRepositoryInterface.
+type MockInsightRepositoryInterfaceMockRecorder struct {
+	mock *MockInsightRepositoryInterface
+}
+
+// NewMockInsightRepositoryInterface creates a new mock instance.
+func NewMockInsightRepositoryInterface(ctrl *gomock.Controller) *MockInsightRepositoryInterface {
+	mock := &MockInsightRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockInsightRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInsightRepositoryInterface) EXPECT() *MockInsightRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetInsights mocks base method.
+func (m *MockInsightRepositoryInterface) GetInsights(ctx context.Context, userID uint, travelID *uint) ([]models.Insight, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInsights", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.Insight)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInsights indicates an expected call of GetInsights.
+func (mr *MockInsightRepositoryInterfaceMockRecorder) GetInsights(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInsights", reflect.TypeOf((*MockInsightRepositoryInterface)(nil).GetInsights), ctx, userID, travelID)
+}
+
+// ReplaceInsights mocks base method.
+func (m *MockInsightRepositoryInterface) ReplaceInsights(ctx context.Context, userID, travelID uint, insights []models.Insight) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceInsights", ctx, userID, travelID, insights)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceInsights indicates an expected call of ReplaceInsights.
+func (mr *MockInsightRepositoryInterfaceMockRecorder) ReplaceInsights(ctx, userID, travelID, insights interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceInsights", reflect.TypeOf((*MockInsightRepositoryInterface)(nil).ReplaceInsights), ctx, userID, travelID, insights)
+}
+
+// MockTripReportRepositoryInterface is a mock of TripReportRepositoryInterface interface.
+type MockTripReportRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTripReportRepositoryInterfaceMockRecorder
+}
+
+// MockTripReportRepositoryInterfaceMockRecorder is the mock recorder for MockTripReportRepositoryInterface.
+type MockTripReportRepositoryInterfaceMockRecorder struct {
+	mock *MockTripReportRepositoryInterface
+}
+
+// NewMockTripReportRepositoryInterface creates a new mock instance.
+func NewMockTripReportRepositoryInterface(ctrl *gomock.Controller) *MockTripReportRepositoryInterface {
+	mock := &MockTripReportRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockTripReportRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTripReportRepositoryInterface) EXPECT() *MockTripReportRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateJob mocks base method.
+func (m *MockTripReportRepositoryInterface) CreateJob(ctx context.Context, job *models.TripReportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateJob indicates an expected call of CreateJob.
+func (mr *MockTripReportRepositoryInterfaceMockRecorder) CreateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateJob", reflect.TypeOf((*MockTripReportRepositoryInterface)(nil).CreateJob), ctx, job)
+}
+
+// DeleteJobsBefore mocks base method.
+func (m *MockTripReportRepositoryInterface) DeleteJobsBefore(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteJobsBefore", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteJobsBefore indicates an expected call of DeleteJobsBefore.
+func (mr *MockTripReportRepositoryInterfaceMockRecorder) DeleteJobsBefore(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteJobsBefore", reflect.TypeOf((*MockTripReportRepositoryInterface)(nil).DeleteJobsBefore), ctx, before)
+}
+
+// GetJobByID mocks base method.
+func (m *MockTripReportRepositoryInterface) GetJobByID(ctx context.Context, jobID uint) (*models.TripReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobByID", ctx, jobID)
+	ret0, _ := ret[0].(*models.TripReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobByID indicates an expected call of GetJobByID.
+func (mr *MockTripReportRepositoryInterfaceMockRecorder) GetJobByID(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobByID", reflect.TypeOf((*MockTripReportRepositoryInterface)(nil).GetJobByID), ctx, jobID)
+}
+
+// GetJobFile mocks base method.
+func (m *MockTripReportRepositoryInterface) GetJobFile(ctx context.Context, jobID uint) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJobFile", ctx, jobID)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJobFile indicates an expected call of GetJobFile.
+func (mr *MockTripReportRepositoryInterfaceMockRecorder) GetJobFile(ctx, jobID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJobFile", reflect.TypeOf((*MockTripReportRepositoryInterface)(nil).GetJobFile), ctx, jobID)
+}
+
+// UpdateJob mocks base method.
+func (m *MockTripReportRepositoryInterface) UpdateJob(ctx context.Context, job *models.TripReportJob) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateJob", ctx, job)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateJob indicates an expected call of UpdateJob.
+func (mr *MockTripReportRepositoryInterfaceMockRecorder) UpdateJob(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateJob", reflect.TypeOf((*MockTripReportRepositoryInterface)(nil).UpdateJob), ctx, job)
+}
+
+// MockSessionRepositoryInterface is a mock of SessionRepositoryInterface interface.
+type MockSessionRepositoryInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionRepositoryInterfaceMockRecorder
+}
+
+// MockSessionRepositoryInterfaceMockRecorder is the mock recorder for MockSessionRepositoryInterface.
+type MockSessionRepositoryInterfaceMockRecorder struct {
+	mock *MockSessionRepositoryInterface
+}
+
+// NewMockSessionRepositoryInterface creates a new mock instance.
+func NewMockSessionRepositoryInterface(ctrl *gomock.Controller) *MockSessionRepositoryInterface {
+	mock := &MockSessionRepositoryInterface{ctrl: ctrl}
+	mock.recorder = &MockSessionRepositoryInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionRepositoryInterface) EXPECT() *MockSessionRepositoryInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateSession mocks base method.
+func (m *MockSessionRepositoryInterface) CreateSession(ctx context.Context, session *models.Session, token *models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) CreateSession(ctx, session, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).CreateSession), ctx, session, token)
+}
+
+// DeleteExpiredTokens mocks base method.
+func (m *MockSessionRepositoryInterface) DeleteExpiredTokens(ctx context.Context, before time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpiredTokens", ctx, before)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteExpiredTokens indicates an expected call of DeleteExpiredTokens.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) DeleteExpiredTokens(ctx, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpiredTokens", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).DeleteExpiredTokens), ctx, before)
+}
+
+// GetActiveSessions mocks base method.
+func (m *MockSessionRepositoryInterface) GetActiveSessions(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessions", ctx, userID, now)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessions indicates an expected call of GetActiveSessions.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) GetActiveSessions(ctx, userID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessions", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).GetActiveSessions), ctx, userID, now)
+}
+
+// GetRefreshToken mocks base method.
+func (m *MockSessionRepositoryInterface) GetRefreshToken(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRefreshToken", ctx, hash)
+	ret0, _ := ret[0].(*models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRefreshToken indicates an expected call of GetRefreshToken.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) GetRefreshToken(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRefreshToken", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).GetRefreshToken), ctx, hash)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockSessionRepositoryInterface) RevokeOtherSessions(ctx context.Context, userID, keepID uint, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userID, keepID, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) RevokeOtherSessions(ctx, userID, keepID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).RevokeOtherSessions), ctx, userID, keepID, now)
+}
+
+// RevokeSession mocks base method.
+func (m *MockSessionRepositoryInterface) RevokeSession(ctx context.Context, sessionID uint, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, sessionID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) RevokeSession(ctx, sessionID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).RevokeSession), ctx, sessionID, now)
+}
+
+// RevokeUserSession mocks base method.
+func (m *MockSessionRepositoryInterface) RevokeUserSession(ctx context.Context, userID, sessionID uint, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSession", ctx, userID, sessionID, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeUserSession indicates an expected call of RevokeUserSession.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) RevokeUserSession(ctx, userID, sessionID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSession", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).RevokeUserSession), ctx, userID, sessionID, now)
+}
+
+// RotateRefreshToken mocks base method.
+func (m *MockSessionRepositoryInterface) RotateRefreshToken(ctx context.Context, usedID uint, next *models.RefreshToken, now time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateRefreshToken", ctx, usedID, next, now)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateRefreshToken indicates an expected call of RotateRefreshToken.
+func (mr *MockSessionRepositoryInterfaceMockRecorder) RotateRefreshToken(ctx, usedID, next, now interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByName", reflect.TypeOf((*MockCategoryRepositoryInterface)(nil).GetCategoryByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateRefreshToken", reflect.TypeOf((*MockSessionRepositoryInterface)(nil).RotateRefreshToken), ctx, usedID, next, now)
 }
@@ -9,6 +9,7 @@ import (
 	reflect "reflect"
 	time "time"
 	dto "wanderwallet/internal/dto"
+	i18n "wanderwallet/internal/i18n"
 	models "wanderwallet/internal/models"
 
 	gomock "github.com/golang/mock/gomock"
@@ -37,6 +38,35 @@ func (m *MockUserServiceInterface) EXPECT() *MockUserServiceInterfaceMockRecorde
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockUserServiceInterface) ChangePassword(ctx context.Context, user *models.User, sessionID uint, current, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, user, sessionID, current, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockUserServiceInterfaceMockRecorder) ChangePassword(ctx, user, sessionID, current, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockUserServiceInterface)(nil).ChangePassword), ctx, user, sessionID, current, password)
+}
+
+// GetSessions mocks base method.
+func (m *MockUserServiceInterface) GetSessions(ctx context.Context, userID uint) ([]models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", ctx, userID)
+	ret0, _ := ret[0].([]models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockUserServiceInterfaceMockRecorder) GetSessions(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockUserServiceInterface)(nil).GetSessions), ctx, userID)
+}
+
 // GetUserByID mocks base method.
 func (m *MockUserServiceInterface) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
 	m.ctrl.T.Helper()
@@ -53,33 +83,149 @@ func (mr *MockUserServiceInterfaceMockRecorder) GetUserByID(ctx, id interface{})
 }
 
 // Login mocks base method.
-func (m *MockUserServiceInterface) Login(ctx context.Context, login, password string) (*dto.LoginResponse, error) {
+func (m *MockUserServiceInterface) Login(ctx context.Context, login, password string, device dto.Device) (*dto.LoginResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", ctx, login, password)
+	ret := m.ctrl.Call(m, "Login", ctx, login, password, device)
 	ret0, _ := ret[0].(*dto.LoginResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockUserServiceInterfaceMockRecorder) Login(ctx, login, password interface{}) *gomock.Call {
+func (mr *MockUserServiceInterfaceMockRecorder) Login(ctx, login, password, device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserServiceInterface)(nil).Login), ctx, login, password, device)
+}
+
+// Logout mocks base method.
+func (m *MockUserServiceInterface) Logout(ctx context.Context, sessionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockUserServiceInterfaceMockRecorder) Logout(ctx, sessionID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockUserServiceInterface)(nil).Logout), ctx, sessionID)
+}
+
+// Refresh mocks base method.
+func (m *MockUserServiceInterface) Refresh(ctx context.Context, refreshToken string) (*dto.Tokens, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(*dto.Tokens)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockUserServiceInterfaceMockRecorder) Refresh(ctx, refreshToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUserServiceInterface)(nil).Login), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockUserServiceInterface)(nil).Refresh), ctx, refreshToken)
 }
 
 // Register mocks base method.
-func (m *MockUserServiceInterface) Register(ctx context.Context, login, password string) (*dto.RegisterResponse, error) {
+func (m *MockUserServiceInterface) Register(ctx context.Context, login, password string, device dto.Device) (*dto.RegisterResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Register", ctx, login, password)
+	ret := m.ctrl.Call(m, "Register", ctx, login, password, device)
 	ret0, _ := ret[0].(*dto.RegisterResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Register indicates an expected call of Register.
-func (mr *MockUserServiceInterfaceMockRecorder) Register(ctx, login, password interface{}) *gomock.Call {
+func (mr *MockUserServiceInterfaceMockRecorder) Register(ctx, login, password, device interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserServiceInterface)(nil).Register), ctx, login, password, device)
+}
+
+// RequestPasswordReset mocks base method.
+func (m *MockUserServiceInterface) RequestPasswordReset(ctx context.Context, loginOrEmail string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequestPasswordReset", ctx, loginOrEmail)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RequestPasswordReset indicates an expected call of RequestPasswordReset.
+func (mr *MockUserServiceInterfaceMockRecorder) RequestPasswordReset(ctx, loginOrEmail interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequestPasswordReset", reflect.TypeOf((*MockUserServiceInterface)(nil).RequestPasswordReset), ctx, loginOrEmail)
+}
+
+// ResetPassword mocks base method.
+func (m *MockUserServiceInterface) ResetPassword(ctx context.Context, token, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", ctx, token, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockUserServiceInterfaceMockRecorder) ResetPassword(ctx, token, password interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockUserServiceInterface)(nil).ResetPassword), ctx, token, password)
+}
+
+// RevokeOtherSessions mocks base method.
+func (m *MockUserServiceInterface) RevokeOtherSessions(ctx context.Context, userID, currentID uint) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeOtherSessions", ctx, userID, currentID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RevokeOtherSessions indicates an expected call of RevokeOtherSessions.
+func (mr *MockUserServiceInterfaceMockRecorder) RevokeOtherSessions(ctx, userID, currentID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeOtherSessions", reflect.TypeOf((*MockUserServiceInterface)(nil).RevokeOtherSessions), ctx, userID, currentID)
+}
+
+// RevokeSession mocks base method.
+func (m *MockUserServiceInterface) RevokeSession(ctx context.Context, userID, sessionID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeSession", ctx, userID, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeSession indicates an expected call of RevokeSession.
+func (mr *MockUserServiceInterfaceMockRecorder) RevokeSession(ctx, userID, sessionID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockUserServiceInterface)(nil).Register), ctx, login, password)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeSession", reflect.TypeOf((*MockUserServiceInterface)(nil).RevokeSession), ctx, userID, sessionID)
+}
+
+// SetEmail mocks base method.
+func (m *MockUserServiceInterface) SetEmail(ctx context.Context, userID uint, email string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetEmail", ctx, userID, email)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetEmail indicates an expected call of SetEmail.
+func (mr *MockUserServiceInterfaceMockRecorder) SetEmail(ctx, userID, email interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetEmail", reflect.TypeOf((*MockUserServiceInterface)(nil).SetEmail), ctx, userID, email)
+}
+
+// SetLocale mocks base method.
+func (m *MockUserServiceInterface) SetLocale(ctx context.Context, userID uint, locale string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLocale", ctx, userID, locale)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLocale indicates an expected call of SetLocale.
+func (mr *MockUserServiceInterfaceMockRecorder) SetLocale(ctx, userID, locale interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLocale", reflect.TypeOf((*MockUserServiceInterface)(nil).SetLocale), ctx, userID, locale)
 }
 
 // MockTravelServiceInterface is a mock of TravelServiceInterface interface.
@@ -106,18 +252,18 @@ func (m *MockTravelServiceInterface) EXPECT() *MockTravelServiceInterfaceMockRec
 }
 
 // CreateTravel mocks base method.
-func (m *MockTravelServiceInterface) CreateTravel(ctx context.Context, userID uint, title string, start, end time.Time) (*models.Travel, error) {
+func (m *MockTravelServiceInterface) CreateTravel(ctx context.Context, userID uint, title string, start, end time.Time, participants int, budget *float64, country string) (*models.Travel, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTravel", ctx, userID, title, start, end)
+	ret := m.ctrl.Call(m, "CreateTravel", ctx, userID, title, start, end, participants, budget, country)
 	ret0, _ := ret[0].(*models.Travel)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateTravel indicates an expected call of CreateTravel.
-func (mr *MockTravelServiceInterfaceMockRecorder) CreateTravel(ctx, userID, title, start, end interface{}) *gomock.Call {
+func (mr *MockTravelServiceInterfaceMockRecorder) CreateTravel(ctx, userID, title, start, end, participants, budget, country interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTravel", reflect.TypeOf((*MockTravelServiceInterface)(nil).CreateTravel), ctx, userID, title, start, end)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTravel", reflect.TypeOf((*MockTravelServiceInterface)(nil).CreateTravel), ctx, userID, title, start, end, participants, budget, country)
 }
 
 // GetTravelByID mocks base method.
@@ -135,6 +281,20 @@ func (mr *MockTravelServiceInterfaceMockRecorder) GetTravelByID(ctx, travelID in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTravelByID", reflect.TypeOf((*MockTravelServiceInterface)(nil).GetTravelByID), ctx, travelID)
 }
 
+// SetBudget mocks base method.
+func (m *MockTravelServiceInterface) SetBudget(ctx context.Context, travel *models.Travel, budget *float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBudget", ctx, travel, budget)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBudget indicates an expected call of SetBudget.
+func (mr *MockTravelServiceInterfaceMockRecorder) SetBudget(ctx, travel, budget interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBudget", reflect.TypeOf((*MockTravelServiceInterface)(nil).SetBudget), ctx, travel, budget)
+}
+
 // MockExpenseServiceInterface is a mock of ExpenseServiceInterface interface.
 type MockExpenseServiceInterface struct {
 	ctrl     *gomock.Controller
@@ -159,18 +319,18 @@ func (m *MockExpenseServiceInterface) EXPECT() *MockExpenseServiceInterfaceMockR
 }
 
 // CreateExpense mocks base method.
-func (m *MockExpenseServiceInterface) CreateExpense(ctx context.Context, expense *models.Expense) (*models.Expense, error) {
+func (m *MockExpenseServiceInterface) CreateExpense(ctx context.Context, expense *models.Expense, force bool) ([]uint, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateExpense", ctx, expense)
-	ret0, _ := ret[0].(*models.Expense)
+	ret := m.ctrl.Call(m, "CreateExpense", ctx, expense, force)
+	ret0, _ := ret[0].([]uint)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateExpense indicates an expected call of CreateExpense.
-func (mr *MockExpenseServiceInterfaceMockRecorder) CreateExpense(ctx, expense interface{}) *gomock.Call {
+func (mr *MockExpenseServiceInterfaceMockRecorder) CreateExpense(ctx, expense, force interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpense", reflect.TypeOf((*MockExpenseServiceInterface)(nil).CreateExpense), ctx, expense)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateExpense", reflect.TypeOf((*MockExpenseServiceInterface)(nil).CreateExpense), ctx, expense, force)
 }
 
 // DeleteExpense mocks base method.
@@ -187,6 +347,21 @@ func (mr *MockExpenseServiceInterfaceMockRecorder) DeleteExpense(ctx, id interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpense", reflect.TypeOf((*MockExpenseServiceInterface)(nil).DeleteExpense), ctx, id)
 }
 
+// FindDuplicates mocks base method.
+func (m *MockExpenseServiceInterface) FindDuplicates(ctx context.Context, expense *models.Expense) ([]models.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindDuplicates", ctx, expense)
+	ret0, _ := ret[0].([]models.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindDuplicates indicates an expected call of FindDuplicates.
+func (mr *MockExpenseServiceInterfaceMockRecorder) FindDuplicates(ctx, expense interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindDuplicates", reflect.TypeOf((*MockExpenseServiceInterface)(nil).FindDuplicates), ctx, expense)
+}
+
 // GetExpensesByUserID mocks base method.
 func (m *MockExpenseServiceInterface) GetExpensesByUserID(ctx context.Context, id uint) ([]models.Expense, error) {
 	m.ctrl.T.Helper()
@@ -202,6 +377,35 @@ func (mr *MockExpenseServiceInterfaceMockRecorder) GetExpensesByUserID(ctx, id i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpensesByUserID", reflect.TypeOf((*MockExpenseServiceInterface)(nil).GetExpensesByUserID), ctx, id)
 }
 
+// ReplacePerDiem mocks base method.
+func (m *MockExpenseServiceInterface) ReplacePerDiem(ctx context.Context, userID, travelID uint, entries []models.Expense) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplacePerDiem", ctx, userID, travelID, entries)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplacePerDiem indicates an expected call of ReplacePerDiem.
+func (mr *MockExpenseServiceInterfaceMockRecorder) ReplacePerDiem(ctx, userID, travelID, entries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplacePerDiem", reflect.TypeOf((*MockExpenseServiceInterface)(nil).ReplacePerDiem), ctx, userID, travelID, entries)
+}
+
+// ScanDuplicates mocks base method.
+func (m *MockExpenseServiceInterface) ScanDuplicates(ctx context.Context, userID, travelID uint) ([][]models.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ScanDuplicates", ctx, userID, travelID)
+	ret0, _ := ret[0].([][]models.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ScanDuplicates indicates an expected call of ScanDuplicates.
+func (mr *MockExpenseServiceInterfaceMockRecorder) ScanDuplicates(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ScanDuplicates", reflect.TypeOf((*MockExpenseServiceInterface)(nil).ScanDuplicates), ctx, userID, travelID)
+}
+
 // UpdateExpense mocks base method.
 func (m *MockExpenseServiceInterface) UpdateExpense(ctx context.Context, expense *models.Expense) error {
 	m.ctrl.T.Helper()
@@ -254,17 +458,17 @@ func (mr *MockCategoryServiceInterfaceMockRecorder) CreateCategory(ctx, category
 }
 
 // DeleteCategory mocks base method.
-func (m *MockCategoryServiceInterface) DeleteCategory(ctx context.Context, categoryID uint) error {
+func (m *MockCategoryServiceInterface) DeleteCategory(ctx context.Context, categoryID uint, children string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "DeleteCategory", ctx, categoryID)
+	ret := m.ctrl.Call(m, "DeleteCategory", ctx, categoryID, children)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // DeleteCategory indicates an expected call of DeleteCategory.
-func (mr *MockCategoryServiceInterfaceMockRecorder) DeleteCategory(ctx, categoryID interface{}) *gomock.Call {
+func (mr *MockCategoryServiceInterfaceMockRecorder) DeleteCategory(ctx, categoryID, children interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoryServiceInterface)(nil).DeleteCategory), ctx, categoryID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCategory", reflect.TypeOf((*MockCategoryServiceInterface)(nil).DeleteCategory), ctx, categoryID, children)
 }
 
 // GetCategoryByID mocks base method.
@@ -283,18 +487,91 @@ func (mr *MockCategoryServiceInterfaceMockRecorder) GetCategoryByID(ctx, id inte
 }
 
 // GetCategoryByName mocks base method.
-func (m *MockCategoryServiceInterface) GetCategoryByName(ctx context.Context, name string) (*models.Category, error) {
+func (m *MockCategoryServiceInterface) GetCategoryByName(ctx context.Context, userID uint, name string) (*models.Category, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCategoryByName", ctx, name)
+	ret := m.ctrl.Call(m, "GetCategoryByName", ctx, userID, name)
 	ret0, _ := ret[0].(*models.Category)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetCategoryByName indicates an expected call of GetCategoryByName.
-func (mr *MockCategoryServiceInterfaceMockRecorder) GetCategoryByName(ctx, name interface{}) *gomock.Call {
+func (mr *MockCategoryServiceInterfaceMockRecorder) GetCategoryByName(ctx, userID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByName", reflect.TypeOf((*MockCategoryServiceInterface)(nil).GetCategoryByName), ctx, userID, name)
+}
+
+// MergeCategory mocks base method.
+func (m *MockCategoryServiceInterface) MergeCategory(ctx context.Context, source *models.Category, targetID uint) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeCategory", ctx, source, targetID)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MergeCategory indicates an expected call of MergeCategory.
+func (mr *MockCategoryServiceInterfaceMockRecorder) MergeCategory(ctx, source, targetID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeCategory", reflect.TypeOf((*MockCategoryServiceInterface)(nil).MergeCategory), ctx, source, targetID)
+}
+
+// ReorderCategories mocks base method.
+func (m *MockCategoryServiceInterface) ReorderCategories(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReorderCategories", ctx, userID, categoryIDs)
+	ret0, _ := ret[0].([]models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReorderCategories indicates an expected call of ReorderCategories.
+func (mr *MockCategoryServiceInterfaceMockRecorder) ReorderCategories(ctx, userID, categoryIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReorderCategories", reflect.TypeOf((*MockCategoryServiceInterface)(nil).ReorderCategories), ctx, userID, categoryIDs)
+}
+
+// ResolveCategory mocks base method.
+func (m *MockCategoryServiceInterface) ResolveCategory(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResolveCategory", ctx, userID, categoryID, name)
+	ret0, _ := ret[0].(*models.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResolveCategory indicates an expected call of ResolveCategory.
+func (mr *MockCategoryServiceInterfaceMockRecorder) ResolveCategory(ctx, userID, categoryID, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveCategory", reflect.TypeOf((*MockCategoryServiceInterface)(nil).ResolveCategory), ctx, userID, categoryID, name)
+}
+
+// SetArchived mocks base method.
+func (m *MockCategoryServiceInterface) SetArchived(ctx context.Context, userID uint, category *models.Category, archived bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetArchived", ctx, userID, category, archived)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetArchived indicates an expected call of SetArchived.
+func (mr *MockCategoryServiceInterfaceMockRecorder) SetArchived(ctx, userID, category, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetArchived", reflect.TypeOf((*MockCategoryServiceInterface)(nil).SetArchived), ctx, userID, category, archived)
+}
+
+// UpdateCategory mocks base method.
+func (m *MockCategoryServiceInterface) UpdateCategory(ctx context.Context, category *models.Category, name string, parentID *uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCategory", ctx, category, name, parentID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCategory indicates an expected call of UpdateCategory.
+func (mr *MockCategoryServiceInterfaceMockRecorder) UpdateCategory(ctx, category, name, parentID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByName", reflect.TypeOf((*MockCategoryServiceInterface)(nil).GetCategoryByName), ctx, name)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCategory", reflect.TypeOf((*MockCategoryServiceInterface)(nil).UpdateCategory), ctx, category, name, parentID)
 }
 
 // MockAnalyticsServiceInterfase is a mock of AnalyticsServiceInterfase interface.
@@ -321,16 +598,821 @@ func (m *MockAnalyticsServiceInterfase) EXPECT() *MockAnalyticsServiceInterfaseM
 }
 
 // Aggregate mocks base method.
-func (m *MockAnalyticsServiceInterfase) Aggregate(ctx context.Context, userID, travelID uint, from, to time.Time) (*dto.AnalyticsResponse, error) {
+func (m *MockAnalyticsServiceInterfase) Aggregate(ctx context.Context, userID, travelID uint, from, to time.Time, granularity, tz string, loc i18n.Locale) (*dto.AnalyticsResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Aggregate", ctx, userID, travelID, from, to)
+	ret := m.ctrl.Call(m, "Aggregate", ctx, userID, travelID, from, to, granularity, tz, loc)
 	ret0, _ := ret[0].(*dto.AnalyticsResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Aggregate indicates an expected call of Aggregate.
-func (mr *MockAnalyticsServiceInterfaseMockRecorder) Aggregate(ctx, userID, travelID, from, to interface{}) *gomock.Call {
+func (mr *MockAnalyticsServiceInterfaseMockRecorder) Aggregate(ctx, userID, travelID, from, to, granularity, tz, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockAnalyticsServiceInterfase)(nil).Aggregate), ctx, userID, travelID, from, to, granularity, tz, loc)
+}
+
+// CompareTravels mocks base method.
+func (m *MockAnalyticsServiceInterfase) CompareTravels(ctx context.Context, userID uint, travelIDs []uint, loc i18n.Locale) (*dto.TravelComparisonResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompareTravels", ctx, userID, travelIDs, loc)
+	ret0, _ := ret[0].(*dto.TravelComparisonResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompareTravels indicates an expected call of CompareTravels.
+func (mr *MockAnalyticsServiceInterfaseMockRecorder) CompareTravels(ctx, userID, travelIDs, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompareTravels", reflect.TypeOf((*MockAnalyticsServiceInterfase)(nil).CompareTravels), ctx, userID, travelIDs, loc)
+}
+
+// MockReconciliationServiceInterface is a mock of ReconciliationServiceInterface interface.
+type MockReconciliationServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconciliationServiceInterfaceMockRecorder
+}
+
+// MockReconciliationServiceInterfaceMockRecorder is the mock recorder for MockReconciliationServiceInterface.
+type MockReconciliationServiceInterfaceMockRecorder struct {
+	mock *MockReconciliationServiceInterface
+}
+
+// NewMockReconciliationServiceInterface creates a new mock instance.
+func NewMockReconciliationServiceInterface(ctrl *gomock.Controller) *MockReconciliationServiceInterface {
+	mock := &MockReconciliationServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockReconciliationServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconciliationServiceInterface) EXPECT() *MockReconciliationServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// ConfirmMatch mocks base method.
+func (m *MockReconciliationServiceInterface) ConfirmMatch(ctx context.Context, match *models.ReconciliationMatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMatch", ctx, match)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConfirmMatch indicates an expected call of ConfirmMatch.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) ConfirmMatch(ctx, match interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMatch", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).ConfirmMatch), ctx, match)
+}
+
+// GetMatchByID mocks base method.
+func (m *MockReconciliationServiceInterface) GetMatchByID(ctx context.Context, matchID uint) (*models.ReconciliationMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMatchByID", ctx, matchID)
+	ret0, _ := ret[0].(*models.ReconciliationMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMatchByID indicates an expected call of GetMatchByID.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) GetMatchByID(ctx, matchID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMatchByID", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).GetMatchByID), ctx, matchID)
+}
+
+// GetMatches mocks base method.
+func (m *MockReconciliationServiceInterface) GetMatches(ctx context.Context, userID, travelID uint) ([]models.ReconciliationMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMatches", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.ReconciliationMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMatches indicates an expected call of GetMatches.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) GetMatches(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMatches", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).GetMatches), ctx, userID, travelID)
+}
+
+// GetUnreconciled mocks base method.
+func (m *MockReconciliationServiceInterface) GetUnreconciled(ctx context.Context, userID, travelID uint) ([]models.BankTransaction, []models.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnreconciled", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.BankTransaction)
+	ret1, _ := ret[1].([]models.Expense)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetUnreconciled indicates an expected call of GetUnreconciled.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) GetUnreconciled(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnreconciled", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).GetUnreconciled), ctx, userID, travelID)
+}
+
+// ImportTransactions mocks base method.
+func (m *MockReconciliationServiceInterface) ImportTransactions(ctx context.Context, transactions []models.BankTransaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportTransactions", ctx, transactions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ImportTransactions indicates an expected call of ImportTransactions.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) ImportTransactions(ctx, transactions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportTransactions", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).ImportTransactions), ctx, transactions)
+}
+
+// MergeMatch mocks base method.
+func (m *MockReconciliationServiceInterface) MergeMatch(ctx context.Context, match *models.ReconciliationMatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MergeMatch", ctx, match)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MergeMatch indicates an expected call of MergeMatch.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) MergeMatch(ctx, match interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeMatch", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).MergeMatch), ctx, match)
+}
+
+// ProposeMatches mocks base method.
+func (m *MockReconciliationServiceInterface) ProposeMatches(ctx context.Context, userID, travelID uint) ([]models.ReconciliationMatch, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProposeMatches", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.ReconciliationMatch)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProposeMatches indicates an expected call of ProposeMatches.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) ProposeMatches(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProposeMatches", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).ProposeMatches), ctx, userID, travelID)
+}
+
+// RejectMatch mocks base method.
+func (m *MockReconciliationServiceInterface) RejectMatch(ctx context.Context, match *models.ReconciliationMatch) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectMatch", ctx, match)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectMatch indicates an expected call of RejectMatch.
+func (mr *MockReconciliationServiceInterfaceMockRecorder) RejectMatch(ctx, match interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectMatch", reflect.TypeOf((*MockReconciliationServiceInterface)(nil).RejectMatch), ctx, match)
+}
+
+// MockExpenseReportServiceInterface is a mock of ExpenseReportServiceInterface interface.
+type MockExpenseReportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpenseReportServiceInterfaceMockRecorder
+}
+
+// MockExpenseReportServiceInterfaceMockRecorder is the mock recorder for MockExpenseReportServiceInterface.
+type MockExpenseReportServiceInterfaceMockRecorder struct {
+	mock *MockExpenseReportServiceInterface
+}
+
+// NewMockExpenseReportServiceInterface creates a new mock instance.
+func NewMockExpenseReportServiceInterface(ctrl *gomock.Controller) *MockExpenseReportServiceInterface {
+	mock := &MockExpenseReportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockExpenseReportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpenseReportServiceInterface) EXPECT() *MockExpenseReportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Approve mocks base method.
+func (m *MockExpenseReportServiceInterface) Approve(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Approve", ctx, report, actor, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Approve indicates an expected call of Approve.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) Approve(ctx, report, actor, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Approve", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).Approve), ctx, report, actor, comment)
+}
+
+// CommentLine mocks base method.
+func (m *MockExpenseReportServiceInterface) CommentLine(ctx context.Context, report *models.ExpenseReport, lineID uint, actor models.User, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CommentLine", ctx, report, lineID, actor, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CommentLine indicates an expected call of CommentLine.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) CommentLine(ctx, report, lineID, actor, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CommentLine", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).CommentLine), ctx, report, lineID, actor, comment)
+}
+
+// CreateReport mocks base method.
+func (m *MockExpenseReportServiceInterface) CreateReport(ctx context.Context, userID, travelID uint, title string, expenseIDs []uint) (*models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReport", ctx, userID, travelID, title, expenseIDs)
+	ret0, _ := ret[0].(*models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateReport indicates an expected call of CreateReport.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) CreateReport(ctx, userID, travelID, title, expenseIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReport", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).CreateReport), ctx, userID, travelID, title, expenseIDs)
+}
+
+// DeleteReport mocks base method.
+func (m *MockExpenseReportServiceInterface) DeleteReport(ctx context.Context, report *models.ExpenseReport, actor models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteReport", ctx, report, actor)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteReport indicates an expected call of DeleteReport.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) DeleteReport(ctx, report, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteReport", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).DeleteReport), ctx, report, actor)
+}
+
+// GetPendingReports mocks base method.
+func (m *MockExpenseReportServiceInterface) GetPendingReports(ctx context.Context, actor models.User) ([]models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingReports", ctx, actor)
+	ret0, _ := ret[0].([]models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingReports indicates an expected call of GetPendingReports.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) GetPendingReports(ctx, actor interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingReports", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).GetPendingReports), ctx, actor)
+}
+
+// GetReportByID mocks base method.
+func (m *MockExpenseReportServiceInterface) GetReportByID(ctx context.Context, reportID uint) (*models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportByID", ctx, reportID)
+	ret0, _ := ret[0].(*models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportByID indicates an expected call of GetReportByID.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) GetReportByID(ctx, reportID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportByID", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).GetReportByID), ctx, reportID)
+}
+
+// GetReportsByUserID mocks base method.
+func (m *MockExpenseReportServiceInterface) GetReportsByUserID(ctx context.Context, userID uint) ([]models.ExpenseReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReportsByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.ExpenseReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReportsByUserID indicates an expected call of GetReportsByUserID.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) GetReportsByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReportsByUserID", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).GetReportsByUserID), ctx, userID)
+}
+
+// MarkPaid mocks base method.
+func (m *MockExpenseReportServiceInterface) MarkPaid(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkPaid", ctx, report, actor, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkPaid indicates an expected call of MarkPaid.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) MarkPaid(ctx, report, actor, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkPaid", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).MarkPaid), ctx, report, actor, comment)
+}
+
+// Reject mocks base method.
+func (m *MockExpenseReportServiceInterface) Reject(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reject", ctx, report, actor, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reject indicates an expected call of Reject.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) Reject(ctx, report, actor, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reject", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).Reject), ctx, report, actor, comment)
+}
+
+// Submit mocks base method.
+func (m *MockExpenseReportServiceInterface) Submit(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, report, actor, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockExpenseReportServiceInterfaceMockRecorder) Submit(ctx, report, actor, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockExpenseReportServiceInterface)(nil).Submit), ctx, report, actor, comment)
+}
+
+// MockPolicyServiceInterface is a mock of PolicyServiceInterface interface.
+type MockPolicyServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockPolicyServiceInterfaceMockRecorder
+}
+
+// MockPolicyServiceInterfaceMockRecorder is the mock recorder for MockPolicyServiceInterface.
+type MockPolicyServiceInterfaceMockRecorder struct {
+	mock *MockPolicyServiceInterface
+}
+
+// NewMockPolicyServiceInterface creates a new mock instance.
+func NewMockPolicyServiceInterface(ctrl *gomock.Controller) *MockPolicyServiceInterface {
+	mock := &MockPolicyServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockPolicyServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPolicyServiceInterface) EXPECT() *MockPolicyServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreatePolicy mocks base method.
+func (m *MockPolicyServiceInterface) CreatePolicy(ctx context.Context, policy *models.SpendingPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePolicy", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePolicy indicates an expected call of CreatePolicy.
+func (mr *MockPolicyServiceInterfaceMockRecorder) CreatePolicy(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePolicy", reflect.TypeOf((*MockPolicyServiceInterface)(nil).CreatePolicy), ctx, policy)
+}
+
+// DeletePolicy mocks base method.
+func (m *MockPolicyServiceInterface) DeletePolicy(ctx context.Context, policy *models.SpendingPolicy) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePolicy", ctx, policy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePolicy indicates an expected call of DeletePolicy.
+func (mr *MockPolicyServiceInterfaceMockRecorder) DeletePolicy(ctx, policy interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePolicy", reflect.TypeOf((*MockPolicyServiceInterface)(nil).DeletePolicy), ctx, policy)
+}
+
+// EvaluateTravel mocks base method.
+func (m *MockPolicyServiceInterface) EvaluateTravel(ctx context.Context, userID, travelID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateTravel", ctx, userID, travelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvaluateTravel indicates an expected call of EvaluateTravel.
+func (mr *MockPolicyServiceInterfaceMockRecorder) EvaluateTravel(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateTravel", reflect.TypeOf((*MockPolicyServiceInterface)(nil).EvaluateTravel), ctx, userID, travelID)
+}
+
+// EvaluateUser mocks base method.
+func (m *MockPolicyServiceInterface) EvaluateUser(ctx context.Context, userID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EvaluateUser", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EvaluateUser indicates an expected call of EvaluateUser.
+func (mr *MockPolicyServiceInterfaceMockRecorder) EvaluateUser(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EvaluateUser", reflect.TypeOf((*MockPolicyServiceInterface)(nil).EvaluateUser), ctx, userID)
+}
+
+// GetCompliance mocks base method.
+func (m *MockPolicyServiceInterface) GetCompliance(ctx context.Context, userID, travelID uint) ([]models.PolicyViolation, int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCompliance", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.PolicyViolation)
+	ret1, _ := ret[1].(int)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetCompliance indicates an expected call of GetCompliance.
+func (mr *MockPolicyServiceInterfaceMockRecorder) GetCompliance(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCompliance", reflect.TypeOf((*MockPolicyServiceInterface)(nil).GetCompliance), ctx, userID, travelID)
+}
+
+// GetPoliciesByUserID mocks base method.
+func (m *MockPolicyServiceInterface) GetPoliciesByUserID(ctx context.Context, userID uint) ([]models.SpendingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPoliciesByUserID", ctx, userID)
+	ret0, _ := ret[0].([]models.SpendingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPoliciesByUserID indicates an expected call of GetPoliciesByUserID.
+func (mr *MockPolicyServiceInterfaceMockRecorder) GetPoliciesByUserID(ctx, userID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPoliciesByUserID", reflect.TypeOf((*MockPolicyServiceInterface)(nil).GetPoliciesByUserID), ctx, userID)
+}
+
+// GetPolicyByID mocks base method.
+func (m *MockPolicyServiceInterface) GetPolicyByID(ctx context.Context, policyID uint) (*models.SpendingPolicy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPolicyByID", ctx, policyID)
+	ret0, _ := ret[0].(*models.SpendingPolicy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPolicyByID indicates an expected call of GetPolicyByID.
+func (mr *MockPolicyServiceInterfaceMockRecorder) GetPolicyByID(ctx, policyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPolicyByID", reflect.TypeOf((*MockPolicyServiceInterface)(nil).GetPolicyByID), ctx, policyID)
+}
+
+// MockAllowanceServiceInterface is a mock of AllowanceServiceInterface interface.
+type MockAllowanceServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockAllowanceServiceInterfaceMockRecorder
+}
+
+// MockAllowanceServiceInterfaceMockRecorder is the mock recorder for MockAllowanceServiceInterface.
+type MockAllowanceServiceInterfaceMockRecorder struct {
+	mock *MockAllowanceServiceInterface
+}
+
+// NewMockAllowanceServiceInterface creates a new mock instance.
+func NewMockAllowanceServiceInterface(ctrl *gomock.Controller) *MockAllowanceServiceInterface {
+	mock := &MockAllowanceServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockAllowanceServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAllowanceServiceInterface) EXPECT() *MockAllowanceServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// CreateMileage mocks base method.
+func (m *MockAllowanceServiceInterface) CreateMileage(ctx context.Context, expense *models.Expense, rate float64, country, city string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMileage", ctx, expense, rate, country, city)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMileage indicates an expected call of CreateMileage.
+func (mr *MockAllowanceServiceInterfaceMockRecorder) CreateMileage(ctx, expense, rate, country, city interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMileage", reflect.TypeOf((*MockAllowanceServiceInterface)(nil).CreateMileage), ctx, expense, rate, country, city)
+}
+
+// GeneratePerDiem mocks base method.
+func (m *MockAllowanceServiceInterface) GeneratePerDiem(ctx context.Context, travel *models.Travel, country, city string, meals []models.MealsProvided, loc i18n.Locale) ([]models.Expense, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GeneratePerDiem", ctx, travel, country, city, meals, loc)
+	ret0, _ := ret[0].([]models.Expense)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GeneratePerDiem indicates an expected call of GeneratePerDiem.
+func (mr *MockAllowanceServiceInterfaceMockRecorder) GeneratePerDiem(ctx, travel, country, city, meals, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GeneratePerDiem", reflect.TypeOf((*MockAllowanceServiceInterface)(nil).GeneratePerDiem), ctx, travel, country, city, meals, loc)
+}
+
+// GetRates mocks base method.
+func (m *MockAllowanceServiceInterface) GetRates(ctx context.Context, country string) ([]models.PerDiemRate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRates", ctx, country)
+	ret0, _ := ret[0].([]models.PerDiemRate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRates indicates an expected call of GetRates.
+func (mr *MockAllowanceServiceInterfaceMockRecorder) GetRates(ctx, country interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRates", reflect.TypeOf((*MockAllowanceServiceInterface)(nil).GetRates), ctx, country)
+}
+
+// MockForecastServiceInterface is a mock of ForecastServiceInterface interface.
+type MockForecastServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockForecastServiceInterfaceMockRecorder
+}
+
+// MockForecastServiceInterfaceMockRecorder is the mock recorder for MockForecastServiceInterface.
+type MockForecastServiceInterfaceMockRecorder struct {
+	mock *MockForecastServiceInterface
+}
+
+// NewMockForecastServiceInterface creates a new mock instance.
+func NewMockForecastServiceInterface(ctrl *gomock.Controller) *MockForecastServiceInterface {
+	mock := &MockForecastServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockForecastServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockForecastServiceInterface) EXPECT() *MockForecastServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Forecast mocks base method.
+func (m *MockForecastServiceInterface) Forecast(ctx context.Context, travel *models.Travel, now time.Time, loc i18n.Locale) (*dto.ForecastResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Forecast", ctx, travel, now, loc)
+	ret0, _ := ret[0].(*dto.ForecastResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Forecast indicates an expected call of Forecast.
+func (mr *MockForecastServiceInterfaceMockRecorder) Forecast(ctx, travel, now, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Forecast", reflect.TypeOf((*MockForecastServiceInterface)(nil).Forecast), ctx, travel, now, loc)
+}
+
+// MockInsightServiceInterface is a mock of InsightServiceInterface interface.
+type MockInsightServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockInsightServiceInterfaceMockRecorder
+}
+
+// MockInsightServiceInterfaceMockRecorder is the mock recorder for MockInsightServiceInterface.
+type MockInsightServiceInterfaceMockRecorder struct {
+	mock *MockInsightServiceInterface
+}
+
+// NewMockInsightServiceInterface creates a new mock instance.
+func NewMockInsightServiceInterface(ctrl *gomock.Controller) *MockInsightServiceInterface {
+	mock := &MockInsightServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockInsightServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockInsightServiceInterface) EXPECT() *MockInsightServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetInsights mocks base method.
+func (m *MockInsightServiceInterface) GetInsights(ctx context.Context, userID uint, travelID *uint) ([]models.Insight, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInsights", ctx, userID, travelID)
+	ret0, _ := ret[0].([]models.Insight)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInsights indicates an expected call of GetInsights.
+func (mr *MockInsightServiceInterfaceMockRecorder) GetInsights(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInsights", reflect.TypeOf((*MockInsightServiceInterface)(nil).GetInsights), ctx, userID, travelID)
+}
+
+// RefreshTravel mocks base method.
+func (m *MockInsightServiceInterface) RefreshTravel(ctx context.Context, userID, travelID uint) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefreshTravel", ctx, userID, travelID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RefreshTravel indicates an expected call of RefreshTravel.
+func (mr *MockInsightServiceInterfaceMockRecorder) RefreshTravel(ctx, userID, travelID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefreshTravel", reflect.TypeOf((*MockInsightServiceInterface)(nil).RefreshTravel), ctx, userID, travelID)
+}
+
+// Schedule mocks base method.
+func (m *MockInsightServiceInterface) Schedule(userID uint, travelIDs ...uint) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{userID}
+	for _, a := range travelIDs {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Schedule", varargs...)
+}
+
+// Schedule indicates an expected call of Schedule.
+func (mr *MockInsightServiceInterfaceMockRecorder) Schedule(userID interface{}, travelIDs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{userID}, travelIDs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Schedule", reflect.TypeOf((*MockInsightServiceInterface)(nil).Schedule), varargs...)
+}
+
+// MockStatisticsServiceInterface is a mock of StatisticsServiceInterface interface.
+type MockStatisticsServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockStatisticsServiceInterfaceMockRecorder
+}
+
+// MockStatisticsServiceInterfaceMockRecorder is the mock recorder for MockStatisticsServiceInterface.
+type MockStatisticsServiceInterfaceMockRecorder struct {
+	mock *MockStatisticsServiceInterface
+}
+
+// NewMockStatisticsServiceInterface creates a new mock instance.
+func NewMockStatisticsServiceInterface(ctrl *gomock.Controller) *MockStatisticsServiceInterface {
+	mock := &MockStatisticsServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockStatisticsServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStatisticsServiceInterface) EXPECT() *MockStatisticsServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Statistics mocks base method.
+func (m *MockStatisticsServiceInterface) Statistics(ctx context.Context, userID uint, travelID *uint, top int, tz string, now time.Time, loc i18n.Locale) (*dto.StatisticsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Statistics", ctx, userID, travelID, top, tz, now, loc)
+	ret0, _ := ret[0].(*dto.StatisticsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Statistics indicates an expected call of Statistics.
+func (mr *MockStatisticsServiceInterfaceMockRecorder) Statistics(ctx, userID, travelID, top, tz, now, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Statistics", reflect.TypeOf((*MockStatisticsServiceInterface)(nil).Statistics), ctx, userID, travelID, top, tz, now, loc)
+}
+
+// MockReviewServiceInterface is a mock of ReviewServiceInterface interface.
+type MockReviewServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockReviewServiceInterfaceMockRecorder
+}
+
+// MockReviewServiceInterfaceMockRecorder is the mock recorder for MockReviewServiceInterface.
+type MockReviewServiceInterfaceMockRecorder struct {
+	mock *MockReviewServiceInterface
+}
+
+// NewMockReviewServiceInterface creates a new mock instance.
+func NewMockReviewServiceInterface(ctrl *gomock.Controller) *MockReviewServiceInterface {
+	mock := &MockReviewServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockReviewServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReviewServiceInterface) EXPECT() *MockReviewServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Review mocks base method.
+func (m *MockReviewServiceInterface) Review(ctx context.Context, userID uint, from, to time.Time, loc i18n.Locale) (*dto.ReviewResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, userID, from, to, loc)
+	ret0, _ := ret[0].(*dto.ReviewResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockReviewServiceInterfaceMockRecorder) Review(ctx, userID, from, to, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockReviewServiceInterface)(nil).Review), ctx, userID, from, to, loc)
+}
+
+// MockChartServiceInterface is a mock of ChartServiceInterface interface.
+type MockChartServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartServiceInterfaceMockRecorder
+}
+
+// MockChartServiceInterfaceMockRecorder is the mock recorder for MockChartServiceInterface.
+type MockChartServiceInterfaceMockRecorder struct {
+	mock *MockChartServiceInterface
+}
+
+// NewMockChartServiceInterface creates a new mock instance.
+func NewMockChartServiceInterface(ctrl *gomock.Controller) *MockChartServiceInterface {
+	mock := &MockChartServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockChartServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartServiceInterface) EXPECT() *MockChartServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// Render mocks base method.
+func (m *MockChartServiceInterface) Render(ctx context.Context, travel *models.Travel, kind string, req dto.ChartRequest, from, to time.Time, loc i18n.Locale) ([]byte, string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Render", ctx, travel, kind, req, from, to, loc)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(string)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Render indicates an expected call of Render.
+func (mr *MockChartServiceInterfaceMockRecorder) Render(ctx, travel, kind, req, from, to, loc interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Render", reflect.TypeOf((*MockChartServiceInterface)(nil).Render), ctx, travel, kind, req, from, to, loc)
+}
+
+// MockTripReportServiceInterface is a mock of TripReportServiceInterface interface.
+type MockTripReportServiceInterface struct {
+	ctrl     *gomock.Controller
+	recorder *MockTripReportServiceInterfaceMockRecorder
+}
+
+// MockTripReportServiceInterfaceMockRecorder is the mock recorder for MockTripReportServiceInterface.
+type MockTripReportServiceInterfaceMockRecorder struct {
+	mock *MockTripReportServiceInterface
+}
+
+// NewMockTripReportServiceInterface creates a new mock instance.
+func NewMockTripReportServiceInterface(ctrl *gomock.Controller) *MockTripReportServiceInterface {
+	mock := &MockTripReportServiceInterface{ctrl: ctrl}
+	mock.recorder = &MockTripReportServiceInterfaceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTripReportServiceInterface) EXPECT() *MockTripReportServiceInterfaceMockRecorder {
+	return m.recorder
+}
+
+// GetJob mocks base method.
+func (m *MockTripReportServiceInterface) GetJob(ctx context.Context, jobID uint, now time.Time) (*models.TripReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetJob", ctx, jobID, now)
+	ret0, _ := ret[0].(*models.TripReportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetJob indicates an expected call of GetJob.
+func (mr *MockTripReportServiceInterfaceMockRecorder) GetJob(ctx, jobID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetJob", reflect.TypeOf((*MockTripReportServiceInterface)(nil).GetJob), ctx, jobID, now)
+}
+
+// JobFile mocks base method.
+func (m *MockTripReportServiceInterface) JobFile(ctx context.Context, job *models.TripReportJob) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JobFile", ctx, job)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JobFile indicates an expected call of JobFile.
+func (mr *MockTripReportServiceInterfaceMockRecorder) JobFile(ctx, job interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JobFile", reflect.TypeOf((*MockTripReportServiceInterface)(nil).JobFile), ctx, job)
+}
+
+// Report mocks base method.
+func (m *MockTripReportServiceInterface) Report(ctx context.Context, travel *models.Travel, async bool, now time.Time, loc i18n.Locale) ([]byte, *models.TripReportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Report", ctx, travel, async, now, loc)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(*models.TripReportJob)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Report indicates an expected call of Report.
+func (mr *MockTripReportServiceInterfaceMockRecorder) Report(ctx, travel, async, now, loc interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Aggregate", reflect.TypeOf((*MockAnalyticsServiceInterfase)(nil).Aggregate), ctx, userID, travelID, from, to)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Report", reflect.TypeOf((*MockTripReportServiceInterface)(nil).Report), ctx, travel, async, now, loc)
 }
@@ -0,0 +1,26 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PerDiemRate — ставка суточных и пробега. Строка с пустым городом задаёт ставку по стране.
+type PerDiemRate struct {
+	gorm.Model
+	ID          uint    `gorm:"primaryKey"`
+	Country     string  `gorm:"size:2;not null;uniqueIndex:idx_per_diem_location"` // ISO 3166-1 alpha-2
+	City        string  `gorm:"not null;default:'';uniqueIndex:idx_per_diem_location"`
+	DailyRate   float64 `gorm:"not null"`
+	MileageRate float64 // за километр
+}
+
+// MealsProvided — питание, оплаченное за путешественника в указанный день.
+// Не хранится в БД, используется при расчёте суточных.
+type MealsProvided struct {
+	Date      time.Time
+	Breakfast bool
+	Lunch     bool
+	Dinner    bool
+}
@@ -4,13 +4,38 @@ import (
 	"gorm.io/gorm"
 )
 
+// Имя категории уникально среди встроенных и в пределах категорий одного пользователя
 type Category struct {
 	gorm.Model
 	ID      uint   `gorm:"primaryKey"`
-	Name    string `gorm:"unique;not null"`
-	UserID  *uint  // null → встроенная, не null → пользовательская
+	Name    string `gorm:"not null;uniqueIndex:idx_categories_user_name,where:deleted_at IS NULL;uniqueIndex:idx_categories_builtin_name,where:user_id IS NULL AND deleted_at IS NULL"`
+	UserID  *uint  `gorm:"uniqueIndex:idx_categories_user_name,where:deleted_at IS NULL"` // null → встроенная, не null → пользовательская
 	Builtin bool   `gorm:"default:false"`
+	// Языконезависимый ключ встроенной категории; название на языке клиента берётся из i18n
+	Key string `gorm:"size:64;index"`
+	// Родительская категория: встроенная или собственная категория пользователя
+	ParentID *uint `gorm:"index"`
 
-	User     *User     `gorm:"foreignKey:UserID"`
-	Expenses []Expense `gorm:"foreignKey:CategoryID"`
+	// Оформление: ключ иконки на фронтенде и цвет в формате #rrggbb
+	Icon  string `gorm:"size:32;not null;default:''"`
+	Color string `gorm:"size:7;not null;default:''"`
+	// Порядок в списках и признак архива. Для встроенных категорий значения
+	// конкретного пользователя хранятся в CategoryPreference.
+	SortOrder int  `gorm:"not null;default:0"`
+	Archived  bool `gorm:"not null;default:false"` // скрыта из выбора, но остаётся в истории и аналитике
+
+	User     *User      `gorm:"foreignKey:UserID"`
+	Parent   *Category  `gorm:"foreignKey:ParentID"`
+	Children []Category `gorm:"foreignKey:ParentID"`
+	Expenses []Expense  `gorm:"foreignKey:CategoryID"`
+}
+
+// CategoryPreference — порядок и архив встроенной категории для одного пользователя
+type CategoryPreference struct {
+	gorm.Model
+	ID         uint `gorm:"primaryKey"`
+	UserID     uint `gorm:"not null;uniqueIndex:idx_category_preference"`
+	CategoryID uint `gorm:"not null;uniqueIndex:idx_category_preference"`
+	SortOrder  int  `gorm:"not null;default:0"`
+	Archived   bool `gorm:"not null;default:false"`
 }
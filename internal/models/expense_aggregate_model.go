@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ExpenseAggregate — суммы записей поездки за день (UTC) в одной категории с разбивкой по виду.
+// Таблица пересчитывается в той же транзакции, что и изменение расходов, и служит
+// источником аналитики вместо запросов к expenses.
+type ExpenseAggregate struct {
+	TravelID   uint      `gorm:"primaryKey;autoIncrement:false"`
+	CategoryID uint      `gorm:"primaryKey;autoIncrement:false"`
+	Day        time.Time `gorm:"primaryKey;type:date"`
+	UserID     uint      `gorm:"not null;index"`
+	Gross      float64   `gorm:"not null;default:0"`
+	Refunds    float64   `gorm:"not null;default:0"`
+	Income     float64   `gorm:"not null;default:0"`
+	Allowances float64   `gorm:"not null;default:0"`
+	Count      int       `gorm:"not null;default:0"`
+}
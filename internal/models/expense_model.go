@@ -6,6 +6,18 @@ import (
 	"gorm.io/gorm"
 )
 
+type ExpenseKind string
+
+const (
+	KindExpense       ExpenseKind = "expense"
+	KindRefund        ExpenseKind = "refund"        // возврат денег за отменённую покупку, депозит
+	KindReimbursement ExpenseKind = "reimbursement" // компенсация расходов работодателем или попутчиком
+	KindIncome        ExpenseKind = "income"
+	KindPerDiem       ExpenseKind = "per_diem" // суточные, рассчитываются по ставке страны
+	KindMileage       ExpenseKind = "mileage"  // компенсация пробега: расстояние × ставка
+)
+
+// Сумма записи всегда положительна, направление задаётся видом записи
 type Expense struct {
 	gorm.Model
 	ID          uint    `gorm:"primaryKey"`
@@ -15,8 +27,35 @@ type Expense struct {
 	Amount      float64 `gorm:"not null"`
 	Description string
 	CreatedAt   time.Time
+	// У записи указано время, а не только дата. Дата без времени хранится как полночь UTC
+	// и не переводится в часовой пояс пользователя.
+	HasTime    bool        `gorm:"not null;default:false"`
+	Kind       ExpenseKind `gorm:"type:varchar(16);not null;default:'expense';index"`
+	RefundOfID *uint       `gorm:"index"` // для возврата — исходный расход
+	Distance   float64     // для пробега — расстояние в километрах
 
 	User     User     `gorm:"foreignKey:UserID"`
 	Travel   Travel   `gorm:"foreignKey:TravelID"`
 	Category Category `gorm:"foreignKey:CategoryID"`
+	RefundOf *Expense `gorm:"foreignKey:RefundOfID"`
+
+	Violations []PolicyViolation `gorm:"foreignKey:ExpenseID"`
+}
+
+func (k ExpenseKind) Valid() bool {
+	switch k {
+	case KindExpense, KindRefund, KindReimbursement, KindIncome, KindPerDiem, KindMileage:
+		return true
+	}
+	return false
+}
+
+// IsRefund — запись уменьшает траты (возврат или компенсация)
+func (k ExpenseKind) IsRefund() bool {
+	return k == KindRefund || k == KindReimbursement
+}
+
+// IsAllowance — запись начислена по нормативу, а не по чеку
+func (k ExpenseKind) IsAllowance() bool {
+	return k == KindPerDiem || k == KindMileage
 }
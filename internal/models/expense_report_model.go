@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ReportStatus string
+
+const (
+	ReportDraft     ReportStatus = "draft"
+	ReportSubmitted ReportStatus = "submitted"
+	ReportApproved  ReportStatus = "approved"
+	ReportRejected  ReportStatus = "rejected"
+	ReportPaid      ReportStatus = "paid"
+)
+
+var ReportStatuses = []ReportStatus{ReportDraft, ReportSubmitted, ReportApproved, ReportRejected, ReportPaid}
+
+// Locks — после утверждения расходы отчёта нельзя менять и удалять
+func (s ReportStatus) Locks() bool {
+	return s == ReportApproved || s == ReportPaid
+}
+
+// LockingStatuses — статусы, в которых отчёт блокирует свои расходы (см. Locks)
+func LockingStatuses() []ReportStatus {
+	var statuses []ReportStatus
+	for _, s := range ReportStatuses {
+		if s.Locks() {
+			statuses = append(statuses, s)
+		}
+	}
+	return statuses
+}
+
+// ExpenseReport — отчёт по командировке, собранный из возмещаемых расходов поездки
+type ExpenseReport struct {
+	gorm.Model
+	ID       uint         `gorm:"primaryKey"`
+	UserID   uint         `gorm:"not null;index"`
+	TravelID uint         `gorm:"not null;index"`
+	Title    string       `gorm:"not null"`
+	Status   ReportStatus `gorm:"type:varchar(16);not null;default:'draft';index"`
+
+	User    User                      `gorm:"foreignKey:UserID"`
+	Travel  Travel                    `gorm:"foreignKey:TravelID"`
+	Lines   []ExpenseReportLine       `gorm:"foreignKey:ReportID"`
+	History []ExpenseReportTransition `gorm:"foreignKey:ReportID"`
+}
+
+type ExpenseReportLine struct {
+	gorm.Model
+	ID              uint `gorm:"primaryKey"`
+	ReportID        uint `gorm:"not null;index"`
+	ExpenseID       uint `gorm:"not null;index"`
+	Comment         string
+	ApproverComment string
+
+	Expense Expense `gorm:"foreignKey:ExpenseID"`
+}
+
+// ExpenseReportTransition — запись истории смены статуса отчёта
+type ExpenseReportTransition struct {
+	ID         uint         `gorm:"primaryKey"`
+	ReportID   uint         `gorm:"not null;index"`
+	FromStatus ReportStatus `gorm:"type:varchar(16);not null"`
+	ToStatus   ReportStatus `gorm:"type:varchar(16);not null"`
+	ActorID    uint         `gorm:"not null"`
+	Comment    string
+	CreatedAt  time.Time
+
+	Actor User `gorm:"foreignKey:ActorID"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InsightType string
+
+const (
+	InsightOutlierExpense InsightType = "outlier_expense" // запись намного дороже обычной в своей категории
+	InsightUnusualDay     InsightType = "unusual_day"     // траты за день намного выше обычных
+	InsightCategoryShift  InsightType = "category_shift"  // доля категории заметно отличается от прошлых поездок
+)
+
+// Insight — замеченная особенность трат поездки. Пересчитывается при изменении расходов поездки.
+type Insight struct {
+	gorm.Model
+	ID         uint        `gorm:"primaryKey"`
+	UserID     uint        `gorm:"not null;index"`
+	TravelID   uint        `gorm:"not null;index"`
+	Type       InsightType `gorm:"type:varchar(32);not null"`
+	Severity   Severity    `gorm:"type:varchar(16);not null"`
+	ExpenseID  *uint       // для outlier_expense
+	CategoryID *uint       // null → все категории (дневная сумма)
+	Day        *time.Time  // для unusual_day
+	Value      float64     // наблюдаемое значение: сумма или доля категории
+	Baseline   float64     // обычное значение для сравнения
+	Ratio      float64     // Value / Baseline; 0, если сравнивать не с чем
+
+	Category *Category `gorm:"foreignKey:CategoryID"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PasswordResetToken — одноразовый код сброса пароля из письма. Хранится только
+// SHA-256 хеш; новый запрос сброса удаляет прежние коды пользователя.
+type PasswordResetToken struct {
+	gorm.Model
+	ID        uint      `gorm:"primaryKey"`
+	UserID    uint      `gorm:"not null;index"`
+	Hash      string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+}
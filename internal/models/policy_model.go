@@ -0,0 +1,68 @@
+package models
+
+import (
+	"gorm.io/gorm"
+)
+
+type PolicyRuleType string
+
+const (
+	RuleMaxPerExpense     PolicyRuleType = "max_per_expense"    // сумма одной записи выше лимита
+	RuleMaxPerDay         PolicyRuleType = "max_per_day"        // сумма за день выше лимита
+	RuleForbiddenCategory PolicyRuleType = "forbidden_category" // категория запрещена
+)
+
+func (t PolicyRuleType) Valid() bool {
+	switch t {
+	case RuleMaxPerExpense, RuleMaxPerDay, RuleForbiddenCategory:
+		return true
+	}
+	return false
+}
+
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+func (s Severity) Valid() bool {
+	switch s {
+	case SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+// SpendingPolicy — правило трат пользователя, общее или для одной поездки
+type SpendingPolicy struct {
+	gorm.Model
+	ID           uint           `gorm:"primaryKey"`
+	UserID       uint           `gorm:"not null;index"`
+	TravelID     *uint          `gorm:"index"` // null → действует во всех поездках пользователя
+	Name         string         `gorm:"not null"`
+	RuleType     PolicyRuleType `gorm:"type:varchar(32);not null"`
+	CategoryID   *uint          // null → любая категория
+	Limit        float64
+	RequiresNote bool     // превышение допустимо, если у расхода есть комментарий
+	Severity     Severity `gorm:"type:varchar(16);not null;default:'warning'"`
+
+	User     User      `gorm:"foreignKey:UserID"`
+	Category *Category `gorm:"foreignKey:CategoryID"`
+}
+
+// PolicyViolation — нарушение правила конкретным расходом
+type PolicyViolation struct {
+	gorm.Model
+	ID        uint     `gorm:"primaryKey"`
+	UserID    uint     `gorm:"not null;index"`
+	TravelID  uint     `gorm:"not null;index"`
+	ExpenseID uint     `gorm:"not null;index"`
+	PolicyID  uint     `gorm:"not null;index"`
+	Severity  Severity `gorm:"type:varchar(16);not null"`
+	Message   string
+
+	Policy SpendingPolicy `gorm:"foreignKey:PolicyID"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// BankTransaction — строка импортированной банковской выписки
+type BankTransaction struct {
+	gorm.Model
+	ID          uint      `gorm:"primaryKey"`
+	UserID      uint      `gorm:"not null;index"`
+	TravelID    uint      `gorm:"not null;index"`
+	Amount      float64   `gorm:"not null"`
+	Date        time.Time `gorm:"not null"`
+	Description string
+	ExpenseID   *uint `gorm:"index"` // null → операция ещё не сопоставлена с расходом
+
+	User    User     `gorm:"foreignKey:UserID"`
+	Travel  Travel   `gorm:"foreignKey:TravelID"`
+	Expense *Expense `gorm:"foreignKey:ExpenseID"`
+}
+
+type MatchStatus string
+
+const (
+	MatchProposed  MatchStatus = "proposed"
+	MatchConfirmed MatchStatus = "confirmed"
+	MatchRejected  MatchStatus = "rejected"
+)
+
+// ReconciliationMatch — предложенная пара "банковская операция ↔ расход"
+type ReconciliationMatch struct {
+	gorm.Model
+	ID            uint        `gorm:"primaryKey"`
+	UserID        uint        `gorm:"not null;index"`
+	TravelID      uint        `gorm:"not null;index"`
+	TransactionID uint        `gorm:"not null;index"`
+	ExpenseID     uint        `gorm:"not null;index"`
+	Score         float64     `gorm:"not null"`
+	Status        MatchStatus `gorm:"type:varchar(16);not null;default:'proposed'"`
+
+	Transaction BankTransaction `gorm:"foreignKey:TransactionID"`
+	Expense     Expense         `gorm:"foreignKey:ExpenseID"`
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Session — вход пользователя с устройства. Все токены обновления, выданные после входа,
+// образуют одно семейство; отзыв сессии делает недействительными и их, и токены доступа.
+type Session struct {
+	gorm.Model
+	ID        uint   `gorm:"primaryKey"`
+	UserID    uint   `gorm:"not null;index"`
+	UserAgent string `gorm:"size:512"`
+	IP        string `gorm:"size:64"`
+	// Время последнего запроса; обновляется не чаще раза в несколько минут
+	LastSeenAt *time.Time
+	RevokedAt  *time.Time
+}
+
+// RefreshToken — токен обновления. Хранится только SHA-256 хеш; токен одноразовый:
+// при обновлении он помечается использованным и взамен выдаётся следующий.
+type RefreshToken struct {
+	gorm.Model
+	ID        uint      `gorm:"primaryKey"`
+	SessionID uint      `gorm:"not null;index"`
+	Hash      string    `gorm:"size:64;not null;uniqueIndex"`
+	ExpiresAt time.Time `gorm:"not null"`
+	UsedAt    *time.Time
+
+	Session Session `gorm:"foreignKey:SessionID"`
+}
@@ -13,6 +13,12 @@ type Travel struct {
 	Title     string
 	StartDate time.Time
 	EndDate   time.Time
+	// Число путешественников, на которых делятся расходы в сравнении поездок
+	Participants int `gorm:"not null;default:1"`
+	// Бюджет поездки на чистые траты; nil — не задан
+	Budget *float64
+	// Страна назначения, ISO 3166-1 alpha-2; пусто — неизвестна
+	Country string `gorm:"size:2;not null;default:''"`
 
 	User     User      `gorm:"foreignKey:UserID"`
 	Expenses []Expense `gorm:"foreignKey:TravelID"`
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TripReportStatus string
+
+const (
+	TripReportPending TripReportStatus = "pending"
+	TripReportRunning TripReportStatus = "running"
+	TripReportDone    TripReportStatus = "done"
+	TripReportFailed  TripReportStatus = "failed"
+)
+
+// TripReportJob — фоновое построение PDF-отчёта по большой поездке. Готовый файл хранится
+// в записи до истечения срока и отдаётся по ID задания.
+type TripReportJob struct {
+	gorm.Model
+	ID         uint             `gorm:"primaryKey"`
+	UserID     uint             `gorm:"not null;index"`
+	TravelID   uint             `gorm:"not null;index"`
+	Status     TripReportStatus `gorm:"type:varchar(16);not null;default:'pending'"`
+	Locale     string           `gorm:"size:8"`
+	Error      string
+	File       []byte
+	FinishedAt *time.Time
+}
@@ -2,12 +2,27 @@ package models
 
 import "gorm.io/gorm"
 
+const (
+	RoleUser     = "user"
+	RoleApprover = "approver" // может утверждать отчёты других пользователей
+)
+
 type User struct {
 	gorm.Model
 	Login    string `gorm:"unique"`
 	Password string
+	Role     string `gorm:"type:varchar(16);not null;default:'user'"`
+	// Предпочитаемый язык ответов; пустой — по заголовку Accept-Language
+	Locale string `gorm:"size:8;not null;default:''"`
+	// Адрес для сброса пароля; хранится в нижнем регистре, пустой — не задан.
+	// Непустой адрес уникален: код сброса должен уходить владельцу единственной учётной записи.
+	Email string `gorm:"size:254;not null;default:'';uniqueIndex:idx_users_email_unique,where:email <> ''"`
 
 	Travels    []Travel   `gorm:"foreignKey:UserID"`
 	Categories []Category `gorm:"foreignKey:UserID"`
 	Expenses   []Expense  `gorm:"foreignKey:UserID"`
 }
+
+func (u User) IsApprover() bool {
+	return u.Role == RoleApprover
+}
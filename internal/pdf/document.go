@@ -0,0 +1,250 @@
+// Package pdf собирает простые PDF-документы без внешних зависимостей: текст встроенным
+// шрифтом, заливки, линии и векторные графики из пакета charts.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"strconv"
+	"time"
+	"wanderwallet/internal/charts"
+	"wanderwallet/internal/fonts"
+)
+
+// Размер страницы A4 в пунктах
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document — многостраничный документ; координаты страниц отсчитываются
+// от левого верхнего угла, как в charts.Canvas
+type Document struct {
+	Title   string
+	Created time.Time
+	pages   []*Page
+}
+
+func New(title string, created time.Time) *Document {
+	return &Document{Title: title, Created: created}
+}
+
+func (d *Document) AddPage() *Page {
+	p := &Page{glyphs: [2]glyphSet{{}, {}}}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+type Page struct {
+	content bytes.Buffer
+	glyphs  [2]glyphSet // использованные глифы обычного и полужирного шрифтов
+}
+
+func (p *Page) op(format string, args ...any) {
+	fmt.Fprintf(&p.content, format, args...)
+	p.content.WriteByte('\n')
+}
+
+// Text выводит строку; y — базовая линия
+func (p *Page) Text(x, y float64, text string, size float64, fill color.RGBA, bold bool) {
+	font, used := "F1", p.glyphs[0]
+	if bold {
+		font, used = "F2", p.glyphs[1]
+	}
+	p.op("BT %s rg /%s %s Tf %s %s Td <%X> Tj ET", rgb(fill), font, num(size), num(x), num(PageHeight-y), encode(fonts.Face(bold), text, used))
+}
+
+// TextRight выводит строку, выровненную по правому краю x
+func (p *Page) TextRight(x, y float64, text string, size float64, fill color.RGBA, bold bool) {
+	p.Text(x-TextWidth(text, size, bold), y, text, size, fill, bold)
+}
+
+func (p *Page) Rect(x, y, w, h float64, fill color.RGBA) {
+	p.op("%s rg %s %s %s %s re f", rgb(fill), num(x), num(PageHeight-y-h), num(w), num(h))
+}
+
+func (p *Page) Line(x1, y1, x2, y2, width float64, stroke color.RGBA) {
+	p.op("%s RG %s w [] 0 d %s %s m %s %s l S", rgb(stroke), num(width), num(x1), num(PageHeight-y1), num(x2), num(PageHeight-y2))
+}
+
+// Chart выводит холст графика в прямоугольник с левым верхним углом (x, y) и шириной width
+func (p *Page) Chart(c *charts.Canvas, x, y, width float64) {
+	scale := width / float64(c.Width)
+	p.Rect(x, y, width, float64(c.Height)*scale, c.Background)
+	p.op("q")
+	c.Draw(&chartDrawer{page: p, x: x, y: y, scale: scale, background: c.Background})
+	p.op("Q")
+}
+
+type chartDrawer struct {
+	page       *Page
+	x, y       float64
+	scale      float64
+	background color.RGBA
+}
+
+func (d *chartDrawer) point(pt charts.Point) (string, string) {
+	return num(d.x + pt.X*d.scale), num(PageHeight - d.y - pt.Y*d.scale)
+}
+
+func (d *chartDrawer) path(points []charts.Point) string {
+	var b bytes.Buffer
+	for i, pt := range points {
+		x, y := d.point(pt)
+		op := "l"
+		if i == 0 {
+			op = "m"
+		}
+		fmt.Fprintf(&b, "%s %s %s ", x, y, op)
+	}
+	return b.String()
+}
+
+// Polygon заливает многоугольник. Прозрачность в PDF требует отдельного графического
+// состояния, поэтому полупрозрачный цвет заранее смешивается с фоном графика.
+func (d *chartDrawer) Polygon(points []charts.Point, fill color.RGBA) {
+	if len(points) < 3 {
+		return
+	}
+	d.page.op("%s rg %sh f", rgb(blend(fill, d.background)), d.path(points))
+}
+
+func (d *chartDrawer) Line(points []charts.Point, width float64, stroke color.RGBA, dashed bool) {
+	if len(points) < 2 {
+		return
+	}
+	dash := "[]"
+	if dashed {
+		dash = fmt.Sprintf("[%s %s]", num(width*4*d.scale), num(width*3*d.scale))
+	}
+	d.page.op("%s RG %s w 1 J 1 j %s 0 d %sS", rgb(blend(stroke, d.background)), num(width*d.scale), dash, d.path(points))
+}
+
+func (d *chartDrawer) Text(at charts.Point, text string, size float64, fill color.RGBA, anchor charts.Anchor, bold bool) {
+	size *= d.scale
+	x := d.x + at.X*d.scale
+	switch anchor {
+	case charts.AnchorMiddle:
+		x -= TextWidth(text, size, bold) / 2
+	case charts.AnchorEnd:
+		x -= TextWidth(text, size, bold)
+	}
+	d.page.Text(x, d.y+at.Y*d.scale, text, size, blend(fill, d.background), bold)
+}
+
+// Bytes собирает документ: каталог, дерево страниц, сведения о документе, два шрифта
+// (см. fontObjects), затем по паре объектов (страница и её сжатое содержимое) на каждую страницу
+func (d *Document) Bytes() ([]byte, error) {
+	pages := d.pages
+	if len(pages) == 0 {
+		pages = []*Page{{}}
+	}
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"",
+		fmt.Sprintf("<< /Title <FEFF%X> /Producer (wanderwallet) /CreationDate (D:%s) >>",
+			utf16(d.Title), d.Created.UTC().Format("20060102150405Z")),
+	}
+	const regularObject = 4
+	const boldObject = regularObject + fontObjectCount
+	for i, name := range []string{"OpenSans", "OpenSans-Bold"} {
+		used := glyphSet{}
+		for _, p := range pages {
+			for g, r := range p.glyphs[i] {
+				used[g] = r
+			}
+		}
+		font, err := fontObjects(fonts.Face(i == 1), name, used, len(objects)+1)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, font...)
+	}
+
+	firstPageObject := len(objects) + 1
+	kids := make([]byte, 0, len(pages)*8)
+	for i, p := range pages {
+		pageObject := firstPageObject + 2*i
+		kids = append(kids, fmt.Sprintf("%d 0 R ", pageObject)...)
+		content, err := stream("", p.content.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] "+
+				"/Resources << /Font << /F1 %d 0 R /F2 %d 0 R >> >> /Contents %d 0 R >>",
+				num(PageWidth), num(PageHeight), regularObject, boldObject, pageObject+1),
+			content,
+		)
+	}
+	objects[1] = fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", bytes.TrimSpace(kids), len(pages))
+
+	var out bytes.Buffer
+	out.WriteString("%PDF-1.4\n%\xE2\xE3\xCF\xD3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = out.Len()
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return out.Bytes(), nil
+}
+
+// stream сжимает данные и оформляет их как поток; extra — дополнительные ключи словаря
+func stream(extra string, data []byte) (string, error) {
+	var compressed bytes.Buffer
+	w := zlib.NewWriter(&compressed)
+	if _, err := w.Write(data); err != nil {
+		return "", err
+	}
+	if err := w.Close(); err != nil {
+		return "", err
+	}
+	if extra != "" {
+		extra = " " + extra
+	}
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode%s >>\nstream\n%s\nendstream", compressed.Len(), extra, compressed.Bytes()), nil
+}
+
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func rgb(c color.RGBA) string {
+	return fmt.Sprintf("%s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255))
+}
+
+// blend накладывает цвет с альфа-каналом на непрозрачный фон
+func blend(c, background color.RGBA) color.RGBA {
+	if c.A == 255 {
+		return c
+	}
+	mix := func(fg, bg uint8) uint8 {
+		return uint8((int(fg)*int(c.A) + int(bg)*(255-int(c.A))) / 255)
+	}
+	return color.RGBA{R: mix(c.R, background.R), G: mix(c.G, background.G), B: mix(c.B, background.B), A: 255}
+}
+
+// utf16 кодирует строку для текстовых полей словаря сведений о документе
+func utf16(s string) []byte {
+	out := make([]byte, 0, len(s)*2)
+	for _, r := range s {
+		if r > 0xFFFF {
+			r = '?'
+		}
+		out = append(out, byte(r>>8), byte(r))
+	}
+	return out
+}
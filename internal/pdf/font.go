@@ -0,0 +1,158 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"wanderwallet/internal/fonts"
+)
+
+// Текст выводится встроенным шрифтом Open Sans (пакет fonts). В документ встраивается
+// подмножество шрифта с глифами, которые в нём встречаются, как CID-шрифт TrueType
+// с кодировкой Identity-H: строка кодируется двухбайтовыми номерами глифов. Таблица
+// ToUnicode позволяет копировать и искать текст. Символы, которых нет в шрифте, выводятся «?».
+
+// glyphSet — глифы шрифта, встретившиеся в документе, и символы, которые они выводят
+type glyphSet map[uint16]rune
+
+// encode переводит строку в номера глифов и отмечает их в used
+func encode(face *fonts.Font, text string, used glyphSet) []byte {
+	out := make([]byte, 0, 2*len(text))
+	for _, r := range text {
+		if !face.Has(r) {
+			r = '?'
+		}
+		g := face.Index(r)
+		if used != nil {
+			used[g] = r
+		}
+		out = append(out, byte(g>>8), byte(g))
+	}
+	return out
+}
+
+// TextWidth — ширина строки в пунктах
+func TextWidth(text string, size float64, bold bool) float64 {
+	return fonts.Face(bold).TextWidth(text, size)
+}
+
+// Fit укорачивает строку с многоточием, чтобы она помещалась в width
+func Fit(text string, width, size float64, bold bool) string {
+	if TextWidth(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		if candidate := string(runes) + "…"; TextWidth(candidate, size, bold) <= width {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// Wrap разбивает текст на строки не шире width по пробелам; слишком длинное слово укорачивается
+func Wrap(text string, width, size float64, bold bool) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+		if TextWidth(candidate, size, bold) <= width {
+			line = candidate
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+		line = Fit(word, width, size, bold)
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fontObjectCount — сколько объектов занимает один шрифт: Type0, CIDFont, описание
+// шрифта, файл шрифта и ToUnicode
+const fontObjectCount = 5
+
+// fontObjects возвращает объекты шрифта, начиная с номера first
+func fontObjects(face *fonts.Font, name string, used glyphSet, first int) ([]string, error) {
+	glyphs := make([]uint16, 0, len(used))
+	for g := range used {
+		glyphs = append(glyphs, g)
+	}
+	sort.Slice(glyphs, func(i, j int) bool { return glyphs[i] < glyphs[j] })
+
+	file, err := face.Subset(glyphs)
+	if err != nil {
+		return nil, err
+	}
+	fileStream, err := stream(fmt.Sprintf("/Length1 %d", len(file)), file)
+	if err != nil {
+		return nil, err
+	}
+	toUnicode, err := stream("", toUnicodeCMap(glyphs, used))
+	if err != nil {
+		return nil, err
+	}
+
+	// Подмножество помечается шестибуквенным префиксом, зависящим от набора глифов
+	h := fnv.New32a()
+	for _, g := range glyphs {
+		h.Write([]byte{byte(g >> 8), byte(g)})
+	}
+	tag := make([]byte, 6)
+	for i, sum := 0, h.Sum32(); i < len(tag); i, sum = i+1, sum/26 {
+		tag[i] = 'A' + byte(sum%26)
+	}
+	baseFont := fmt.Sprintf("%s+%s", tag, name)
+
+	scale := func(v int) int { return v * 1000 / face.UnitsPerEm }
+	var widths bytes.Buffer
+	for _, g := range glyphs {
+		fmt.Fprintf(&widths, "%d [%d] ", g, scale(face.Advance(g)))
+	}
+	stemV := 80
+	if face == fonts.Bold {
+		stemV = 140
+	}
+
+	return []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H "+
+			"/DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>", baseFont, first+1, first+4),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s "+
+			"/CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> "+
+			"/FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>", baseFont, first+2, bytes.TrimSpace(widths.Bytes())),
+		fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] "+
+			"/ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV %d /FontFile2 %d 0 R >>",
+			baseFont, scale(face.BBox[0]), scale(face.BBox[1]), scale(face.BBox[2]), scale(face.BBox[3]),
+			scale(face.Ascent), scale(face.Descent), scale(face.CapHeight), stemV, first+3),
+		fileStream,
+		toUnicode,
+	}, nil
+}
+
+// toUnicodeCMap сопоставляет номерам глифов символы; в одном блоке bfchar не больше 100 записей
+func toUnicodeCMap(glyphs []uint16, used glyphSet) []byte {
+	var b bytes.Buffer
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n" +
+		"1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	for start := 0; start < len(glyphs); start += 100 {
+		end := min(start+100, len(glyphs))
+		fmt.Fprintf(&b, "%d beginbfchar\n", end-start)
+		for _, g := range glyphs[start:end] {
+			fmt.Fprintf(&b, "<%04X> <%X>\n", g, utf16(string(used[g])))
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.Bytes()
+}
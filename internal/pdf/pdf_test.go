@@ -0,0 +1,89 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+	"wanderwallet/internal/charts"
+	"wanderwallet/internal/fonts"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	doc := New("Поездка", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	page := doc.AddPage()
+	page.Text(40, 60, "Итого: 100", 12, color.RGBA{A: 255}, true)
+	page.Rect(40, 70, 100, 20, color.RGBA{R: 200, A: 255})
+	theme, _ := charts.ThemeByName("light")
+	chart := charts.Pie(charts.Options{Width: 400, Height: 300, Theme: theme}, []charts.Value{{Label: "Еда", Value: 1}}, true)
+	doc.AddPage().Chart(chart, 40, 40, 300)
+
+	out, err := doc.Bytes()
+	assert.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(out, []byte("%PDF-1.4")))
+
+	// Смещения в таблице xref указывают на начала объектов
+	startxref := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	assert.True(t, bytes.HasPrefix(out[xref:], []byte("xref")))
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(out[xref:], -1)
+	assert.Len(t, offsets, 3+2*fontObjectCount+2*2)
+	objects := make([][]byte, len(offsets))
+	for i, m := range offsets {
+		offset, _ := strconv.Atoi(string(m[1]))
+		assert.True(t, bytes.HasPrefix(out[offset:], []byte(strconv.Itoa(i+1)+" 0 obj")), "object %d", i+1)
+		objects[i] = out[offset:]
+	}
+
+	// Содержимое первой страницы сжато и содержит текст в номерах глифов полужирного шрифта
+	text := encode(fonts.Bold, "Итого: 100", nil)
+	assert.Contains(t, string(streamData(t, objects[14])), fmt.Sprintf("/F2 12.00 Tf 40.00 781.89 Td <%X> Tj", text))
+
+	// Во второй шрифт встроено подмножество Open Sans с глифами заголовка
+	assert.Contains(t, string(objects[8]), "/Subtype /Type0")
+	assert.Contains(t, string(objects[10]), "/FontFile2 12 0 R")
+	font, err := fonts.Parse(streamData(t, objects[11]))
+	assert.NoError(t, err)
+	glyph, err := font.GlyphData(fonts.Bold.Index('И'))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, glyph)
+	assert.Contains(t, string(streamData(t, objects[12])), fmt.Sprintf("<%04X> <0418>", fonts.Bold.Index('И')))
+}
+
+// streamData распаковывает поток объекта
+func streamData(t *testing.T, object []byte) []byte {
+	m := regexp.MustCompile(`^\d+ 0 obj\n<< /Length (\d+) /Filter /FlateDecode[^>]*>>\nstream\n`).FindSubmatchIndex(object)
+	if !assert.NotNil(t, m) {
+		return nil
+	}
+	length, _ := strconv.Atoi(string(object[m[2]:m[3]]))
+	r, err := zlib.NewReader(bytes.NewReader(object[m[1] : m[1]+length]))
+	assert.NoError(t, err)
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	return data
+}
+
+func TestEncode(t *testing.T) {
+	used := glyphSet{}
+	out := encode(fonts.Regular, "AЯ☃", used)
+	a, ya, question := fonts.Regular.Index('A'), fonts.Regular.Index('Я'), fonts.Regular.Index('?')
+	assert.Equal(t, []byte{byte(a >> 8), byte(a), byte(ya >> 8), byte(ya), byte(question >> 8), byte(question)}, out)
+	assert.Equal(t, glyphSet{a: 'A', ya: 'Я', question: '?'}, used)
+}
+
+func TestWrap(t *testing.T) {
+	assert.Equal(t, "abc", Fit("abc", 100, 10, false))
+	assert.Equal(t, "ab…", Fit("abcdefgh", TextWidth("ab…", 10, false), 10, false))
+
+	lines := Wrap("один два три четыре", TextWidth("один два", 10, false), 10, false)
+	assert.Equal(t, []string{"один два", "три", "четыре"}, lines)
+	assert.Empty(t, Wrap("   ", 100, 10, false))
+}
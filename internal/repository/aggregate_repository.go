@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"time"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AggregateRepository отвечает на запросы аналитики по таблице expense_aggregates.
+// Агрегаты хранятся по дням UTC, поэтому граница to включает весь день, а суммы с рядом
+// в другом часовом поясе считаются по исходным записям.
+type AggregateRepository struct {
+	db   *gorm.DB
+	live *ExpenseRepository
+}
+
+func NewAggregateRepository(db *gorm.DB) *AggregateRepository {
+	return &AggregateRepository{db: db, live: &ExpenseRepository{db: db}}
+}
+
+const aggregateSummarySelect = `COALESCE(SUM(expense_aggregates.gross), 0) as gross,
+	COALESCE(SUM(expense_aggregates.refunds), 0) as refunds,
+	COALESCE(SUM(expense_aggregates.income), 0) as income,
+	COALESCE(SUM(expense_aggregates.allowances), 0) as allowances`
+
+// aggregateScope отбирает агрегаты пользователя в поездке за период; travelID == 0 — во всех поездках
+func aggregateScope(userID uint, travelID uint, from, to *time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("expense_aggregates.user_id = ?", userID)
+		if travelID != 0 {
+			db = db.Where("expense_aggregates.travel_id = ?", travelID)
+		}
+		if from != nil {
+			db = db.Where("expense_aggregates.day >= ?", from.UTC().Format("2006-01-02"))
+		}
+		if to != nil {
+			db = db.Where("expense_aggregates.day <= ?", to.UTC().Format("2006-01-02"))
+		}
+		return db
+	}
+}
+
+func (r *AggregateRepository) SumByTravelAndCategory(ctx context.Context, userID uint) ([]TravelCategorySummary, error) {
+	var results []TravelCategorySummary
+	err := r.db.WithContext(ctx).Table("expense_aggregates").
+		Select("expense_aggregates.travel_id, expense_aggregates.category_id, categories.name as category, categories.key as category_key, " + aggregateSummarySelect).
+		Joins("LEFT JOIN categories ON expense_aggregates.category_id = categories.id").
+		Scopes(aggregateScope(userID, 0, nil, nil)).
+		Group("expense_aggregates.travel_id, expense_aggregates.category_id, categories.name, categories.key").
+		Scan(&results).Error
+	return results, err
+}
+
+// Summarize считает все суммы аналитики одним запросом по дневным агрегатам. Дни агрегатов
+// совпадают с местными только в UTC, поэтому для другого часового пояса запрос идёт к expenses.
+func (r *AggregateRepository) Summarize(ctx context.Context, q SummaryQuery) (*AnalyticsSums, error) {
+	if q.Timezone != "UTC" {
+		return r.live.Summarize(ctx, q)
+	}
+	source := r.db.Table("expense_aggregates").
+		Select(`expense_aggregates.travel_id, expense_aggregates.category_id,
+			to_char(date_trunc(?, expense_aggregates.day), 'YYYY-MM-DD') AS start,
+			expense_aggregates.gross, expense_aggregates.refunds, expense_aggregates.income, expense_aggregates.allowances`, q.Unit).
+		Scopes(aggregateScope(q.UserID, q.TravelID, q.From, q.To))
+	return summarize(r.db.WithContext(ctx), source, q.ByTravel)
+}
+
+// RebuildAggregates пересчитывает все агрегаты по исходным записям. Таблица блокируется
+// от изменений до конца пересчёта; записи, сохраняемые параллельно, дождутся его
+// и пересчитают свои дни сами.
+func (r *AggregateRepository) RebuildAggregates(ctx context.Context) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE expense_aggregates IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM expense_aggregates").Error; err != nil {
+			return err
+		}
+		return tx.Exec(aggregateInsert + " GROUP BY user_id, travel_id, category_id, day").Error
+	})
+}
+
+const aggregateInsert = `INSERT INTO expense_aggregates (travel_id, category_id, day, user_id, gross, refunds, income, allowances, count)
+	SELECT expenses.travel_id, expenses.category_id, (expenses.created_at AT TIME ZONE 'UTC')::date AS day, expenses.user_id,
+	` + amountSummarySelect + `, COUNT(*)
+	FROM expenses
+	WHERE expenses.deleted_at IS NULL`
+
+// syncAggregates пересчитывает агрегаты поездки за дни days (UTC) по записям в expenses;
+// без days — за всю поездку. Вызывается в транзакции, изменившей записи. Строка поездки
+// блокируется, чтобы параллельные изменения одной поездки пересчитывались по очереди.
+func syncAggregates(tx *gorm.DB, travelID uint, days ...time.Time) error {
+	if err := tx.Model(&models.Travel{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", travelID).
+		Find(&[]models.Travel{}).Error; err != nil {
+		return err
+	}
+
+	dates := make([]string, 0, len(days))
+	for _, d := range days {
+		dates = append(dates, d.UTC().Format("2006-01-02"))
+	}
+
+	remove := tx.Where("travel_id = ?", travelID)
+	insert := aggregateInsert + " AND expenses.travel_id = ?"
+	args := []interface{}{travelID}
+	if len(dates) > 0 {
+		remove = remove.Where("day IN ?", dates)
+		insert += " AND (expenses.created_at AT TIME ZONE 'UTC')::date IN ?"
+		args = append(args, dates)
+	}
+	if err := remove.Delete(&models.ExpenseAggregate{}).Error; err != nil {
+		return err
+	}
+	return tx.Exec(insert+" GROUP BY user_id, travel_id, category_id, day", args...).Error
+}
+
+// syncExpenseChange пересчитывает агрегаты дней, затронутых изменением записи:
+// до изменения (before, может быть nil) и после (after, может быть nil)
+func syncExpenseChange(tx *gorm.DB, before, after *models.Expense) error {
+	days := make(map[uint][]time.Time)
+	var travelIDs []uint
+	for _, e := range []*models.Expense{before, after} {
+		if e == nil {
+			continue
+		}
+		if _, ok := days[e.TravelID]; !ok {
+			travelIDs = append(travelIDs, e.TravelID)
+		}
+		days[e.TravelID] = append(days[e.TravelID], e.CreatedAt)
+	}
+	// Поездки блокируются по возрастанию ID, чтобы встречные переносы записей не взаимоблокировались
+	sort.Slice(travelIDs, func(i, j int) bool { return travelIDs[i] < travelIDs[j] })
+	for _, travelID := range travelIDs {
+		if err := syncAggregates(tx, travelID, days[travelID]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
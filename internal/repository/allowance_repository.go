@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AllowanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAllowanceRepository(db *gorm.DB) AllowanceRepositoryInterface {
+	return &AllowanceRepository{db: db}
+}
+
+// UpsertRates добавляет ставки или обновляет существующие для той же страны и города
+func (r *AllowanceRepository) UpsertRates(ctx context.Context, rates []models.PerDiemRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "country"}, {Name: "city"}},
+		DoUpdates: clause.AssignmentColumns([]string{"daily_rate", "mileage_rate", "updated_at"}),
+	}).Create(&rates).Error
+}
+
+func (r *AllowanceRepository) GetRates(ctx context.Context, country string) ([]models.PerDiemRate, error) {
+	var rates []models.PerDiemRate
+	query := r.db.WithContext(ctx).Order("country, city")
+	if country != "" {
+		query = query.Where("country = ?", country)
+	}
+	err := query.Find(&rates).Error
+	return rates, err
+}
+
+// FindRate возвращает ставку города, а если её нет — ставку страны
+func (r *AllowanceRepository) FindRate(ctx context.Context, country string, city string) (*models.PerDiemRate, error) {
+	var rate models.PerDiemRate
+	err := r.db.WithContext(ctx).
+		Where("country = ? AND city = ?", country, city).
+		First(&rate).Error
+	if err == nil || city == "" || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return &rate, err
+	}
+	err = r.db.WithContext(ctx).
+		Where("country = ? AND city = ''", country).
+		First(&rate).Error
+	return &rate, err
+}
@@ -3,9 +3,11 @@ package repository
 import (
 	"context"
 	"errors"
+	"sort"
 	"wanderwallet/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CategoryRepository struct {
@@ -24,30 +26,216 @@ func (r *CategoryRepository) GetCategoryByID(ctx context.Context, id uint) (*mod
 	return &category, err
 }
 
-func (r *CategoryRepository) GetCategoryByName(ctx context.Context, name string) (*models.Category, error) {
+// GetCategoryByName ищет категорию среди встроенных и собственных категорий пользователя.
+// При совпадении имён собственная категория имеет приоритет.
+func (r *CategoryRepository) GetCategoryByName(ctx context.Context, userID uint, name string) (*models.Category, error) {
 	var category models.Category
-	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&category).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Where("name = ? AND (user_id = ? OR user_id IS NULL)", name, userID).
+		Order("user_id NULLS LAST").
+		First(&category).Error; err != nil {
 		return nil, err
 	}
 	return &category, nil
 }
 
+// GetCategoryByKey ищет встроенную категорию по языконезависимому ключу
+func (r *CategoryRepository) GetCategoryByKey(ctx context.Context, key string) (*models.Category, error) {
+	var category models.Category
+	if err := r.db.WithContext(ctx).
+		Where("key = ? AND user_id IS NULL", key).
+		First(&category).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// GetAllCategories возвращает встроенные и собственные категории пользователя,
+// включая архивные, с учётом его порядка и архива встроенных категорий.
 func (r *CategoryRepository) GetAllCategories(ctx context.Context, userID uint) ([]models.Category, error) {
 	var categories []models.Category
-	if err := r.db.WithContext(ctx).Where(" builtin = true OR user_id = ?", userID).Find(&categories).Error; err != nil {
+	if err := r.db.WithContext(ctx).Where(" builtin = true OR user_id = ?", userID).Order("id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	var prefs []models.CategoryPreference
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&prefs).Error; err != nil {
 		return nil, err
 	}
+	byCategory := make(map[uint]models.CategoryPreference, len(prefs))
+	for _, p := range prefs {
+		byCategory[p.CategoryID] = p
+	}
+	for i, c := range categories {
+		if p, ok := byCategory[c.ID]; ok && c.UserID == nil {
+			categories[i].SortOrder = p.SortOrder
+			categories[i].Archived = p.Archived
+		}
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return categories[i].SortOrder < categories[j].SortOrder
+	})
 	return categories, nil
 }
 
 func (r *CategoryRepository) CreateCategory(ctx context.Context, category *models.Category) error {
-	var existing models.Category
-	if err := r.db.WithContext(ctx).Where("user_id = ? AND name = ?", category.UserID, category.Name).First(&existing).Error; err == nil {
+	taken, err := r.nameTaken(ctx, category)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrCategoryExists
+	}
+	if category.SortOrder == 0 && category.UserID != nil {
+		// новая категория встаёт в конец списка пользователя
+		if err := r.db.WithContext(ctx).Raw(
+			`SELECT COALESCE(MAX(sort_order), 0) + 1 FROM (
+				SELECT sort_order FROM categories WHERE user_id = ? AND deleted_at IS NULL
+				UNION ALL
+				SELECT sort_order FROM category_preferences WHERE user_id = ? AND deleted_at IS NULL
+			) orders`, *category.UserID, *category.UserID).
+			Scan(&category.SortOrder).Error; err != nil {
+			return err
+		}
+	}
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, category *models.Category) error {
+	taken, err := r.nameTaken(ctx, category)
+	if err != nil {
+		return err
+	}
+	if taken {
 		return ErrCategoryExists
 	}
-	return r.db.Create(category).Error
+	return r.db.WithContext(ctx).Save(category).Error
 }
 
+func (r *CategoryRepository) GetChildCategories(ctx context.Context, parentID uint) ([]models.Category, error) {
+	var categories []models.Category
+	err := r.db.WithContext(ctx).Where("parent_id = ?", parentID).Order("name").Find(&categories).Error
+	return categories, err
+}
+
+// DeleteCategory удаляет категорию, а её подкатегории переносит к её родителю
 func (r *CategoryRepository) DeleteCategory(ctx context.Context, categoryID uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Category{}, categoryID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category models.Category
+		if err := tx.Where("id = ?", categoryID).First(&category).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).
+			Where("parent_id = ?", categoryID).
+			Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+}
+
+// MergeCategory переносит в target расходы, правила трат и подкатегории source
+// и удаляет source. Родитель target сохраняется из переданной модели: это нужно,
+// когда target был потомком source. Если какой-то расход source входит в утверждённый
+// или оплаченный отчёт, слияние не выполняется и возвращается ErrExpenseLocked.
+func (r *CategoryRepository) MergeCategory(ctx context.Context, source *models.Category, target *models.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked int64
+		if err := lockedLines(tx).
+			Joins("JOIN expenses ON expenses.id = expense_report_lines.expense_id").
+			Where("expenses.category_id = ?", source.ID).
+			Count(&locked).Error; err != nil {
+			return err
+		}
+		if locked > 0 {
+			return ErrExpenseLocked
+		}
+		if err := tx.Model(&models.Category{}).
+			Where("id = ?", target.ID).
+			Update("parent_id", target.ParentID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Category{}).
+			Where("parent_id = ? AND id <> ?", source.ID, target.ID).
+			Update("parent_id", target.ID).Error; err != nil {
+			return err
+		}
+		var travelIDs []uint
+		if err := tx.Model(&models.Expense{}).
+			Where("category_id = ?", source.ID).
+			Distinct().Order("travel_id").
+			Pluck("travel_id", &travelIDs).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.Expense{}).
+			Where("category_id = ?", source.ID).
+			Update("category_id", target.ID).Error; err != nil {
+			return err
+		}
+		for _, travelID := range travelIDs {
+			if err := syncAggregates(tx, travelID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Unscoped().Model(&models.SpendingPolicy{}).
+			Where("category_id = ?", source.ID).
+			Update("category_id", target.ID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Category{}, source.ID).Error
+	})
+}
+
+// SetCategoryOrder нумерует категории пользователя в переданном порядке начиная с 1
+func (r *CategoryRepository) SetCategoryOrder(ctx context.Context, userID uint, categories []models.Category) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i, c := range categories {
+			if c.UserID != nil {
+				if err := tx.Model(&models.Category{}).Where("id = ?", c.ID).Update("sort_order", i+1).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := upsertPreference(tx, models.CategoryPreference{UserID: userID, CategoryID: c.ID, SortOrder: i + 1}, "sort_order"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SetCategoryArchived архивирует категорию или возвращает её из архива.
+// Встроенная категория архивируется только для пользователя userID.
+func (r *CategoryRepository) SetCategoryArchived(ctx context.Context, userID uint, category *models.Category, archived bool) error {
+	if category.UserID != nil {
+		return r.db.WithContext(ctx).Model(&models.Category{}).Where("id = ?", category.ID).Update("archived", archived).Error
+	}
+	pref := models.CategoryPreference{UserID: userID, CategoryID: category.ID, SortOrder: category.SortOrder, Archived: archived}
+	return upsertPreference(r.db.WithContext(ctx), pref, "archived")
+}
+
+func upsertPreference(db *gorm.DB, pref models.CategoryPreference, column string) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{column, "updated_at"}),
+	}).Create(&pref).Error
+}
+
+func (r *CategoryRepository) DeleteCategories(ctx context.Context, categoryIDs []uint) error {
+	return r.db.WithContext(ctx).Where("id IN ?", categoryIDs).Delete(&models.Category{}).Error
+}
+
+// nameTaken — имя уже занято встроенной категорией или другой категорией того же пользователя
+func (r *CategoryRepository) nameTaken(ctx context.Context, category *models.Category) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&models.Category{}).
+		Where("name = ? AND id <> ?", category.Name, category.ID)
+	if category.UserID == nil {
+		query = query.Where("user_id IS NULL")
+	} else {
+		query = query.Where("user_id = ? OR user_id IS NULL", *category.UserID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
 }
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ExpenseReportRepository struct {
+	db *gorm.DB
+}
+
+func NewExpenseReportRepository(db *gorm.DB) ExpenseReportRepositoryInterface {
+	return &ExpenseReportRepository{db: db}
+}
+
+func (r *ExpenseReportRepository) CreateReport(ctx context.Context, report *models.ExpenseReport) error {
+	return r.db.WithContext(ctx).Omit("Lines.Expense").Create(report).Error
+}
+
+func (r *ExpenseReportRepository) GetReportByID(ctx context.Context, reportID uint) (*models.ExpenseReport, error) {
+	var report models.ExpenseReport
+	err := r.db.WithContext(ctx).
+		Preload("Lines.Expense.Category").
+		Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("created_at") }).
+		Where("id = ?", reportID).
+		First(&report).Error
+	return &report, err
+}
+
+func (r *ExpenseReportRepository) GetReportsByUserID(ctx context.Context, userID uint) ([]models.ExpenseReport, error) {
+	var reports []models.ExpenseReport
+	err := r.db.WithContext(ctx).
+		Preload("Lines.Expense.Category").
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *ExpenseReportRepository) GetReportsByStatus(ctx context.Context, status models.ReportStatus) ([]models.ExpenseReport, error) {
+	var reports []models.ExpenseReport
+	err := r.db.WithContext(ctx).
+		Preload("Lines.Expense.Category").
+		Where("status = ?", status).
+		Order("updated_at").
+		Find(&reports).Error
+	return reports, err
+}
+
+// GetReportedExpenseIDs возвращает расходы поездки, уже включённые в неотклонённые отчёты
+func (r *ExpenseReportRepository) GetReportedExpenseIDs(ctx context.Context, travelID uint) ([]uint, error) {
+	var ids []uint
+	err := r.db.WithContext(ctx).Model(&models.ExpenseReportLine{}).
+		Joins("JOIN expense_reports ON expense_reports.id = expense_report_lines.report_id AND expense_reports.deleted_at IS NULL").
+		Where("expense_reports.travel_id = ? AND expense_reports.status <> ?", travelID, models.ReportRejected).
+		Pluck("expense_report_lines.expense_id", &ids).Error
+	return ids, err
+}
+
+var ErrInvalidTransition = errors.New("report status transition is not allowed")
+
+// UpdateStatus меняет статус отчёта и записывает переход в историю в одной транзакции.
+// Статус меняется, только если отчёт всё ещё в transition.FromStatus: из двух одновременных
+// переходов выполняется один, второй получает ErrInvalidTransition.
+func (r *ExpenseReportRepository) UpdateStatus(ctx context.Context, reportID uint, transition *models.ExpenseReportTransition) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.ExpenseReport{}).
+			Where("id = ? AND status = ?", reportID, transition.FromStatus).
+			Update("status", transition.ToStatus)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrInvalidTransition
+		}
+		return tx.Create(transition).Error
+	})
+}
+
+func (r *ExpenseReportRepository) UpdateLine(ctx context.Context, line *models.ExpenseReportLine) error {
+	return r.db.WithContext(ctx).Model(line).
+		Select("comment", "approver_comment").
+		Updates(line).Error
+}
+
+func (r *ExpenseReportRepository) DeleteReport(ctx context.Context, reportID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("report_id = ?", reportID).Delete(&models.ExpenseReportLine{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.ExpenseReport{}, reportID).Error
+	})
+}
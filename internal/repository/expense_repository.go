@@ -2,10 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 	"wanderwallet/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ExpenseRepository struct {
@@ -17,7 +19,12 @@ func NewExpenseRepository(db *gorm.DB) ExpenseRepositoryInterface {
 }
 
 func (r *ExpenseRepository) CreateExpense(ctx context.Context, expense *models.Expense) error {
-	return r.db.WithContext(ctx).Create(expense).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(expense).Error; err != nil {
+			return err
+		}
+		return syncExpenseChange(tx, nil, expense)
+	})
 }
 
 func (r *ExpenseRepository) GetExpenseByID(ctx context.Context, expenseID uint) (*models.Expense, error) {
@@ -33,6 +40,90 @@ func (r *ExpenseRepository) GetExpensesByUserID(ctx context.Context, userID uint
 
 }
 
+func (r *ExpenseRepository) GetExpensesByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.Expense, error) {
+	var expenses []models.Expense
+	err := r.db.WithContext(ctx).
+		Preload("Category").
+		Preload("Violations").
+		Where("user_id = ? AND travel_id = ?", userID, travelID).
+		Order("created_at").
+		Find(&expenses).Error
+	return expenses, err
+}
+
+// ErrExpenseLocked — расход входит в утверждённый или оплаченный отчёт и не меняется
+var ErrExpenseLocked = errors.New("expense is locked by an approved report")
+
+// GetReportStatuses возвращает статусы отчётов, в которые входит расход
+func (r *ExpenseRepository) GetReportStatuses(ctx context.Context, expenseID uint) ([]models.ReportStatus, error) {
+	var statuses []models.ReportStatus
+	err := reportLines(r.db.WithContext(ctx)).
+		Where("expense_report_lines.expense_id = ?", expenseID).
+		Pluck("expense_reports.status", &statuses).Error
+	return statuses, err
+}
+
+// lockedLines — строки отчётов, блокирующих свои расходы
+func lockedLines(db *gorm.DB) *gorm.DB {
+	return reportLines(db).
+		Where("expense_reports.status IN ?", models.LockingStatuses())
+}
+
+// reportLines — строки неудалённых отчётов в любом статусе
+func reportLines(db *gorm.DB) *gorm.DB {
+	return db.Model(&models.ExpenseReportLine{}).
+		Joins("JOIN expense_reports ON expense_reports.id = expense_report_lines.report_id AND expense_reports.deleted_at IS NULL")
+}
+
+// ErrExpenseReported — расход входит в отчёт, который ещё не отклонён
+var ErrExpenseReported = errors.New("expense is included in a report")
+
+// ReplacePerDiem заменяет начисленные суточные поездки новым расчётом. Если какие-то
+// из прежних суточных уже входят в неотклонённый отчёт, ничего не меняется и возвращается
+// ErrExpenseReported: строки отчёта не должны ссылаться на удалённые записи. Строки
+// отклонённых отчётов удаляются вместе с записями, как в DeleteExpense.
+func (r *ExpenseRepository) ReplacePerDiem(ctx context.Context, userID uint, travelID uint, entries []models.Expense) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		previous := tx.Model(&models.Expense{}).
+			Select("id").
+			Where("user_id = ? AND travel_id = ? AND kind = ?", userID, travelID, models.KindPerDiem)
+		var reported int64
+		if err := reportLines(tx).
+			Where("expense_report_lines.expense_id IN (?) AND expense_reports.status <> ?", previous, models.ReportRejected).
+			Count(&reported).Error; err != nil {
+			return err
+		}
+		if reported > 0 {
+			return ErrExpenseReported
+		}
+		if err := deleteRejectedLines(tx, previous); err != nil {
+			return err
+		}
+		if err := tx.
+			Where("user_id = ? AND travel_id = ? AND kind = ?", userID, travelID, models.KindPerDiem).
+			Delete(&models.Expense{}).Error; err != nil {
+			return err
+		}
+		if len(entries) > 0 {
+			if err := tx.Omit(clause.Associations).Create(&entries).Error; err != nil {
+				return err
+			}
+		}
+		return syncAggregates(tx, travelID)
+	})
+}
+
+// FindSameAmountExpenses ищет расходы поездки с той же суммой в интервале [from, to].
+func (r *ExpenseRepository) FindSameAmountExpenses(ctx context.Context, userID uint, travelID uint, amount float64, from, to time.Time) ([]models.Expense, error) {
+	var expenses []models.Expense
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND travel_id = ? AND ROUND(amount::numeric, 2) = ROUND(?::numeric, 2)", userID, travelID, amount).
+		Where("created_at BETWEEN ? AND ?", from, to).
+		Order("created_at").
+		Find(&expenses).Error
+	return expenses, err
+}
+
 type ExpenseFilter struct {
 	UserID     uint
 	FromTime   *time.Time
@@ -42,7 +133,7 @@ type ExpenseFilter struct {
 
 func (r *ExpenseRepository) GetExpensesByUserTimeAndCategory(ctx context.Context, filter ExpenseFilter) ([]models.Expense, error) {
 	var expenses []models.Expense
-	query := r.db.WithContext(ctx).Model(&models.Expense{}).Where("user_id = ?", filter.UserID)
+	query := r.db.WithContext(ctx).Model(&models.Expense{}).Preload("Category").Preload("Violations").Where("user_id = ?", filter.UserID)
 
 	if filter.CategoryID != nil {
 		query = query.Where("category_id = ?", *filter.CategoryID)
@@ -61,12 +152,56 @@ func (r *ExpenseRepository) GetExpensesByUserTimeAndCategory(ctx context.Context
 }
 
 func (r *ExpenseRepository) UpdateExpense(ctx context.Context, expense *models.Expense) error {
-	return r.db.WithContext(ctx).Save(expense).Error
-
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := expenseLocation(tx, expense.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(expense).Error; err != nil {
+			return err
+		}
+		return syncExpenseChange(tx, before, expense)
+	})
 }
 
+// DeleteExpense удаляет запись вместе с её строками в отклонённых отчётах: отчёт, отправленный
+// повторно, не должен ссылаться на удалённый расход. Из действующих отчётов расход не удаляется —
+// это проверяет сервис.
 func (r *ExpenseRepository) DeleteExpense(ctx context.Context, id uint) error {
-	return r.db.WithContext(ctx).Delete(&models.Expense{}, id).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := expenseLocation(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := deleteRejectedLines(tx, []uint{id}); err != nil {
+			return err
+		}
+		if err := tx.Delete(&models.Expense{}, id).Error; err != nil {
+			return err
+		}
+		return syncExpenseChange(tx, before, nil)
+	})
+}
+
+// deleteRejectedLines удаляет строки отклонённых отчётов с расходами expenseIDs
+// (список или подзапрос)
+func deleteRejectedLines(tx *gorm.DB, expenseIDs any) error {
+	rejected := tx.Model(&models.ExpenseReport{}).Select("id").Where("status = ?", models.ReportRejected)
+	return tx.
+		Where("expense_id IN (?) AND report_id IN (?)", expenseIDs, rejected).
+		Delete(&models.ExpenseReportLine{}).Error
+}
+
+// expenseLocation возвращает поездку и дату записи до изменения; nil — записи нет
+func expenseLocation(tx *gorm.DB, id uint) (*models.Expense, error) {
+	var expenses []models.Expense
+	if err := tx.Select("id", "travel_id", "created_at").Where("id = ?", id).Limit(1).Find(&expenses).Error; err != nil {
+		return nil, err
+	}
+	if len(expenses) == 0 {
+		return nil, nil
+	}
+	return &expenses[0], nil
 }
 
 func (r *ExpenseRepository) ExistsByCategoryID(ctx context.Context, categoryID uint) (bool, error) {
@@ -79,68 +214,197 @@ func (r *ExpenseRepository) ExistsByCategoryID(ctx context.Context, categoryID u
 	return count > 0, nil
 }
 
-func (r *ExpenseRepository) SumByCategory(ctx context.Context, userID uint, travelID uint, from, to *time.Time) (map[string]float64, error) {
-	var results []struct {
-		Category string
-		Amount   float64
-	}
-	query := r.db.WithContext(ctx).Table("expenses").
-		Select("categories.name as category, SUM(expenses.amount) as amount").
-		Joins("LEFT JOIN categories ON expenses.category_id = categories.id").
-		Where("expenses.user_id = ? AND expenses.travel_id = ? AND expenses.deleted_at IS NULL", userID, travelID).
-		Group("categories.name")
-	if from != nil {
-		query = query.Where("expenses.created_at >= ?", *from)
-	}
-	if to != nil {
-		query = query.Where("expenses.created_at <= ?", *to)
-	}
-	if err := query.Scan(&results).Error; err != nil {
-		return nil, err
+// AmountSummary — суммы записей, разложенные по виду
+type AmountSummary struct {
+	Gross      float64 // расходы
+	Refunds    float64 // возвраты и компенсации
+	Income     float64
+	Allowances float64 // суточные и пробег
+}
+
+// Net — чистые траты: расходы за вычетом возвратов
+func (a AmountSummary) Net() float64 {
+	return a.Gross - a.Refunds
+}
+
+func (a AmountSummary) Add(b AmountSummary) AmountSummary {
+	return AmountSummary{
+		Gross:      a.Gross + b.Gross,
+		Refunds:    a.Refunds + b.Refunds,
+		Income:     a.Income + b.Income,
+		Allowances: a.Allowances + b.Allowances,
 	}
-	res := make(map[string]float64)
-	for _, r := range results {
-		res[r.Category] = r.Amount
+}
+
+const amountSummarySelect = `COALESCE(SUM(CASE WHEN expenses.kind = 'expense' THEN expenses.amount END), 0) as gross,
+	COALESCE(SUM(CASE WHEN expenses.kind IN ('refund', 'reimbursement') THEN expenses.amount END), 0) as refunds,
+	COALESCE(SUM(CASE WHEN expenses.kind = 'income' THEN expenses.amount END), 0) as income,
+	COALESCE(SUM(CASE WHEN expenses.kind IN ('per_diem', 'mileage') THEN expenses.amount END), 0) as allowances`
+
+// travelScope отбирает записи пользователя в поездке; travelID == 0 — во всех поездках
+func travelScope(userID uint, travelID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("expenses.user_id = ? AND expenses.deleted_at IS NULL", userID)
+		if travelID != 0 {
+			db = db.Where("expenses.travel_id = ?", travelID)
+		}
+		return db
 	}
-	return res, nil
 }
 
-func (r *ExpenseRepository) SumByDay(ctx context.Context, userID uint, travelID uint, from, to *time.Time) (map[string]float64, error) {
-	var results []struct {
-		Day    string
-		Amount float64
+// CategorySummary — суммы записей, отнесённых непосредственно к категории
+type CategorySummary struct {
+	CategoryID  uint
+	Category    string
+	CategoryKey string
+	AmountSummary
+}
+
+// TravelCategorySummary — суммы записей одной категории в одной поездке
+type TravelCategorySummary struct {
+	TravelID uint
+	CategorySummary
+}
+
+// SumByTravelAndCategory считает суммы по категориям сразу для всех поездок пользователя
+func (r *ExpenseRepository) SumByTravelAndCategory(ctx context.Context, userID uint) ([]TravelCategorySummary, error) {
+	var results []TravelCategorySummary
+	err := r.db.WithContext(ctx).Table("expenses").
+		Select("expenses.travel_id, expenses.category_id, categories.name as category, categories.key as category_key, "+amountSummarySelect).
+		Joins("LEFT JOIN categories ON expenses.category_id = categories.id").
+		Where("expenses.user_id = ? AND expenses.deleted_at IS NULL", userID).
+		Group("expenses.travel_id, expenses.category_id, categories.name, categories.key").
+		Scan(&results).Error
+	return results, err
+}
+
+// TravelSummary — суммы записей поездки
+type TravelSummary struct {
+	TravelID uint
+	Title    string
+	AmountSummary
+}
+
+// PeriodSummary — суммы за период, начинающийся с Start (YYYY-MM-DD)
+type PeriodSummary struct {
+	Start string
+	AmountSummary
+}
+
+// SummaryQuery — параметры Summarize
+type SummaryQuery struct {
+	UserID   uint
+	TravelID uint // 0 — все поездки пользователя
+	// Границы периода — даты в часовом поясе Timezone, обе включительно; время не учитывается
+	From, To *time.Time
+	// Шаг ряда: day, week или month. Передаётся в date_trunc и должен быть проверен вызывающим кодом.
+	Unit     string
+	Timezone string // часовой пояс дат ряда
+	ByTravel bool   // добавить суммы по поездкам
+}
+
+// AnalyticsSums — итог, суммы по категориям, по периодам и по поездкам,
+// посчитанные одним запросом по одному снимку данных
+type AnalyticsSums struct {
+	Total      AmountSummary
+	ByCategory []CategorySummary
+	ByPeriod   []PeriodSummary // по возрастанию Start
+	ByTravel   []TravelSummary // только при SummaryQuery.ByTravel
+}
+
+// localDaySQL — дата записи в часовом поясе из параметра. Запись со временем (has_time)
+// переводится в этот пояс; запись без времени хранится как полночь UTC и остаётся на своей
+// дате, иначе западнее UTC она съехала бы на предыдущий день.
+const localDaySQL = `CASE WHEN expenses.has_time
+	THEN (expenses.created_at AT TIME ZONE ?)::date
+	ELSE (expenses.created_at AT TIME ZONE 'UTC')::date END`
+
+// Summarize считает все суммы аналитики одним запросом с GROUPING SETS по исходным записям.
+// Периоды и границы From/To сравниваются с датой записи в часовом поясе запроса.
+func (r *ExpenseRepository) Summarize(ctx context.Context, q SummaryQuery) (*AnalyticsSums, error) {
+	dated := r.db.Table("expenses").
+		Select("expenses.travel_id, expenses.category_id, expenses.kind, expenses.amount, "+localDaySQL+" AS day", q.Timezone).
+		Scopes(travelScope(q.UserID, q.TravelID))
+	source := r.db.Table("(?) AS e", dated).
+		Select(`e.travel_id, e.category_id,
+			to_char(date_trunc(?, e.day::timestamp), 'YYYY-MM-DD') AS start,
+			CASE WHEN e.kind = 'expense' THEN e.amount ELSE 0 END AS gross,
+			CASE WHEN e.kind IN ('refund', 'reimbursement') THEN e.amount ELSE 0 END AS refunds,
+			CASE WHEN e.kind = 'income' THEN e.amount ELSE 0 END AS income,
+			CASE WHEN e.kind IN ('per_diem', 'mileage') THEN e.amount ELSE 0 END AS allowances`,
+			q.Unit)
+	if q.From != nil {
+		source = source.Where("e.day >= ?::date", q.From.Format("2006-01-02"))
 	}
-	query := r.db.WithContext(ctx).Table("expenses").
-		Select("DATE(expenses.created_at) as day, SUM(expenses.amount) as amount").
-		Where("expenses.user_id = ? AND expenses.travel_id = ? AND expenses.deleted_at IS NULL", userID, travelID).
-		Group("day")
-	if from != nil {
-		query = query.Where("expenses.created_at >= ?", *from)
+	if q.To != nil {
+		source = source.Where("e.day <= ?::date", q.To.Format("2006-01-02"))
 	}
-	if to != nil {
-		query = query.Where("expenses.created_at <= ?", *to)
+	return summarize(r.db.WithContext(ctx), source, q.ByTravel)
+}
+
+// summaryRow — строка результата GROUPING SETS; флаги No* равны 1 для колонок,
+// по которым строка не группировалась
+type summaryRow struct {
+	CategoryID  *uint
+	Category    *string
+	CategoryKey *string
+	Start       *string
+	TravelID    *uint
+	Title       *string
+	NoCategory  int
+	NoPeriod    int
+	NoTravel    int
+	AmountSummary
+}
+
+// summarize группирует source — подзапрос с колонками travel_id, category_id, start, gross,
+// refunds, income и allowances — сразу по нескольким наборам: итог, категории, периоды
+// и, если нужно, поездки. Новый разрез добавляется ещё одним набором.
+func summarize(db *gorm.DB, source *gorm.DB, byTravel bool) (*AnalyticsSums, error) {
+	columns := `src.category_id, categories.name AS category, categories.key AS category_key, src.start,
+		GROUPING(src.category_id) AS no_category, GROUPING(src.start) AS no_period,
+		COALESCE(SUM(src.gross), 0) AS gross, COALESCE(SUM(src.refunds), 0) AS refunds,
+		COALESCE(SUM(src.income), 0) AS income, COALESCE(SUM(src.allowances), 0) AS allowances`
+	sets := "(), (src.category_id, categories.name, categories.key), (src.start)"
+	query := db.Table("(?) AS src", source).
+		Joins("LEFT JOIN categories ON src.category_id = categories.id")
+	if byTravel {
+		columns += ", src.travel_id, travels.title, GROUPING(src.travel_id) AS no_travel"
+		sets += ", (src.travel_id, travels.title)"
+		query = query.Joins("LEFT JOIN travels ON src.travel_id = travels.id")
+	} else {
+		columns += ", 1 AS no_travel"
 	}
-	if err := query.Scan(&results).Error; err != nil {
+
+	var rows []summaryRow
+	if err := query.Select(columns).
+		Group("GROUPING SETS (" + sets + ")").
+		Order("src.start").
+		Scan(&rows).Error; err != nil {
 		return nil, err
 	}
-	res := make(map[string]float64)
-	for _, r := range results {
-		res[r.Day] = r.Amount
+
+	sums := &AnalyticsSums{}
+	for _, row := range rows {
+		switch {
+		case row.NoCategory == 0:
+			c := CategorySummary{CategoryID: deref(row.CategoryID), Category: deref(row.Category), CategoryKey: deref(row.CategoryKey), AmountSummary: row.AmountSummary}
+			sums.ByCategory = append(sums.ByCategory, c)
+		case row.NoPeriod == 0:
+			sums.ByPeriod = append(sums.ByPeriod, PeriodSummary{Start: deref(row.Start), AmountSummary: row.AmountSummary})
+		case row.NoTravel == 0:
+			sums.ByTravel = append(sums.ByTravel, TravelSummary{TravelID: deref(row.TravelID), Title: deref(row.Title), AmountSummary: row.AmountSummary})
+		default:
+			sums.Total = row.AmountSummary
+		}
 	}
-	return res, nil
+	return sums, nil
 }
 
-func (r *ExpenseRepository) TotalSum(ctx context.Context, userID uint, travelID uint, from, to *time.Time) (float64, error) {
-	var sum float64
-	query := r.db.WithContext(ctx).Model(&models.Expense{}).
-		Select("SUM(amount)").
-		Where("user_id = ? AND travel_id = ? AND deleted_at IS NULL", userID, travelID)
-	if from != nil {
-		query = query.Where("created_at >= ?", *from)
-	}
-	if to != nil {
-		query = query.Where("created_at <= ?", *to)
+func deref[T any](v *T) T {
+	var zero T
+	if v == nil {
+		return zero
 	}
-	err := query.Scan(&sum).Error
-	return sum, err
+	return *v
 }
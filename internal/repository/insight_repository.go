@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type InsightRepository struct {
+	db *gorm.DB
+}
+
+func NewInsightRepository(db *gorm.DB) InsightRepositoryInterface {
+	return &InsightRepository{db: db}
+}
+
+// GetInsights возвращает наблюдения пользователя, новые первыми; travelID == nil — по всем поездкам
+func (r *InsightRepository) GetInsights(ctx context.Context, userID uint, travelID *uint) ([]models.Insight, error) {
+	var insights []models.Insight
+	query := r.db.WithContext(ctx).Preload("Category").Where("user_id = ?", userID)
+	if travelID != nil {
+		query = query.Where("travel_id = ?", *travelID)
+	}
+	err := query.Order("created_at DESC, id").Find(&insights).Error
+	return insights, err
+}
+
+// ReplaceInsights заменяет все наблюдения поездки результатом нового анализа
+func (r *InsightRepository) ReplaceInsights(ctx context.Context, userID uint, travelID uint, insights []models.Insight) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ? AND travel_id = ?", userID, travelID).
+			Delete(&models.Insight{}).Error; err != nil {
+			return err
+		}
+		if len(insights) == 0 {
+			return nil
+		}
+		return tx.Omit("Category").Create(&insights).Error
+	})
+}
@@ -10,31 +10,120 @@ type UserRepositoryInterface interface {
 	CreateUser(ctx context.Context, user *models.User) error
 	GetByLogin(ctx context.Context, login string) (*models.User, error)
 	GetByID(ctx context.Context, id uint) (*models.User, error)
+	UpdateLocale(ctx context.Context, userID uint, locale string) error
 	IsLoginExists(ctx context.Context, login string) (bool, error)
+	UpdateEmail(ctx context.Context, userID uint, email string) error
+	GetByLoginOrEmail(ctx context.Context, value string) (*models.User, error)
+	ChangePassword(ctx context.Context, userID uint, hash string, keepSessionID uint, now time.Time) error
+	CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error
+	GetResetToken(ctx context.Context, hash string) (*models.PasswordResetToken, error)
+	ResetPassword(ctx context.Context, token *models.PasswordResetToken, hash string, now time.Time) (bool, error)
 }
 
 type TravelRepositoryInterface interface {
 	CreateTravel(ctx context.Context, travel *models.Travel) error
 	GetTravelByID(ctx context.Context, travelID uint) (*models.Travel, error)
+	GetTravelsByUserID(ctx context.Context, userID uint) ([]models.Travel, error)
+	GetTravelsInRange(ctx context.Context, userID uint, from, to time.Time) ([]models.Travel, error)
+	CountTravelDays(ctx context.Context, userID uint, from, to time.Time) (int, error)
+	UpdateBudget(ctx context.Context, travelID uint, budget *float64) error
 }
 
 type ExpenseRepositoryInterface interface {
 	CreateExpense(ctx context.Context, expense *models.Expense) error
 	GetExpensesByUserID(ctx context.Context, id uint) ([]models.Expense, error)
 	GetExpensesByUserTimeAndCategory(ctx context.Context, filter ExpenseFilter) ([]models.Expense, error)
+	GetExpensesByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.Expense, error)
+	FindSameAmountExpenses(ctx context.Context, userID uint, travelID uint, amount float64, from, to time.Time) ([]models.Expense, error)
 	GetExpenseByID(ctx context.Context, expenseID uint) (*models.Expense, error)
 	ExistsByCategoryID(ctx context.Context, categoryID uint) (bool, error)
+	GetReportStatuses(ctx context.Context, expenseID uint) ([]models.ReportStatus, error)
 	UpdateExpense(ctx context.Context, expense *models.Expense) error
 	DeleteExpense(ctx context.Context, id uint) error
-	SumByCategory(ctx context.Context, userID uint, travelID uint, from, to *time.Time) (map[string]float64, error)
-	SumByDay(ctx context.Context, userID uint, travelID uint, from, to *time.Time) (map[string]float64, error)
-	TotalSum(ctx context.Context, userID uint, travelID uint, from, to *time.Time) (float64, error)
+	ReplacePerDiem(ctx context.Context, userID uint, travelID uint, entries []models.Expense) error
+	SumByTravelAndCategory(ctx context.Context, userID uint) ([]TravelCategorySummary, error)
+	Summarize(ctx context.Context, q SummaryQuery) (*AnalyticsSums, error)
+}
+
+// AnalyticsRepositoryInterface — суммы для аналитики; их отдают ExpenseRepository
+// по исходным записям и AggregateRepository по дневным агрегатам
+type AnalyticsRepositoryInterface interface {
+	SumByTravelAndCategory(ctx context.Context, userID uint) ([]TravelCategorySummary, error)
+	Summarize(ctx context.Context, q SummaryQuery) (*AnalyticsSums, error)
 }
 
 type CategoryRepositoryInterface interface {
 	GetAllCategories(ctx context.Context, userID uint) ([]models.Category, error)
 	GetCategoryByID(ctx context.Context, id uint) (*models.Category, error)
-	GetCategoryByName(ctx context.Context, name string) (*models.Category, error)
+	GetCategoryByName(ctx context.Context, userID uint, name string) (*models.Category, error)
+	GetCategoryByKey(ctx context.Context, key string) (*models.Category, error)
 	CreateCategory(ctx context.Context, category *models.Category) error
+	UpdateCategory(ctx context.Context, category *models.Category) error
+	GetChildCategories(ctx context.Context, parentID uint) ([]models.Category, error)
+	SetCategoryOrder(ctx context.Context, userID uint, categories []models.Category) error
+	SetCategoryArchived(ctx context.Context, userID uint, category *models.Category, archived bool) error
 	DeleteCategory(ctx context.Context, categoryID uint) error
+	DeleteCategories(ctx context.Context, categoryIDs []uint) error
+	MergeCategory(ctx context.Context, source *models.Category, target *models.Category) error
+}
+
+type ReconciliationRepositoryInterface interface {
+	CreateTransactions(ctx context.Context, transactions []models.BankTransaction) error
+	GetTransactionsByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.BankTransaction, error)
+	GetMatchesByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.ReconciliationMatch, error)
+	GetMatchByID(ctx context.Context, matchID uint) (*models.ReconciliationMatch, error)
+	ReplaceProposedMatches(ctx context.Context, userID uint, travelID uint, matches []models.ReconciliationMatch) error
+	UpdateMatchStatus(ctx context.Context, matchID uint, status models.MatchStatus) error
+	ConfirmMatch(ctx context.Context, match *models.ReconciliationMatch, expense *models.Expense) error
+}
+
+type ExpenseReportRepositoryInterface interface {
+	CreateReport(ctx context.Context, report *models.ExpenseReport) error
+	GetReportByID(ctx context.Context, reportID uint) (*models.ExpenseReport, error)
+	GetReportsByUserID(ctx context.Context, userID uint) ([]models.ExpenseReport, error)
+	GetReportsByStatus(ctx context.Context, status models.ReportStatus) ([]models.ExpenseReport, error)
+	GetReportedExpenseIDs(ctx context.Context, travelID uint) ([]uint, error)
+	UpdateStatus(ctx context.Context, reportID uint, transition *models.ExpenseReportTransition) error
+	UpdateLine(ctx context.Context, line *models.ExpenseReportLine) error
+	DeleteReport(ctx context.Context, reportID uint) error
+}
+
+type PolicyRepositoryInterface interface {
+	CreatePolicy(ctx context.Context, policy *models.SpendingPolicy) error
+	GetPolicyByID(ctx context.Context, policyID uint) (*models.SpendingPolicy, error)
+	GetPoliciesByUserID(ctx context.Context, userID uint) ([]models.SpendingPolicy, error)
+	GetPoliciesForTravel(ctx context.Context, userID uint, travelID uint) ([]models.SpendingPolicy, error)
+	DeletePolicy(ctx context.Context, policyID uint) error
+	GetViolationsByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.PolicyViolation, error)
+	ReplaceViolations(ctx context.Context, userID uint, travelID uint, violations []models.PolicyViolation) error
+}
+
+type AllowanceRepositoryInterface interface {
+	UpsertRates(ctx context.Context, rates []models.PerDiemRate) error
+	GetRates(ctx context.Context, country string) ([]models.PerDiemRate, error)
+	FindRate(ctx context.Context, country string, city string) (*models.PerDiemRate, error)
+}
+
+type InsightRepositoryInterface interface {
+	GetInsights(ctx context.Context, userID uint, travelID *uint) ([]models.Insight, error)
+	ReplaceInsights(ctx context.Context, userID uint, travelID uint, insights []models.Insight) error
+}
+
+type TripReportRepositoryInterface interface {
+	CreateJob(ctx context.Context, job *models.TripReportJob) error
+	GetJobByID(ctx context.Context, jobID uint) (*models.TripReportJob, error)
+	GetJobFile(ctx context.Context, jobID uint) ([]byte, error)
+	UpdateJob(ctx context.Context, job *models.TripReportJob) error
+	DeleteJobsBefore(ctx context.Context, before time.Time) error
+}
+
+type SessionRepositoryInterface interface {
+	CreateSession(ctx context.Context, session *models.Session, token *models.RefreshToken) error
+	GetRefreshToken(ctx context.Context, hash string) (*models.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, usedID uint, next *models.RefreshToken, now time.Time) (bool, error)
+	RevokeSession(ctx context.Context, sessionID uint, now time.Time) error
+	GetActiveSessions(ctx context.Context, userID uint, now time.Time) ([]models.Session, error)
+	RevokeUserSession(ctx context.Context, userID uint, sessionID uint, now time.Time) (bool, error)
+	RevokeOtherSessions(ctx context.Context, userID uint, keepID uint, now time.Time) (int64, error)
+	DeleteExpiredTokens(ctx context.Context, before time.Time) error
 }
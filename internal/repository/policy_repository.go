@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type PolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewPolicyRepository(db *gorm.DB) PolicyRepositoryInterface {
+	return &PolicyRepository{db: db}
+}
+
+func (r *PolicyRepository) CreatePolicy(ctx context.Context, policy *models.SpendingPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *PolicyRepository) GetPolicyByID(ctx context.Context, policyID uint) (*models.SpendingPolicy, error) {
+	var policy models.SpendingPolicy
+	err := r.db.WithContext(ctx).Where("id = ?", policyID).First(&policy).Error
+	return &policy, err
+}
+
+func (r *PolicyRepository) GetPoliciesByUserID(ctx context.Context, userID uint) ([]models.SpendingPolicy, error) {
+	var policies []models.SpendingPolicy
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&policies).Error
+	return policies, err
+}
+
+// GetPoliciesForTravel возвращает общие правила пользователя и правила конкретной поездки
+func (r *PolicyRepository) GetPoliciesForTravel(ctx context.Context, userID uint, travelID uint) ([]models.SpendingPolicy, error) {
+	var policies []models.SpendingPolicy
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND (travel_id IS NULL OR travel_id = ?)", userID, travelID).
+		Order("id").
+		Find(&policies).Error
+	return policies, err
+}
+
+func (r *PolicyRepository) DeletePolicy(ctx context.Context, policyID uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("policy_id = ?", policyID).Delete(&models.PolicyViolation{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.SpendingPolicy{}, policyID).Error
+	})
+}
+
+func (r *PolicyRepository) GetViolationsByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.PolicyViolation, error) {
+	var violations []models.PolicyViolation
+	err := r.db.WithContext(ctx).
+		Preload("Policy").
+		Where("user_id = ? AND travel_id = ?", userID, travelID).
+		Order("expense_id, policy_id").
+		Find(&violations).Error
+	return violations, err
+}
+
+// ReplaceViolations заменяет все нарушения поездки результатом новой проверки
+func (r *PolicyRepository) ReplaceViolations(ctx context.Context, userID uint, travelID uint, violations []models.PolicyViolation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ? AND travel_id = ?", userID, travelID).
+			Delete(&models.PolicyViolation{}).Error; err != nil {
+			return err
+		}
+		if len(violations) == 0 {
+			return nil
+		}
+		return tx.Omit("Policy").Create(&violations).Error
+	})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ReconciliationRepository struct {
+	db *gorm.DB
+}
+
+func NewReconciliationRepository(db *gorm.DB) ReconciliationRepositoryInterface {
+	return &ReconciliationRepository{db: db}
+}
+
+func (r *ReconciliationRepository) CreateTransactions(ctx context.Context, transactions []models.BankTransaction) error {
+	if len(transactions) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&transactions).Error
+}
+
+func (r *ReconciliationRepository) GetTransactionsByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.BankTransaction, error) {
+	var transactions []models.BankTransaction
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND travel_id = ?", userID, travelID).
+		Order("date").
+		Find(&transactions).Error
+	return transactions, err
+}
+
+func (r *ReconciliationRepository) GetMatchesByTravelID(ctx context.Context, userID uint, travelID uint) ([]models.ReconciliationMatch, error) {
+	var matches []models.ReconciliationMatch
+	err := r.db.WithContext(ctx).
+		Preload("Transaction").
+		Preload("Expense.Category").
+		Where("user_id = ? AND travel_id = ?", userID, travelID).
+		Order("score DESC").
+		Find(&matches).Error
+	return matches, err
+}
+
+func (r *ReconciliationRepository) GetMatchByID(ctx context.Context, matchID uint) (*models.ReconciliationMatch, error) {
+	var match models.ReconciliationMatch
+	err := r.db.WithContext(ctx).
+		Preload("Transaction").
+		Preload("Expense.Category").
+		Where("id = ?", matchID).
+		First(&match).Error
+	return &match, err
+}
+
+// ReplaceProposedMatches заменяет все неподтверждённые предложения по поездке новыми.
+// Подтверждённые и отклонённые пары не трогаются.
+func (r *ReconciliationRepository) ReplaceProposedMatches(ctx context.Context, userID uint, travelID uint, matches []models.ReconciliationMatch) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().
+			Where("user_id = ? AND travel_id = ? AND status = ?", userID, travelID, models.MatchProposed).
+			Delete(&models.ReconciliationMatch{}).Error; err != nil {
+			return err
+		}
+		if len(matches) == 0 {
+			return nil
+		}
+		return tx.Omit("Transaction", "Expense").Create(&matches).Error
+	})
+}
+
+func (r *ReconciliationRepository) UpdateMatchStatus(ctx context.Context, matchID uint, status models.MatchStatus) error {
+	return r.db.WithContext(ctx).Model(&models.ReconciliationMatch{}).
+		Where("id = ?", matchID).
+		Update("status", status).Error
+}
+
+// ConfirmMatch подтверждает пару, привязывает операцию к расходу и снимает
+// конкурирующие предложения. Если передан expense, он сохраняется в той же транзакции.
+func (r *ReconciliationRepository) ConfirmMatch(ctx context.Context, match *models.ReconciliationMatch, expense *models.Expense) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.ReconciliationMatch{}).
+			Where("id = ?", match.ID).
+			Update("status", models.MatchConfirmed).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.BankTransaction{}).
+			Where("id = ?", match.TransactionID).
+			Update("expense_id", match.ExpenseID).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().
+			Where("id <> ? AND status = ? AND (transaction_id = ? OR expense_id = ?)",
+				match.ID, models.MatchProposed, match.TransactionID, match.ExpenseID).
+			Delete(&models.ReconciliationMatch{}).Error; err != nil {
+			return err
+		}
+		if expense == nil {
+			return nil
+		}
+		before, err := expenseLocation(tx, expense.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Omit("User", "Travel", "Category").Save(expense).Error; err != nil {
+			return err
+		}
+		return syncExpenseChange(tx, before, expense)
+	})
+}
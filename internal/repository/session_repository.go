@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepositoryInterface {
+	return &SessionRepository{db: db}
+}
+
+// CreateSession заводит сессию и первый токен обновления в ней
+func (r *SessionRepository) CreateSession(ctx context.Context, session *models.Session, token *models.RefreshToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		token.SessionID = session.ID
+		return tx.Create(token).Error
+	})
+}
+
+// GetRefreshToken ищет токен обновления по хешу вместе с его сессией
+func (r *SessionRepository) GetRefreshToken(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := r.db.WithContext(ctx).Preload("Session").Where("hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RotateRefreshToken помечает токен usedID использованным и сохраняет следующий.
+// Возвращает false, если токен уже был использован параллельным запросом.
+func (r *SessionRepository) RotateRefreshToken(ctx context.Context, usedID uint, next *models.RefreshToken, now time.Time) (bool, error) {
+	rotated := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.RefreshToken{}).
+			Where("id = ? AND used_at IS NULL", usedID).
+			Update("used_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		rotated = true
+		return tx.Create(next).Error
+	})
+	return rotated, err
+}
+
+// RevokeSession отзывает сессию вместе со всеми её токенами
+func (r *SessionRepository) RevokeSession(ctx context.Context, sessionID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).Error
+}
+
+// GetActiveSessions возвращает неотозванные сессии пользователя, у которых остался
+// действующий токен обновления, начиная с последней активной
+func (r *SessionRepository) GetActiveSessions(ctx context.Context, userID uint, now time.Time) ([]models.Session, error) {
+	var sessions []models.Session
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Where(`EXISTS (SELECT 1 FROM refresh_tokens rt
+			WHERE rt.session_id = sessions.id AND rt.used_at IS NULL AND rt.expires_at > ? AND rt.deleted_at IS NULL)`, now).
+		Order("COALESCE(last_seen_at, created_at) DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// RevokeUserSession отзывает сессию пользователя. Возвращает false, если у пользователя
+// нет такой неотозванной сессии.
+func (r *SessionRepository) RevokeUserSession(ctx context.Context, userID uint, sessionID uint, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", sessionID, userID).
+		Update("revoked_at", now)
+	return result.RowsAffected > 0, result.Error
+}
+
+// RevokeOtherSessions отзывает все сессии пользователя, кроме keepID, и возвращает их число
+func (r *SessionRepository) RevokeOtherSessions(ctx context.Context, userID uint, keepID uint, now time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepID).
+		Update("revoked_at", now)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteExpiredTokens удаляет токены обновления, истёкшие раньше before
+func (r *SessionRepository) DeleteExpiredTokens(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where("expires_at < ?", before).
+		Delete(&models.RefreshToken{}).Error
+}
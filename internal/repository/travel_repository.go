@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 	"wanderwallet/internal/models"
 
 	"gorm.io/gorm"
@@ -26,3 +27,35 @@ func (r *TravelRepository) GetTravelByID(ctx context.Context, travelID uint) (*m
 	}
 	return &travel, nil
 }
+
+func (r *TravelRepository) GetTravelsByUserID(ctx context.Context, userID uint) ([]models.Travel, error) {
+	var travels []models.Travel
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("start_date").Find(&travels).Error
+	return travels, err
+}
+
+func (r *TravelRepository) UpdateBudget(ctx context.Context, travelID uint, budget *float64) error {
+	return r.db.WithContext(ctx).Model(&models.Travel{}).Where("id = ?", travelID).Update("budget", budget).Error
+}
+
+// GetTravelsInRange возвращает поездки пользователя, пересекающиеся с периодом [from, to]
+func (r *TravelRepository) GetTravelsInRange(ctx context.Context, userID uint, from, to time.Time) ([]models.Travel, error) {
+	var travels []models.Travel
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND start_date <= ? AND end_date >= ?", userID, to, from).
+		Order("start_date, id").
+		Find(&travels).Error
+	return travels, err
+}
+
+// CountTravelDays считает дни периода [from, to], проведённые в поездках.
+// День, попавший в несколько поездок, считается один раз.
+func (r *TravelRepository) CountTravelDays(ctx context.Context, userID uint, from, to time.Time) (int, error) {
+	var days int
+	err := r.db.WithContext(ctx).Raw(`SELECT COUNT(DISTINCT (d AT TIME ZONE 'UTC')::date)
+		FROM travels, generate_series(GREATEST(travels.start_date, ?), LEAST(travels.end_date, ?), interval '1 day') AS d
+		WHERE travels.user_id = ? AND travels.deleted_at IS NULL AND travels.start_date <= ? AND travels.end_date >= ?`,
+		from, to, userID, to, from).
+		Scan(&days).Error
+	return days, err
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"time"
+	"wanderwallet/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TripReportRepository struct {
+	db *gorm.DB
+}
+
+func NewTripReportRepository(db *gorm.DB) TripReportRepositoryInterface {
+	return &TripReportRepository{db: db}
+}
+
+func (r *TripReportRepository) CreateJob(ctx context.Context, job *models.TripReportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// GetJobByID возвращает задание без файла отчёта
+func (r *TripReportRepository) GetJobByID(ctx context.Context, jobID uint) (*models.TripReportJob, error) {
+	var job models.TripReportJob
+	err := r.db.WithContext(ctx).Omit("File").Where("id = ?", jobID).First(&job).Error
+	return &job, err
+}
+
+func (r *TripReportRepository) GetJobFile(ctx context.Context, jobID uint) ([]byte, error) {
+	var job models.TripReportJob
+	err := r.db.WithContext(ctx).Select("file").Where("id = ?", jobID).First(&job).Error
+	return job.File, err
+}
+
+// UpdateJob сохраняет состояние задания, его ошибку и файл
+func (r *TripReportRepository) UpdateJob(ctx context.Context, job *models.TripReportJob) error {
+	return r.db.WithContext(ctx).Model(&models.TripReportJob{}).
+		Where("id = ?", job.ID).
+		Updates(map[string]interface{}{
+			"status":      job.Status,
+			"error":       job.Error,
+			"file":        job.File,
+			"finished_at": job.FinishedAt,
+		}).Error
+}
+
+// DeleteJobsBefore удаляет задания, созданные раньше before, вместе с файлами
+func (r *TripReportRepository) DeleteJobsBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Unscoped().
+		Where("created_at < ?", before).
+		Delete(&models.TripReportJob{}).Error
+}
@@ -2,9 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 	"wanderwallet/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository struct {
@@ -15,8 +18,19 @@ func NewUserRepository(db *gorm.DB) UserRepositoryInterface {
 	return &UserRepository{db: db}
 }
 
+var (
+	// ErrLoginTaken — логин занят: одновременная регистрация с тем же логином
+	ErrLoginTaken = errors.New("login already exists")
+	// ErrEmailTaken — адрес уже указан у другого пользователя
+	ErrEmailTaken = errors.New("email already in use")
+)
+
 func (r *UserRepository) CreateUser(ctx context.Context, user *models.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	err := r.db.WithContext(ctx).Create(user).Error
+	if isDuplicateKey(r.db, err) {
+		return ErrLoginTaken
+	}
+	return err
 }
 
 func (r *UserRepository) GetByLogin(ctx context.Context, login string) (*models.User, error) {
@@ -42,3 +56,106 @@ func (r *UserRepository) IsLoginExists(ctx context.Context, login string) (bool,
 	err := r.db.WithContext(ctx).Model(&models.User{}).Where("login = ?", login).Count(&count).Error
 	return count > 0, err
 }
+
+func (r *UserRepository) UpdateLocale(ctx context.Context, userID uint, locale string) error {
+	return r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("locale", locale).Error
+}
+
+// UpdateEmail сохраняет адрес пользователя; адрес другого пользователя — ErrEmailTaken.
+// Проверка дублируется уникальным индексом на случай одновременных запросов.
+func (r *UserRepository) UpdateEmail(ctx context.Context, userID uint, email string) error {
+	if email != "" {
+		var count int64
+		if err := r.db.WithContext(ctx).Unscoped().Model(&models.User{}).
+			Where("email = ? AND id <> ?", email, userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrEmailTaken
+		}
+	}
+	err := r.db.WithContext(ctx).Model(&models.User{}).Where("id = ?", userID).Update("email", email).Error
+	if isDuplicateKey(r.db, err) {
+		return ErrEmailTaken
+	}
+	return err
+}
+
+// isDuplicateKey — ошибка нарушения уникального ограничения. Перевод ошибок в gorm.Config
+// не включён, поэтому ошибка драйвера переводится здесь.
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// GetByLoginOrEmail ищет пользователя по логину, а если такого нет — по адресу почты
+func (r *UserRepository) GetByLoginOrEmail(ctx context.Context, value string) (*models.User, error) {
+	var user models.User
+	err := r.db.WithContext(ctx).
+		Where("login = ? OR (email <> '' AND email = LOWER(?))", value, value).
+		Order(clause.Expr{SQL: "login = ? DESC", Vars: []any{value}}).
+		First(&user).Error
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// ChangePassword сохраняет новый хеш пароля и отзывает все сессии пользователя, кроме
+// keepSessionID (0 — отозвать все)
+func (r *UserRepository) ChangePassword(ctx context.Context, userID uint, hash string, keepSessionID uint, now time.Time) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return changePassword(tx, userID, hash, keepSessionID, now)
+	})
+}
+
+func changePassword(tx *gorm.DB, userID uint, hash string, keepSessionID uint, now time.Time) error {
+	if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("password", hash).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, keepSessionID).
+		Update("revoked_at", now).Error
+}
+
+// CreateResetToken сохраняет код сброса пароля, удаляя прежние коды пользователя
+func (r *UserRepository) CreateResetToken(ctx context.Context, token *models.PasswordResetToken) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", token.UserID).Delete(&models.PasswordResetToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+func (r *UserRepository) GetResetToken(ctx context.Context, hash string) (*models.PasswordResetToken, error) {
+	var token models.PasswordResetToken
+	err := r.db.WithContext(ctx).Where("hash = ?", hash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// ResetPassword погашает код сброса и меняет пароль, отзывая все сессии пользователя.
+// Возвращает false, если код уже погашен параллельным запросом.
+func (r *UserRepository) ResetPassword(ctx context.Context, token *models.PasswordResetToken, hash string, now time.Time) (bool, error) {
+	reset := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		reset = true
+		return changePassword(tx, token.UserID, hash, 0, now)
+	})
+	return reset, err
+}
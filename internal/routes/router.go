@@ -13,6 +13,12 @@ func SetupRouter(
 	expenseController *controllers.ExpenseController,
 	categoryController *controllers.CategoryController,
 	analyticsController *controllers.AnalyticsController,
+	reconciliationController *controllers.ReconciliationController,
+	reportController *controllers.ExpenseReportController,
+	policyController *controllers.PolicyController,
+	allowanceController *controllers.AllowanceController,
+	insightController *controllers.InsightController,
+	tripReportController *controllers.TripReportController,
 ) {
 
 	api := r.Group("/api")
@@ -21,11 +27,33 @@ func SetupRouter(
 		{
 			userRoutes.POST("/register", userController.Register)
 			userRoutes.POST("/login", userController.Login)
+			userRoutes.POST("/refresh", userController.Refresh)
+			userRoutes.POST("/logout", userController.Logout)
+			userRoutes.GET("/sessions", userController.GetSessions)
+			userRoutes.DELETE("/sessions", userController.RevokeOtherSessions)
+			userRoutes.DELETE("/sessions/:id", userController.RevokeSession)
+			userRoutes.PUT("/locale", userController.SetLocale)
+			userRoutes.PUT("/email", userController.SetEmail)
+			userRoutes.PUT("/password", userController.ChangePassword)
+			userRoutes.POST("/password/forgot", userController.ForgotPassword)
+			userRoutes.POST("/password/reset", userController.ResetPassword)
 		}
 
 		travelRoutes := api.Group("/travel")
 		{
 			travelRoutes.POST("", travelController.CreateTravel)
+			travelRoutes.PUT("/:id/budget", travelController.SetBudget)
+			travelRoutes.GET("/:id/forecast", analyticsController.GetForecast)
+			travelRoutes.GET("/:id/charts/:kind", analyticsController.GetChart)
+			travelRoutes.GET("/:id/report.pdf", tripReportController.GetTripReport)
+			travelRoutes.GET("/:id/duplicates", expenseController.ScanDuplicates)
+			travelRoutes.GET("/:id/compliance", policyController.GetCompliance)
+			travelRoutes.POST("/:id/per-diem", allowanceController.GeneratePerDiem)
+			travelRoutes.POST("/:id/mileage", allowanceController.CreateMileage)
+			travelRoutes.POST("/:id/transactions", reconciliationController.ImportTransactions)
+			travelRoutes.GET("/:id/reconciliation", reconciliationController.GetMatches)
+			travelRoutes.POST("/:id/reconciliation", reconciliationController.ProposeMatches)
+			travelRoutes.GET("/:id/reconciliation/unreconciled", reconciliationController.GetUnreconciled)
 		}
 
 		expenseRoutes := api.Group("/expenses")
@@ -40,12 +68,57 @@ func SetupRouter(
 		{
 			categoryRoutes.GET("", categoryController.GetCategoriesByUserID)
 			categoryRoutes.POST("", categoryController.CreateCategory)
+			categoryRoutes.PUT("/order", categoryController.ReorderCategories)
+			categoryRoutes.PUT("/:id", categoryController.UpdateCategoryByID)
 			categoryRoutes.DELETE("/:id", categoryController.DeleteCategoryByID)
+			categoryRoutes.POST("/:id/merge", categoryController.MergeCategory)
+			categoryRoutes.POST("/:id/archive", categoryController.ArchiveCategory)
+			categoryRoutes.POST("/:id/unarchive", categoryController.UnarchiveCategory)
 		}
 
 		analyticsRoutes := api.Group("/analytics")
 		{
 			analyticsRoutes.GET("", analyticsController.GetAnalytics)
+			analyticsRoutes.GET("/compare", analyticsController.CompareTravels)
+			analyticsRoutes.GET("/statistics", analyticsController.GetStatistics)
+			analyticsRoutes.GET("/review", analyticsController.GetReview)
 		}
+
+		reconciliationRoutes := api.Group("/reconciliation")
+		{
+			reconciliationRoutes.POST("/:id/confirm", reconciliationController.ConfirmMatch)
+			reconciliationRoutes.POST("/:id/reject", reconciliationController.RejectMatch)
+			reconciliationRoutes.POST("/:id/merge", reconciliationController.MergeMatch)
+		}
+
+		reportRoutes := api.Group("/reports")
+		{
+			reportRoutes.GET("", reportController.GetReports)
+			reportRoutes.POST("", reportController.CreateReport)
+			reportRoutes.GET("/pending", reportController.GetPendingReports)
+			reportRoutes.GET("/:id", reportController.GetReport)
+			reportRoutes.DELETE("/:id", reportController.DeleteReport)
+			reportRoutes.POST("/:id/submit", reportController.SubmitReport)
+			reportRoutes.POST("/:id/approve", reportController.ApproveReport)
+			reportRoutes.POST("/:id/reject", reportController.RejectReport)
+			reportRoutes.POST("/:id/pay", reportController.PayReport)
+			reportRoutes.PUT("/:id/lines/:line_id", reportController.CommentReportLine)
+		}
+
+		reportJobRoutes := api.Group("/report-jobs")
+		{
+			reportJobRoutes.GET("/:id", tripReportController.GetTripReportJob)
+			reportJobRoutes.GET("/:id/file", tripReportController.DownloadTripReport)
+		}
+
+		policyRoutes := api.Group("/policies")
+		{
+			policyRoutes.GET("", policyController.GetPolicies)
+			policyRoutes.POST("", policyController.CreatePolicy)
+			policyRoutes.DELETE("/:id", policyController.DeletePolicy)
+		}
+
+		api.GET("/per-diem-rates", allowanceController.GetRates)
+		api.GET("/insights", insightController.GetInsights)
 	}
 }
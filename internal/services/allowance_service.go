@@ -0,0 +1,181 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRateNotFound  = errors.New("no allowance rate for this location")
+	ErrInvalidPeriod = errors.New("travel end date is before start date")
+)
+
+// Встроенные категории, в которые попадают начисления по нормативу
+const (
+	PerDiemCategory = "per_diem"
+	MileageCategory = "mileage"
+)
+
+// Правила расчёта суточных: в дни отъезда и возвращения начисляется часть ставки,
+// а за оплаченное питание ставка уменьшается на долю полной дневной ставки.
+const (
+	partialDayShare = 0.5
+	breakfastShare  = 0.2
+	lunchShare      = 0.4
+	dinnerShare     = 0.4
+)
+
+type AllowanceService struct {
+	repo         repository.AllowanceRepositoryInterface
+	categoryRepo repository.CategoryRepositoryInterface
+	expenses     ExpenseServiceInterface
+}
+
+func NewAllowanceService(repo repository.AllowanceRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface, expenses ExpenseServiceInterface) *AllowanceService {
+	return &AllowanceService{
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		expenses:     expenses,
+	}
+}
+
+func (s *AllowanceService) GetRates(ctx context.Context, country string) ([]models.PerDiemRate, error) {
+	return s.repo.GetRates(ctx, strings.ToUpper(strings.TrimSpace(country)))
+}
+
+// GeneratePerDiem рассчитывает суточные на каждый день поездки и заменяет ими
+// ранее начисленные. Если прежние суточные уже входят в какой-либо отчёт, пересчёт
+// запрещён (ErrExpenseReported). Описание записей составляется на языке loc.
+func (s *AllowanceService) GeneratePerDiem(ctx context.Context, travel *models.Travel, country string, city string, meals []models.MealsProvided, loc i18n.Locale) ([]models.Expense, error) {
+	if travel.EndDate.Before(travel.StartDate) {
+		return nil, ErrInvalidPeriod
+	}
+	rate, err := s.findRate(ctx, country, city)
+	if err != nil {
+		return nil, err
+	}
+
+	category, err := s.categoryRepo.GetCategoryByKey(ctx, PerDiemCategory)
+	if err != nil {
+		return nil, err
+	}
+
+	location := rate.Country
+	if rate.City != "" {
+		location = fmt.Sprintf("%s, %s", rate.Country, rate.City)
+	}
+	var entries []models.Expense
+	for _, day := range calculatePerDiem(rate.DailyRate, travel.StartDate, travel.EndDate, meals) {
+		entries = append(entries, models.Expense{
+			UserID:      travel.UserID,
+			TravelID:    travel.ID,
+			CategoryID:  category.ID,
+			Amount:      day.Amount,
+			CreatedAt:   day.Date,
+			Description: fmt.Sprintf(i18n.T(loc, "Per diem: %s"), location),
+			Kind:        models.KindPerDiem,
+		})
+	}
+
+	if err := s.expenses.ReplacePerDiem(ctx, travel.UserID, travel.ID, entries); err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		entries[i].Category = *category
+	}
+	return entries, nil
+}
+
+// CreateMileage начисляет компенсацию пробега. Если ставка не задана,
+// берётся ставка за километр для указанной страны или города.
+func (s *AllowanceService) CreateMileage(ctx context.Context, expense *models.Expense, rate float64, country string, city string) error {
+	if expense.Distance <= 0 {
+		return ErrInvalidDistance
+	}
+	if rate <= 0 {
+		found, err := s.findRate(ctx, country, city)
+		if err != nil {
+			return err
+		}
+		if found.MileageRate <= 0 {
+			return ErrRateNotFound
+		}
+		rate = found.MileageRate
+	}
+
+	category, err := s.categoryRepo.GetCategoryByKey(ctx, MileageCategory)
+	if err != nil {
+		return err
+	}
+	expense.Kind = models.KindMileage
+	expense.CategoryID = category.ID
+	expense.Category = *category
+	expense.Amount = roundMoney(expense.Distance * rate)
+
+	_, err = s.expenses.CreateExpense(ctx, expense, true)
+	return err
+}
+
+func (s *AllowanceService) findRate(ctx context.Context, country string, city string) (*models.PerDiemRate, error) {
+	rate, err := s.repo.FindRate(ctx, strings.ToUpper(strings.TrimSpace(country)), strings.TrimSpace(city))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRateNotFound
+		}
+		return nil, err
+	}
+	return rate, nil
+}
+
+type perDiemDay struct {
+	Date   time.Time
+	Amount float64
+}
+
+// calculatePerDiem возвращает суточные по дням поездки. Дни, за которые
+// после вычета питания ничего не положено, пропускаются.
+func calculatePerDiem(dailyRate float64, start, end time.Time, meals []models.MealsProvided) []perDiemDay {
+	provided := make(map[string]models.MealsProvided, len(meals))
+	for _, m := range meals {
+		provided[m.Date.Format("2006-01-02")] = m
+	}
+
+	first := dayOf(start)
+	last := dayOf(end)
+	var days []perDiemDay
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		amount := dailyRate
+		if day.Equal(first) || day.Equal(last) {
+			amount = dailyRate * partialDayShare
+		}
+		if m, ok := provided[day.Format("2006-01-02")]; ok {
+			if m.Breakfast {
+				amount -= dailyRate * breakfastShare
+			}
+			if m.Lunch {
+				amount -= dailyRate * lunchShare
+			}
+			if m.Dinner {
+				amount -= dailyRate * dinnerShare
+			}
+		}
+		if amount <= 0 {
+			continue
+		}
+		days = append(days, perDiemDay{Date: day, Amount: roundMoney(amount)})
+	}
+	return days
+}
+
+func roundMoney(v float64) float64 {
+	return math.Round(v*100) / 100
+}
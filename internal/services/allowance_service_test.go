@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestCalculatePerDiem(t *testing.T) {
+	start := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+
+	t.Run("partial first and last day", func(t *testing.T) {
+		days := calculatePerDiem(1000, start, start.AddDate(0, 0, 2), nil)
+		assert.Len(t, days, 3)
+		assert.Equal(t, 500.0, days[0].Amount)
+		assert.Equal(t, 1000.0, days[1].Amount)
+		assert.Equal(t, 500.0, days[2].Amount)
+	})
+
+	t.Run("meal deductions", func(t *testing.T) {
+		meals := []models.MealsProvided{
+			{Date: start.AddDate(0, 0, 1), Breakfast: true, Dinner: true},
+			{Date: start, Lunch: true, Dinner: true},
+		}
+		days := calculatePerDiem(1000, start, start.AddDate(0, 0, 2), meals)
+		assert.Len(t, days, 2)
+		assert.Equal(t, start.AddDate(0, 0, 1), days[0].Date)
+		assert.Equal(t, 400.0, days[0].Amount)
+		assert.Equal(t, 500.0, days[1].Amount)
+	})
+
+	t.Run("one day trip", func(t *testing.T) {
+		days := calculatePerDiem(1000, start, start, nil)
+		assert.Len(t, days, 1)
+		assert.Equal(t, 500.0, days[0].Amount)
+	})
+}
+
+func TestAllowanceService_GeneratePerDiem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAllowanceRepositoryInterface(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenses := mocks.NewMockExpenseServiceInterface(ctrl)
+	service := NewAllowanceService(mockRepo, mockCategoryRepo, mockExpenses)
+	ctx := context.Background()
+
+	start := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	travel := &models.Travel{ID: 2, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 1)}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().FindRate(ctx, "DE", "Berlin").Return(&models.PerDiemRate{Country: "DE", City: "Berlin", DailyRate: 5000}, nil)
+		mockCategoryRepo.EXPECT().GetCategoryByKey(ctx, PerDiemCategory).Return(&models.Category{ID: 11, Name: "Суточные", Key: PerDiemCategory}, nil)
+		mockExpenses.EXPECT().
+			ReplacePerDiem(ctx, uint(1), uint(2), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ uint, entries []models.Expense) error {
+				assert.Len(t, entries, 2)
+				for _, e := range entries {
+					assert.Equal(t, models.KindPerDiem, e.Kind)
+					assert.Equal(t, uint(11), e.CategoryID)
+					assert.Equal(t, 2500.0, e.Amount)
+					assert.Equal(t, "Per diem: DE, Berlin", e.Description)
+				}
+				return nil
+			})
+
+		entries, err := service.GeneratePerDiem(ctx, travel, "de", "Berlin", nil, i18n.EN)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+	})
+
+	t.Run("unknown location", func(t *testing.T) {
+		mockRepo.EXPECT().FindRate(ctx, "XX", "").Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.GeneratePerDiem(ctx, travel, "XX", "", nil, i18n.RU)
+		assert.ErrorIs(t, err, ErrRateNotFound)
+	})
+
+	t.Run("entries in a report", func(t *testing.T) {
+		mockRepo.EXPECT().FindRate(ctx, "DE", "").Return(&models.PerDiemRate{Country: "DE", DailyRate: 4500}, nil)
+		mockCategoryRepo.EXPECT().GetCategoryByKey(ctx, PerDiemCategory).Return(&models.Category{ID: 11, Key: PerDiemCategory}, nil)
+		mockExpenses.EXPECT().ReplacePerDiem(ctx, uint(1), uint(2), gomock.Any()).Return(ErrExpenseReported)
+
+		_, err := service.GeneratePerDiem(ctx, travel, "DE", "", nil, i18n.RU)
+		assert.ErrorIs(t, err, ErrExpenseReported)
+	})
+}
+
+func TestAllowanceService_CreateMileage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockAllowanceRepositoryInterface(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenses := mocks.NewMockExpenseServiceInterface(ctrl)
+	service := NewAllowanceService(mockRepo, mockCategoryRepo, mockExpenses)
+	ctx := context.Background()
+
+	t.Run("rate from table", func(t *testing.T) {
+		expense := &models.Expense{UserID: 1, TravelID: 2, Distance: 120.5}
+		mockRepo.EXPECT().FindRate(ctx, "DE", "").Return(&models.PerDiemRate{Country: "DE", MileageRate: 30}, nil)
+		mockCategoryRepo.EXPECT().GetCategoryByKey(ctx, MileageCategory).Return(&models.Category{ID: 12, Name: "Пробег", Key: MileageCategory}, nil)
+		mockExpenses.EXPECT().CreateExpense(ctx, expense, true).Return(nil, nil)
+
+		err := service.CreateMileage(ctx, expense, 0, "DE", "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.KindMileage, expense.Kind)
+		assert.Equal(t, 3615.0, expense.Amount)
+	})
+
+	t.Run("invalid distance", func(t *testing.T) {
+		err := service.CreateMileage(ctx, &models.Expense{UserID: 1, TravelID: 2}, 10, "", "")
+		assert.ErrorIs(t, err, ErrInvalidDistance)
+	})
+}
@@ -3,25 +3,66 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
+	"sort"
 	"time"
 	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
 	"wanderwallet/internal/repository"
 )
 
+// Шаг временного ряда аналитики
+const (
+	GranularityDay   = "day"
+	GranularityWeek  = "week"
+	GranularityMonth = "month"
+)
+
+// maxTimeBuckets ограничивает длину ряда, чтобы день за несколько лет не раздувал ответ
+const maxTimeBuckets = 1000
+
+var (
+	ErrInvalidGranularity = errors.New("granularity must be day, week or month")
+	ErrInvalidTimezone    = errors.New("unknown timezone")
+	ErrTooManyBuckets     = errors.New("too many buckets: use a coarser granularity or a shorter period")
+	ErrTravelNotFound     = errors.New("travel not found")
+)
+
 type AnalyticsService struct {
-	repo repository.ExpenseRepositoryInterface
+	repo         repository.AnalyticsRepositoryInterface
+	categoryRepo repository.CategoryRepositoryInterface
+	travelRepo   repository.TravelRepositoryInterface
 }
 
-func NewAnalyticsService(repo repository.ExpenseRepositoryInterface) *AnalyticsService {
+func NewAnalyticsService(repo repository.AnalyticsRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface, travelRepo repository.TravelRepositoryInterface) *AnalyticsService {
 	return &AnalyticsService{
-		repo: repo,
+		repo:         repo,
+		categoryRepo: categoryRepo,
+		travelRepo:   travelRepo,
 	}
 }
 
-func (s *AnalyticsService) Aggregate(ctx context.Context, userID uint, travelID uint, from, to time.Time) (*dto.AnalyticsResponse, error) {
+// Aggregate считает суммы по поездке. Временной ряд строится с шагом granularity
+// по датам в часовом поясе tz; пустые периоды заполняются нулями.
+// Названия встроенных категорий переводятся на язык loc.
+func (s *AnalyticsService) Aggregate(ctx context.Context, userID uint, travelID uint, from, to time.Time, granularity, tz string, loc i18n.Locale) (*dto.AnalyticsResponse, error) {
 	if travelID == 0 {
 		return nil, errors.New("travel_id is required")
 	}
+	if granularity == "" {
+		granularity = GranularityDay
+	}
+	if granularity != GranularityDay && granularity != GranularityWeek && granularity != GranularityMonth {
+		return nil, ErrInvalidGranularity
+	}
+	if tz == "" {
+		tz = "UTC"
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return nil, ErrInvalidTimezone
+	}
 
 	var fromPtr, toPtr *time.Time
 	if !from.IsZero() {
@@ -35,24 +76,352 @@ func (s *AnalyticsService) Aggregate(ctx context.Context, userID uint, travelID
 		return nil, errors.New("from date must be before to date")
 	}
 
-	total, err := s.repo.TotalSum(ctx, userID, travelID, fromPtr, toPtr)
+	sums, err := s.repo.Summarize(ctx, repository.SummaryQuery{
+		UserID:   userID,
+		TravelID: travelID,
+		From:     fromPtr,
+		To:       toPtr,
+		Unit:     granularity,
+		Timezone: tz,
+	})
 	if err != nil {
 		return nil, err
 	}
+	byCat := sums.ByCategory
 
-	byCat, err := s.repo.SumByCategory(ctx, userID, travelID, fromPtr, toPtr)
+	categories, err := s.categoryRepo.GetAllCategories(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	byDay, err := s.repo.SumByDay(ctx, userID, travelID, fromPtr, toPtr)
+	series, err := fillTimeSeries(sums.ByPeriod, granularity, from, to)
 	if err != nil {
 		return nil, err
 	}
 
+	for i := range categories {
+		categories[i].Name = i18n.CategoryName(loc, categories[i].Key, categories[i].Name)
+	}
+	flat := make(map[string]dto.AmountSummary, len(byCat))
+	for i, c := range byCat {
+		byCat[i].Category = i18n.CategoryName(loc, c.CategoryKey, c.Category)
+		flat[byCat[i].Category] = toAmountSummary(c.AmountSummary)
+	}
+
 	return &dto.AnalyticsResponse{
-		Total:      total,
-		ByCategory: byCat,
-		ByDay:      byDay,
+		Total:        toAmountSummary(sums.Total),
+		ByCategory:   flat,
+		CategoryTree: buildCategoryTree(categories, byCat),
+		Granularity:  granularity,
+		Timezone:     tz,
+		Series:       series,
 	}, nil
 }
+
+// fillTimeSeries раскладывает суммы по непрерывному ряду периодов от from до to.
+// Ряд охватывает и границы запроса, и все периоды с записями.
+func fillTimeSeries(sums []repository.PeriodSummary, granularity string, from, to time.Time) ([]dto.TimeBucket, error) {
+	byStart := make(map[string]repository.AmountSummary, len(sums))
+	var first, last time.Time
+	for _, p := range sums {
+		start, err := time.Parse("2006-01-02", p.Start)
+		if err != nil {
+			return nil, err
+		}
+		byStart[p.Start] = p.AmountSummary
+		if first.IsZero() || start.Before(first) {
+			first = start
+		}
+		if start.After(last) {
+			last = start
+		}
+	}
+	// в другом часовом поясе запись у границы периода может попасть на соседнюю дату
+	if !from.IsZero() && (first.IsZero() || from.Before(first)) {
+		first = from
+	}
+	if !to.IsZero() && to.After(last) {
+		last = to
+	}
+
+	series := make([]dto.TimeBucket, 0)
+	if first.IsZero() || last.IsZero() {
+		return series, nil
+	}
+	for start := periodStart(first, granularity); !start.After(last); {
+		if len(series) == maxTimeBuckets {
+			return nil, ErrTooManyBuckets
+		}
+		next := nextPeriod(start, granularity)
+		key := start.Format("2006-01-02")
+		series = append(series, dto.TimeBucket{
+			Start:         key,
+			End:           next.AddDate(0, 0, -1).Format("2006-01-02"),
+			AmountSummary: toAmountSummary(byStart[key]),
+		})
+		start = next
+	}
+	return series, nil
+}
+
+// periodStart возвращает начало дня, недели (понедельник, как date_trunc) или месяца
+func periodStart(t time.Time, granularity string) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch granularity {
+	case GranularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case GranularityMonth:
+		return day.AddDate(0, 0, 1-day.Day())
+	}
+	return day
+}
+
+func nextPeriod(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case GranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case GranularityMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// buildCategoryTree раскладывает суммы по дереву категорий и сворачивает их
+// к родителям. Ветки без записей в дерево не попадают.
+func buildCategoryTree(categories []models.Category, sums []repository.CategorySummary) []dto.CategoryNode {
+	known := make(map[uint]models.Category, len(categories))
+	for _, c := range categories {
+		known[c.ID] = c
+	}
+
+	own := make(map[uint]repository.AmountSummary, len(sums))
+	var roots []models.Category
+	for _, s := range sums {
+		own[s.CategoryID] = s.AmountSummary
+		// записи без категории или в недоступной категории показываются отдельным корнем
+		if _, ok := known[s.CategoryID]; !ok {
+			roots = append(roots, models.Category{ID: s.CategoryID, Name: s.Category})
+		}
+	}
+
+	children := make(map[uint][]models.Category)
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if _, ok := known[*c.ParentID]; ok {
+				children[*c.ParentID] = append(children[*c.ParentID], c)
+				continue
+			}
+		}
+		roots = append(roots, c)
+	}
+
+	var build func(c models.Category) (dto.CategoryNode, repository.AmountSummary, bool)
+	build = func(c models.Category) (dto.CategoryNode, repository.AmountSummary, bool) {
+		self, used := own[c.ID]
+		total := self
+		node := dto.CategoryNode{
+			ID:   fmt.Sprintf("%v", c.ID),
+			Name: c.Name,
+			Own:  toAmountSummary(self),
+		}
+		for _, child := range sortedByName(children[c.ID]) {
+			childNode, childTotal, childUsed := build(child)
+			if !childUsed {
+				continue
+			}
+			used = true
+			total = total.Add(childTotal)
+			node.Children = append(node.Children, childNode)
+		}
+		node.Total = toAmountSummary(total)
+		return node, total, used
+	}
+
+	tree := make([]dto.CategoryNode, 0)
+	for _, c := range sortedByName(roots) {
+		if node, _, used := build(c); used {
+			tree = append(tree, node)
+		}
+	}
+	return tree
+}
+
+func sortedByName(categories []models.Category) []models.Category {
+	sort.Slice(categories, func(i, j int) bool {
+		return categories[i].Name < categories[j].Name
+	})
+	return categories
+}
+
+func toAmountSummary(a repository.AmountSummary) dto.AmountSummary {
+	return dto.AmountSummary{
+		Gross:      a.Gross,
+		Refunds:    a.Refunds,
+		Income:     a.Income,
+		Allowances: a.Allowances,
+		Net:        a.Net(),
+	}
+}
+
+// CompareTravels возвращает сопоставимые показатели поездок travelIDs (пустой список —
+// все поездки пользователя) и их отличие от средней по всем поездкам пользователя с записями.
+// Стоимостью поездки считаются чистые траты: расходы за вычетом возвратов.
+func (s *AnalyticsService) CompareTravels(ctx context.Context, userID uint, travelIDs []uint, loc i18n.Locale) (*dto.TravelComparisonResponse, error) {
+	travels, err := s.travelRepo.GetTravelsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := s.repo.SumByTravelAndCategory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byTravel := make(map[uint][]repository.CategorySummary)
+	for _, sum := range sums {
+		sum.Category = i18n.CategoryName(loc, sum.CategoryKey, sum.Category)
+		byTravel[sum.TravelID] = append(byTravel[sum.TravelID], sum.CategorySummary)
+	}
+
+	all := make(map[uint]dto.TripMetrics, len(travels))
+	order := make([]uint, 0, len(travels))
+	var withEntries []dto.TripMetrics
+	for _, t := range travels {
+		metrics := tripMetrics(t, byTravel[t.ID])
+		all[t.ID] = metrics
+		order = append(order, t.ID)
+		if len(byTravel[t.ID]) > 0 {
+			withEntries = append(withEntries, metrics)
+		}
+	}
+
+	selected := order
+	if len(travelIDs) > 0 {
+		selected = travelIDs
+	}
+	average := averageTrip(withEntries)
+	resp := &dto.TravelComparisonResponse{
+		Trips:   make([]dto.TripMetrics, 0, len(selected)),
+		Average: average,
+	}
+	for _, id := range selected {
+		metrics, ok := all[id]
+		if !ok {
+			return nil, ErrTravelNotFound
+		}
+		metrics.VsAverage = tripDeviation(metrics, average)
+		resp.Trips = append(resp.Trips, metrics)
+	}
+	return resp, nil
+}
+
+func tripMetrics(t models.Travel, sums []repository.CategorySummary) dto.TripMetrics {
+	days := int(t.EndDate.Sub(t.StartDate).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+	participants := t.Participants
+	if participants < 1 {
+		participants = 1
+	}
+
+	var total repository.AmountSummary
+	for _, s := range sums {
+		total = total.Add(s.AmountSummary)
+	}
+	net := total.Net()
+
+	categories := make([]dto.TripCategoryMetrics, 0, len(sums))
+	for _, s := range sums {
+		c := dto.TripCategoryMetrics{
+			CategoryID: fmt.Sprintf("%v", s.CategoryID),
+			Category:   s.Category,
+			Net:        roundMoney(s.Net()),
+			PerDay:     roundMoney(s.Net() / float64(days)),
+		}
+		if net != 0 {
+			c.Share = roundShare(s.Net() / net)
+		}
+		categories = append(categories, c)
+	}
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i].Net != categories[j].Net {
+			return categories[i].Net > categories[j].Net
+		}
+		return categories[i].Category < categories[j].Category
+	})
+
+	return dto.TripMetrics{
+		TravelID:        fmt.Sprintf("%v", t.ID),
+		Title:           t.Title,
+		StartDate:       t.StartDate.Format("2006-01-02"),
+		EndDate:         t.EndDate.Format("2006-01-02"),
+		Days:            days,
+		Participants:    participants,
+		Total:           toAmountSummary(total),
+		PerDay:          roundMoney(net / float64(days)),
+		PerPerson:       roundMoney(net / float64(participants)),
+		PerPersonPerDay: roundMoney(net / float64(days*participants)),
+		Categories:      categories,
+	}
+}
+
+// averageTrip усредняет показатели поездок; доля категории, которой в поездке нет, считается нулевой
+func averageTrip(trips []dto.TripMetrics) dto.TripAverage {
+	avg := dto.TripAverage{Trips: len(trips), CategoryShares: make(map[string]float64)}
+	if len(trips) == 0 {
+		return avg
+	}
+	n := float64(len(trips))
+	for _, t := range trips {
+		avg.Net += t.Total.Net / n
+		avg.Days += float64(t.Days) / n
+		avg.PerDay += t.PerDay / n
+		avg.PerPersonPerDay += t.PerPersonPerDay / n
+		for _, c := range t.Categories {
+			avg.CategoryShares[c.Category] += c.Share / n
+		}
+	}
+	avg.Net = roundMoney(avg.Net)
+	avg.Days = roundMoney(avg.Days)
+	avg.PerDay = roundMoney(avg.PerDay)
+	avg.PerPersonPerDay = roundMoney(avg.PerPersonPerDay)
+	for k, v := range avg.CategoryShares {
+		avg.CategoryShares[k] = roundShare(v)
+	}
+	return avg
+}
+
+func tripDeviation(t dto.TripMetrics, avg dto.TripAverage) dto.TripDeviation {
+	d := dto.TripDeviation{
+		Net:                    roundMoney(t.Total.Net - avg.Net),
+		NetPercent:             percentDiff(t.Total.Net, avg.Net),
+		PerDay:                 roundMoney(t.PerDay - avg.PerDay),
+		PerDayPercent:          percentDiff(t.PerDay, avg.PerDay),
+		PerPersonPerDay:        roundMoney(t.PerPersonPerDay - avg.PerPersonPerDay),
+		PerPersonPerDayPercent: percentDiff(t.PerPersonPerDay, avg.PerPersonPerDay),
+		Days:                   roundMoney(float64(t.Days) - avg.Days),
+		CategoryShares:         make(map[string]float64, len(avg.CategoryShares)),
+	}
+	for name, share := range avg.CategoryShares {
+		d.CategoryShares[name] = -share
+	}
+	for _, c := range t.Categories {
+		d.CategoryShares[c.Category] += c.Share
+	}
+	for name, v := range d.CategoryShares {
+		d.CategoryShares[name] = roundShare(v)
+	}
+	return d
+}
+
+func percentDiff(value, avg float64) *float64 {
+	if avg == 0 {
+		return nil
+	}
+	p := roundMoney((value - avg) / avg * 100)
+	return &p
+}
+
+func roundShare(v float64) float64 {
+	return math.Round(v*10000) / 10000
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildCategoryTree(t *testing.T) {
+	userID := uint(1)
+	food, street, cafe, transport := uint(1), uint(10), uint(11), uint(2)
+	categories := []models.Category{
+		{ID: food, Name: "Питание", Builtin: true},
+		{ID: transport, Name: "Транспорт", Builtin: true},
+		{ID: street, Name: "Уличная еда", UserID: &userID, ParentID: &food},
+		{ID: cafe, Name: "Кафе", UserID: &userID, ParentID: &food},
+		{ID: 12, Name: "Шаурма", UserID: &userID, ParentID: &street},
+	}
+	sums := []repository.CategorySummary{
+		{CategoryID: food, Category: "Питание", AmountSummary: repository.AmountSummary{Gross: 100}},
+		{CategoryID: street, Category: "Уличная еда", AmountSummary: repository.AmountSummary{Gross: 50, Refunds: 10}},
+		{CategoryID: 12, Category: "Шаурма", AmountSummary: repository.AmountSummary{Gross: 25}},
+		{CategoryID: 0, Category: "", AmountSummary: repository.AmountSummary{Gross: 5}},
+	}
+
+	tree := buildCategoryTree(categories, sums)
+	assert.Len(t, tree, 2)
+
+	assert.Equal(t, "", tree[0].Name)
+	assert.Equal(t, 5.0, tree[0].Total.Gross)
+
+	foodNode := tree[1]
+	assert.Equal(t, "Питание", foodNode.Name)
+	assert.Equal(t, 100.0, foodNode.Own.Gross)
+	assert.Equal(t, 175.0, foodNode.Total.Gross)
+	assert.Equal(t, 165.0, foodNode.Total.Net)
+	assert.Len(t, foodNode.Children, 1)
+
+	streetNode := foodNode.Children[0]
+	assert.Equal(t, "Уличная еда", streetNode.Name)
+	assert.Equal(t, 75.0, streetNode.Total.Gross)
+	assert.Len(t, streetNode.Children, 1)
+	assert.Equal(t, 25.0, streetNode.Children[0].Own.Gross)
+}
+
+func TestFillTimeSeries(t *testing.T) {
+	sums := []repository.PeriodSummary{
+		{Start: "2024-05-06", AmountSummary: repository.AmountSummary{Gross: 100}},
+		{Start: "2024-05-20", AmountSummary: repository.AmountSummary{Gross: 50, Refunds: 10}},
+	}
+
+	t.Run("week gaps filled", func(t *testing.T) {
+		series, err := fillTimeSeries(sums, GranularityWeek, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Len(t, series, 3)
+		assert.Equal(t, "2024-05-06", series[0].Start)
+		assert.Equal(t, "2024-05-12", series[0].End)
+		assert.Equal(t, 0.0, series[1].Gross)
+		assert.Equal(t, 40.0, series[2].Net)
+	})
+
+	t.Run("bounds extend series", func(t *testing.T) {
+		from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, 5, 31, 0, 0, 0, 0, time.UTC)
+		series, err := fillTimeSeries(sums, GranularityDay, from, to)
+		assert.NoError(t, err)
+		assert.Len(t, series, 31)
+		assert.Equal(t, "2024-05-01", series[0].Start)
+		assert.Equal(t, 100.0, series[5].Gross)
+	})
+
+	t.Run("month", func(t *testing.T) {
+		months := []repository.PeriodSummary{{Start: "2024-05-01", AmountSummary: repository.AmountSummary{Gross: 150}}}
+		from := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+		series, err := fillTimeSeries(months, GranularityMonth, from, time.Time{})
+		assert.NoError(t, err)
+		assert.Len(t, series, 5)
+		assert.Equal(t, "2024-01-01", series[0].Start)
+		assert.Equal(t, "2024-02-29", series[1].End)
+		assert.Equal(t, 150.0, series[4].Gross)
+	})
+
+	t.Run("too many buckets", func(t *testing.T) {
+		from := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		_, err := fillTimeSeries(sums, GranularityDay, from, time.Time{})
+		assert.ErrorIs(t, err, ErrTooManyBuckets)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		series, err := fillTimeSeries(nil, GranularityDay, time.Time{}, time.Time{})
+		assert.NoError(t, err)
+		assert.Empty(t, series)
+	})
+}
+
+func TestAnalyticsService_CompareTravels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockTravelRepo := mocks.NewMockTravelRepositoryInterface(ctrl)
+	service := NewAnalyticsService(mockRepo, mockCategoryRepo, mockTravelRepo)
+	ctx := context.Background()
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	travels := []models.Travel{
+		{ID: 1, Title: "Рим", StartDate: start, EndDate: start.AddDate(0, 0, 3), Participants: 2},
+		{ID: 2, Title: "Берлин", StartDate: start, EndDate: start.AddDate(0, 0, 1), Participants: 1},
+		{ID: 3, Title: "Планы", StartDate: start, EndDate: start},
+	}
+	sums := []repository.TravelCategorySummary{
+		{TravelID: 1, CategorySummary: repository.CategorySummary{CategoryID: 2, Category: "Питание", CategoryKey: "food", AmountSummary: repository.AmountSummary{Gross: 600, Refunds: 200}}},
+		{TravelID: 1, CategorySummary: repository.CategorySummary{CategoryID: 3, Category: "Жильё", CategoryKey: "lodging", AmountSummary: repository.AmountSummary{Gross: 400}}},
+		{TravelID: 2, CategorySummary: repository.CategorySummary{CategoryID: 2, Category: "Питание", CategoryKey: "food", AmountSummary: repository.AmountSummary{Gross: 200}}},
+	}
+
+	t.Run("selected trips", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockRepo.EXPECT().SumByTravelAndCategory(ctx, uint(1)).Return(sums, nil)
+
+		resp, err := service.CompareTravels(ctx, 1, []uint{1}, i18n.EN)
+		assert.NoError(t, err)
+		assert.Len(t, resp.Trips, 1)
+		assert.Equal(t, 2, resp.Average.Trips) // поездка без записей в среднее не входит
+
+		rome := resp.Trips[0]
+		assert.Equal(t, 4, rome.Days)
+		assert.Equal(t, 800.0, rome.Total.Net)
+		assert.Equal(t, 200.0, rome.PerDay)
+		assert.Equal(t, 100.0, rome.PerPersonPerDay)
+		assert.Equal(t, "Food", rome.Categories[0].Category)
+		assert.Equal(t, 0.5, rome.Categories[0].Share)
+
+		assert.Equal(t, 150.0, resp.Average.PerDay)
+		assert.Equal(t, 50.0, rome.VsAverage.PerDay)
+		assert.InDelta(t, 33.33, *rome.VsAverage.PerDayPercent, 0.01)
+		assert.Equal(t, -0.25, rome.VsAverage.CategoryShares["Food"])
+		assert.Equal(t, 0.25, rome.VsAverage.CategoryShares["Lodging"])
+	})
+
+	t.Run("unknown trip", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockRepo.EXPECT().SumByTravelAndCategory(ctx, uint(1)).Return(sums, nil)
+
+		_, err := service.CompareTravels(ctx, 1, []uint{42}, i18n.RU)
+		assert.ErrorIs(t, err, ErrTravelNotFound)
+	})
+}
@@ -3,23 +3,52 @@ package services
 import (
 	"context"
 	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/repository"
+
+	"gorm.io/gorm"
 )
 
 type CategoryService struct {
 	repo        repository.CategoryRepositoryInterface
 	expenseRepo repository.ExpenseRepositoryInterface
+	policies    PolicyServiceInterface
+	insights    InsightServiceInterface
 }
 
 var (
 	ErrCategoryHasLinkedExpenses = errors.New("category has linked expenses")
+	ErrCategoryHasChildren       = errors.New("category has subcategories")
+	ErrBuiltinCategory           = errors.New("builtin category cannot be changed")
+	ErrInvalidCategoryName       = errors.New("category name must not be empty")
+	ErrInvalidParentCategory     = errors.New("parent category not found or would create a cycle")
+	ErrInvalidMergeTarget        = errors.New("merge target must be another visible category")
+	ErrInvalidCategoryIcon       = errors.New("category icon must be a short key of latin letters, digits, '-' or '_'")
+	ErrInvalidCategoryColor      = errors.New("category color must be in #rrggbb format")
+	ErrInvalidCategoryOrder      = errors.New("order must list distinct visible categories")
+)
+
+var (
+	iconPattern  = regexp.MustCompile(`^[a-z0-9_-]{1,32}$`)
+	colorPattern = regexp.MustCompile(`^#[0-9a-f]{6}$`)
+)
+
+// Что делать с подкатегориями при удалении родителя
+const (
+	ChildrenLift   = "lift"   // перенести к родителю удаляемой категории
+	ChildrenDelete = "delete" // удалить вместе со всеми потомками
 )
 
-func NewCategoryService(repo repository.CategoryRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface) *CategoryService {
+func NewCategoryService(repo repository.CategoryRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface, policies PolicyServiceInterface, insights InsightServiceInterface) *CategoryService {
 	return &CategoryService{
 		repo:        repo,
 		expenseRepo: expenseRepo,
+		policies:    policies,
+		insights:    insights,
 	}
 }
 
@@ -27,19 +56,118 @@ func (s *CategoryService) GetCategoryByID(ctx context.Context, id uint) (*models
 	return s.repo.GetCategoryByID(ctx, id)
 }
 
-func (s *CategoryService) GetCategoryByName(ctx context.Context, name string) (*models.Category, error) {
-	return s.repo.GetCategoryByName(ctx, name)
+// GetCategoryByName ищет категорию среди встроенных и категорий пользователя userID.
+// Встроенную категорию можно указать ключом или названием на любом поддерживаемом языке.
+func (s *CategoryService) GetCategoryByName(ctx context.Context, userID uint, name string) (*models.Category, error) {
+	category, err := s.repo.GetCategoryByName(ctx, userID, name)
+	if err == nil || !errors.Is(err, gorm.ErrRecordNotFound) {
+		return category, err
+	}
+	key, ok := i18n.CategoryKey(name)
+	if !ok {
+		return nil, err
+	}
+	return s.repo.GetCategoryByKey(ctx, key)
 }
 
-func (s *CategoryService) GetAllCategories(ctx context.Context, userID uint) ([]models.Category, error) {
-	return s.repo.GetAllCategories(ctx, userID)
+// ResolveCategory находит категорию для записи пользователя: по ID, если он задан,
+// иначе по названию. Чужая категория считается ненайденной.
+func (s *CategoryService) ResolveCategory(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Category, error) {
+	if categoryID == nil {
+		return s.GetCategoryByName(ctx, userID, name)
+	}
+	category, err := s.repo.GetCategoryByID(ctx, *categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if category.UserID != nil && *category.UserID != userID {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return category, nil
+}
+
+// GetAllCategories возвращает категории в порядке пользователя.
+// Архивные категории скрыты из выбора и попадают в список только при includeArchived.
+func (s *CategoryService) GetAllCategories(ctx context.Context, userID uint, includeArchived bool) ([]models.Category, error) {
+	categories, err := s.repo.GetAllCategories(ctx, userID)
+	if err != nil || includeArchived {
+		return categories, err
+	}
+	active := make([]models.Category, 0, len(categories))
+	for _, c := range categories {
+		if !c.Archived {
+			active = append(active, c)
+		}
+	}
+	return active, nil
 }
 
 func (s *CategoryService) CreateCategory(ctx context.Context, category *models.Category) error {
+	if _, ok := i18n.CategoryKey(category.Name); ok {
+		return repository.ErrCategoryExists
+	}
+	if err := normalizeAppearance(category); err != nil {
+		return err
+	}
+	if category.ParentID != nil {
+		if err := s.checkParent(ctx, category, *category.ParentID); err != nil {
+			return err
+		}
+	}
 	return s.repo.CreateCategory(ctx, category)
 }
 
-func (s *CategoryService) DeleteCategory(ctx context.Context, categoryID uint) error {
+// UpdateCategory переименовывает категорию и, если parentID задан, переносит её
+// под другую категорию. parentID = 0 делает категорию корневой.
+// Иконка и цвет берутся из category и проверяются перед сохранением.
+func (s *CategoryService) UpdateCategory(ctx context.Context, category *models.Category, name string, parentID *uint) error {
+	if category.Builtin {
+		return ErrBuiltinCategory
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ErrInvalidCategoryName
+	}
+	if _, ok := i18n.CategoryKey(name); ok {
+		return repository.ErrCategoryExists
+	}
+	if err := normalizeAppearance(category); err != nil {
+		return err
+	}
+	previousParent := category.ParentID
+	if parentID != nil {
+		if *parentID == 0 {
+			category.ParentID = nil
+		} else {
+			if err := s.checkParent(ctx, category, *parentID); err != nil {
+				return err
+			}
+			category.ParentID = parentID
+		}
+	}
+	category.Name = name
+	if err := s.repo.UpdateCategory(ctx, category); err != nil {
+		return err
+	}
+	// правила по родительской категории действуют и на подкатегории
+	if !sameParent(previousParent, category.ParentID) && category.UserID != nil {
+		if err := s.policies.EvaluateUser(ctx, *category.UserID); err != nil {
+			log.Printf("Failed to evaluate policies for user %d: %v\n", *category.UserID, err)
+		}
+	}
+	return nil
+}
+
+func sameParent(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// DeleteCategory удаляет категорию без расходов. Если у неё есть подкатегории,
+// нужно выбрать, что с ними сделать: ChildrenLift или ChildrenDelete.
+func (s *CategoryService) DeleteCategory(ctx context.Context, categoryID uint, children string) error {
 	hasExpenses, err := s.expenseRepo.ExistsByCategoryID(ctx, categoryID)
 	if err != nil {
 		return err
@@ -48,5 +176,194 @@ func (s *CategoryService) DeleteCategory(ctx context.Context, categoryID uint) e
 		return ErrCategoryHasLinkedExpenses
 	}
 
-	return s.repo.DeleteCategory(ctx, categoryID)
+	descendants, err := s.descendants(ctx, categoryID)
+	if err != nil {
+		return err
+	}
+	if len(descendants) == 0 {
+		return s.repo.DeleteCategory(ctx, categoryID)
+	}
+
+	switch children {
+	case ChildrenLift:
+		return s.repo.DeleteCategory(ctx, categoryID)
+	case ChildrenDelete:
+		for _, id := range descendants {
+			hasExpenses, err := s.expenseRepo.ExistsByCategoryID(ctx, id)
+			if err != nil {
+				return err
+			}
+			if hasExpenses {
+				return ErrCategoryHasLinkedExpenses
+			}
+		}
+		return s.repo.DeleteCategories(ctx, append(descendants, categoryID))
+	default:
+		return ErrCategoryHasChildren
+	}
+}
+
+// ReorderCategories задаёт порядок категорий пользователя. Перечисленные категории
+// идут первыми в указанном порядке, остальные — следом, сохраняя прежний порядок.
+func (s *CategoryService) ReorderCategories(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Category, error) {
+	if len(categoryIDs) == 0 {
+		return nil, ErrInvalidCategoryOrder
+	}
+	categories, err := s.repo.GetAllCategories(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]models.Category, len(categories))
+	for _, c := range categories {
+		byID[c.ID] = c
+	}
+
+	listed := make(map[uint]bool, len(categoryIDs))
+	ordered := make([]models.Category, 0, len(categories))
+	for _, id := range categoryIDs {
+		c, ok := byID[id]
+		if !ok || listed[id] {
+			return nil, ErrInvalidCategoryOrder
+		}
+		listed[id] = true
+		ordered = append(ordered, c)
+	}
+	for _, c := range categories {
+		if !listed[c.ID] {
+			ordered = append(ordered, c)
+		}
+	}
+
+	if err := s.repo.SetCategoryOrder(ctx, userID, ordered); err != nil {
+		return nil, err
+	}
+	for i := range ordered {
+		ordered[i].SortOrder = i + 1
+	}
+	return ordered, nil
+}
+
+// SetArchived архивирует категорию или возвращает её из архива. Встроенная
+// категория архивируется только для пользователя userID.
+func (s *CategoryService) SetArchived(ctx context.Context, userID uint, category *models.Category, archived bool) error {
+	if err := s.repo.SetCategoryArchived(ctx, userID, category, archived); err != nil {
+		return err
+	}
+	category.Archived = archived
+	return nil
+}
+
+// MergeCategory переносит расходы, правила трат и подкатегории source в категорию
+// targetID и удаляет source. Всё выполняется одной транзакцией. Категорию с расходами
+// из утверждённых или оплаченных отчётов слить нельзя (ErrExpenseLocked): возмещённые
+// записи не меняются задним числом.
+func (s *CategoryService) MergeCategory(ctx context.Context, source *models.Category, targetID uint) (*models.Category, error) {
+	if source.Builtin || source.UserID == nil {
+		return nil, ErrBuiltinCategory
+	}
+	if targetID == source.ID {
+		return nil, ErrInvalidMergeTarget
+	}
+	target, err := s.repo.GetCategoryByID(ctx, targetID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidMergeTarget
+		}
+		return nil, err
+	}
+	if target.UserID != nil && *target.UserID != *source.UserID {
+		return nil, ErrInvalidMergeTarget
+	}
+
+	// если target вложен в source, он поднимается на место source,
+	// иначе подкатегории source образовали бы с ним цикл
+	descendant, err := s.isDescendant(ctx, target, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	if descendant {
+		target.ParentID = source.ParentID
+	}
+
+	if err := s.repo.MergeCategory(ctx, source, target); err != nil {
+		return nil, err
+	}
+	if err := s.policies.EvaluateUser(ctx, *source.UserID); err != nil {
+		log.Printf("Failed to evaluate policies for user %d: %v\n", *source.UserID, err)
+	}
+	// категория расходов сменилась во всех поездках, а с ней и история для сравнения
+	s.insights.Schedule(*source.UserID)
+	return target, nil
+}
+
+// normalizeAppearance приводит иконку и цвет к нижнему регистру и проверяет формат.
+// Пустые значения допустимы: фронтенд покажет оформление по умолчанию.
+func normalizeAppearance(category *models.Category) error {
+	category.Icon = strings.ToLower(strings.TrimSpace(category.Icon))
+	category.Color = strings.ToLower(strings.TrimSpace(category.Color))
+	if category.Icon != "" && !iconPattern.MatchString(category.Icon) {
+		return ErrInvalidCategoryIcon
+	}
+	if category.Color != "" && !colorPattern.MatchString(category.Color) {
+		return ErrInvalidCategoryColor
+	}
+	return nil
+}
+
+func (s *CategoryService) isDescendant(ctx context.Context, category *models.Category, ancestorID uint) (bool, error) {
+	visited := make(map[uint]bool)
+	for parentID := category.ParentID; parentID != nil && !visited[*parentID]; {
+		if *parentID == ancestorID {
+			return true, nil
+		}
+		visited[*parentID] = true
+		parent, err := s.repo.GetCategoryByID(ctx, *parentID)
+		if err != nil {
+			return false, err
+		}
+		parentID = parent.ParentID
+	}
+	return false, nil
+}
+
+// checkParent — родитель виден пользователю категории и не является её потомком
+func (s *CategoryService) checkParent(ctx context.Context, category *models.Category, parentID uint) error {
+	visited := make(map[uint]bool)
+	for id := parentID; ; {
+		if (category.ID != 0 && id == category.ID) || visited[id] {
+			return ErrInvalidParentCategory
+		}
+		visited[id] = true
+		parent, err := s.repo.GetCategoryByID(ctx, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidParentCategory
+			}
+			return err
+		}
+		if id == parentID && parent.UserID != nil && (category.UserID == nil || *parent.UserID != *category.UserID) {
+			return ErrInvalidParentCategory
+		}
+		if parent.ParentID == nil {
+			return nil
+		}
+		id = *parent.ParentID
+	}
+}
+
+func (s *CategoryService) descendants(ctx context.Context, categoryID uint) ([]uint, error) {
+	var ids []uint
+	queue := []uint{categoryID}
+	for len(queue) > 0 {
+		children, err := s.repo.GetChildCategories(ctx, queue[0])
+		if err != nil {
+			return nil, err
+		}
+		queue = queue[1:]
+		for _, c := range children {
+			ids = append(ids, c.ID)
+			queue = append(queue, c.ID)
+		}
+	}
+	return ids, nil
 }
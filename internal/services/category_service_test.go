@@ -11,6 +11,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
 )
 
 func TestCategoryService_CreateCategory_Success(t *testing.T) {
@@ -20,7 +21,9 @@ func TestCategoryService_CreateCategory_Success(t *testing.T) {
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
 
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 
 	userID := uint(1)
 	category := &models.Category{
@@ -46,7 +49,9 @@ func TestCategoryService_CreateCategory_NameTaken(t *testing.T) {
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
 
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 
 	userID := uint(1)
 	category := &models.Category{
@@ -70,7 +75,9 @@ func TestCategoryService_DeleteCategory_NoExpenses_Success(t *testing.T) {
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
 
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 	ctx := context.Background()
 
 	categoryID := uint(5)
@@ -78,12 +85,15 @@ func TestCategoryService_DeleteCategory_NoExpenses_Success(t *testing.T) {
 	mockExpenseRepo.EXPECT().
 		ExistsByCategoryID(ctx, categoryID).
 		Return(false, nil)
+	mockRepo.EXPECT().
+		GetChildCategories(ctx, categoryID).
+		Return(nil, nil)
 
 	mockRepo.EXPECT().
 		DeleteCategory(ctx, categoryID).
 		Return(nil)
 
-	err := svc.DeleteCategory(ctx, categoryID)
+	err := svc.DeleteCategory(ctx, categoryID, "")
 	assert.NoError(t, err)
 }
 
@@ -94,7 +104,9 @@ func TestCategoryService_DeleteCategory_HasExpenses_Error(t *testing.T) {
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
 
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 
 	categoryID := uint(5)
 	ctx := context.Background()
@@ -103,7 +115,7 @@ func TestCategoryService_DeleteCategory_HasExpenses_Error(t *testing.T) {
 		ExistsByCategoryID(ctx, categoryID).
 		Return(true, nil)
 
-	err := svc.DeleteCategory(ctx, categoryID)
+	err := svc.DeleteCategory(ctx, categoryID, "")
 	assert.ErrorIs(t, err, services.ErrCategoryHasLinkedExpenses)
 }
 
@@ -114,19 +126,24 @@ func TestCategoryService_DeleteCategory_RepoError(t *testing.T) {
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
 
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 	ctx := context.Background()
 
 	categoryID := uint(5)
 	mockExpenseRepo.EXPECT().
 		ExistsByCategoryID(ctx, categoryID).
 		Return(false, nil)
+	mockRepo.EXPECT().
+		GetChildCategories(ctx, categoryID).
+		Return(nil, nil)
 
 	mockRepo.EXPECT().
 		DeleteCategory(ctx, categoryID).
 		Return(errors.New("db error"))
 
-	err := svc.DeleteCategory(ctx, categoryID)
+	err := svc.DeleteCategory(ctx, categoryID, "")
 	assert.Error(t, err)
 }
 
@@ -136,7 +153,9 @@ func TestCategoryService_GetCategoryByID(t *testing.T) {
 
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 	ctx := context.Background()
 
 	category := &models.Category{ID: 1, Name: "Test"}
@@ -153,15 +172,101 @@ func TestCategoryService_GetAllCategories(t *testing.T) {
 
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 	ctx := context.Background()
 
-	cats := []models.Category{{ID: 1, Name: "A"}, {ID: 2, Name: "B"}}
-	mockRepo.EXPECT().GetAllCategories(ctx, uint(1)).Return(cats, nil)
+	cats := []models.Category{{ID: 1, Name: "A"}, {ID: 2, Name: "B", Archived: true}}
+	mockRepo.EXPECT().GetAllCategories(ctx, uint(1)).Return(cats, nil).Times(2)
 
-	res, err := svc.GetAllCategories(ctx, 1)
+	res, err := svc.GetAllCategories(ctx, 1, true)
 	assert.NoError(t, err)
 	assert.Equal(t, cats, res)
+
+	res, err = svc.GetAllCategories(ctx, 1, false)
+	assert.NoError(t, err)
+	assert.Equal(t, cats[:1], res)
+}
+
+func TestCategoryService_ReorderCategories(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+	userID := uint(1)
+	cats := []models.Category{
+		{ID: 1, Name: "Транспорт", Builtin: true},
+		{ID: 2, Name: "Питание", Builtin: true},
+		{ID: 7, Name: "Кофе", UserID: &userID, SortOrder: 1},
+	}
+
+	t.Run("listed first, rest keep order", func(t *testing.T) {
+		mockRepo.EXPECT().GetAllCategories(ctx, userID).Return(cats, nil)
+		mockRepo.EXPECT().
+			SetCategoryOrder(ctx, userID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uint, ordered []models.Category) error {
+				ids := make([]uint, 0, len(ordered))
+				for _, c := range ordered {
+					ids = append(ids, c.ID)
+				}
+				assert.Equal(t, []uint{7, 2, 1}, ids)
+				return nil
+			})
+
+		res, err := svc.ReorderCategories(ctx, userID, []uint{7, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, res[0].SortOrder)
+		assert.Equal(t, 3, res[2].SortOrder)
+	})
+
+	t.Run("unknown or repeated category", func(t *testing.T) {
+		mockRepo.EXPECT().GetAllCategories(ctx, userID).Return(cats, nil).Times(2)
+
+		_, err := svc.ReorderCategories(ctx, userID, []uint{7, 99})
+		assert.ErrorIs(t, err, services.ErrInvalidCategoryOrder)
+
+		_, err = svc.ReorderCategories(ctx, userID, []uint{7, 7})
+		assert.ErrorIs(t, err, services.ErrInvalidCategoryOrder)
+	})
+}
+
+func TestCategoryService_CreateCategory_Appearance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+	userID := uint(1)
+
+	t.Run("normalized", func(t *testing.T) {
+		category := &models.Category{Name: "Кофе", UserID: &userID, Icon: " Coffee ", Color: "#A1B2C3"}
+		mockRepo.EXPECT().CreateCategory(ctx, category).Return(nil)
+
+		err := svc.CreateCategory(ctx, category)
+		assert.NoError(t, err)
+		assert.Equal(t, "coffee", category.Icon)
+		assert.Equal(t, "#a1b2c3", category.Color)
+	})
+
+	t.Run("invalid color", func(t *testing.T) {
+		err := svc.CreateCategory(ctx, &models.Category{Name: "Кофе", UserID: &userID, Color: "red"})
+		assert.ErrorIs(t, err, services.ErrInvalidCategoryColor)
+	})
+
+	t.Run("invalid icon", func(t *testing.T) {
+		err := svc.CreateCategory(ctx, &models.Category{Name: "Кофе", UserID: &userID, Icon: "<svg>"})
+		assert.ErrorIs(t, err, services.ErrInvalidCategoryIcon)
+	})
 }
 
 func TestCategoryService_GetCategoryByName(t *testing.T) {
@@ -170,13 +275,308 @@ func TestCategoryService_GetCategoryByName(t *testing.T) {
 
 	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
 	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	svc := services.NewCategoryService(mockRepo, mockExpenseRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
 
 	ctx := context.Background()
 	cat := &models.Category{ID: 1, Name: "Food"}
-	mockRepo.EXPECT().GetCategoryByName(ctx, "Food").Return(cat, nil)
+	mockRepo.EXPECT().GetCategoryByName(ctx, uint(1), "Food").Return(cat, nil)
 
-	res, err := svc.GetCategoryByName(ctx, "Food")
+	res, err := svc.GetCategoryByName(ctx, 1, "Food")
 	assert.NoError(t, err)
 	assert.Equal(t, cat, res)
 }
+
+func TestCategoryService_GetCategoryByName_BuiltinTranslation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+
+	ctx := context.Background()
+	cat := &models.Category{ID: 2, Name: "Питание", Key: "food", Builtin: true}
+	mockRepo.EXPECT().GetCategoryByName(ctx, uint(1), "food").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.EXPECT().GetCategoryByKey(ctx, "food").Return(cat, nil)
+
+	res, err := svc.GetCategoryByName(ctx, 1, "food")
+	assert.NoError(t, err)
+	assert.Equal(t, cat, res)
+
+	mockRepo.EXPECT().GetCategoryByName(ctx, uint(1), "Coffee").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err = svc.GetCategoryByName(ctx, 1, "Coffee")
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+}
+
+func TestCategoryService_ResolveCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+	userID, otherID := uint(1), uint(2)
+
+	t.Run("by id", func(t *testing.T) {
+		id := uint(7)
+		cat := &models.Category{ID: 7, Name: "Кофе", UserID: &userID}
+		mockRepo.EXPECT().GetCategoryByID(ctx, id).Return(cat, nil)
+
+		res, err := svc.ResolveCategory(ctx, userID, &id, "ignored")
+		assert.NoError(t, err)
+		assert.Equal(t, cat, res)
+	})
+
+	t.Run("another user's category", func(t *testing.T) {
+		id := uint(8)
+		mockRepo.EXPECT().GetCategoryByID(ctx, id).Return(&models.Category{ID: 8, UserID: &otherID}, nil)
+
+		_, err := svc.ResolveCategory(ctx, userID, &id, "")
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	})
+
+	t.Run("by name", func(t *testing.T) {
+		cat := &models.Category{ID: 2, Name: "Питание", Builtin: true}
+		mockRepo.EXPECT().GetCategoryByName(ctx, userID, "Питание").Return(cat, nil)
+
+		res, err := svc.ResolveCategory(ctx, userID, nil, "Питание")
+		assert.NoError(t, err)
+		assert.Equal(t, cat, res)
+	})
+}
+
+func TestCategoryService_CreateCategory_BuiltinTranslationTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	userID := uint(1)
+
+	err := svc.CreateCategory(context.Background(), &models.Category{Name: "Lodging", UserID: &userID})
+	assert.ErrorIs(t, err, repository.ErrCategoryExists)
+}
+
+func TestCategoryService_DeleteCategory_WithChildren(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+
+	expectTree := func() {
+		mockExpenseRepo.EXPECT().ExistsByCategoryID(ctx, uint(5)).Return(false, nil)
+		mockRepo.EXPECT().GetChildCategories(ctx, uint(5)).Return([]models.Category{{ID: 6}}, nil)
+		mockRepo.EXPECT().GetChildCategories(ctx, uint(6)).Return([]models.Category{{ID: 7}}, nil)
+		mockRepo.EXPECT().GetChildCategories(ctx, uint(7)).Return(nil, nil)
+	}
+
+	t.Run("choice required", func(t *testing.T) {
+		expectTree()
+
+		err := svc.DeleteCategory(ctx, 5, "")
+		assert.ErrorIs(t, err, services.ErrCategoryHasChildren)
+	})
+
+	t.Run("lift children", func(t *testing.T) {
+		expectTree()
+		mockRepo.EXPECT().DeleteCategory(ctx, uint(5)).Return(nil)
+
+		err := svc.DeleteCategory(ctx, 5, services.ChildrenLift)
+		assert.NoError(t, err)
+	})
+
+	t.Run("delete subtree", func(t *testing.T) {
+		expectTree()
+		mockExpenseRepo.EXPECT().ExistsByCategoryID(ctx, uint(6)).Return(false, nil)
+		mockExpenseRepo.EXPECT().ExistsByCategoryID(ctx, uint(7)).Return(false, nil)
+		mockRepo.EXPECT().DeleteCategories(ctx, []uint{6, 7, 5}).Return(nil)
+
+		err := svc.DeleteCategory(ctx, 5, services.ChildrenDelete)
+		assert.NoError(t, err)
+	})
+
+	t.Run("subtree has expenses", func(t *testing.T) {
+		expectTree()
+		mockExpenseRepo.EXPECT().ExistsByCategoryID(ctx, uint(6)).Return(true, nil)
+
+		err := svc.DeleteCategory(ctx, 5, services.ChildrenDelete)
+		assert.ErrorIs(t, err, services.ErrCategoryHasLinkedExpenses)
+	})
+}
+
+func TestCategoryService_CreateCategory_WithParent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+	userID, otherID := uint(1), uint(2)
+	parentID := uint(3)
+
+	t.Run("builtin parent", func(t *testing.T) {
+		category := &models.Category{Name: "Уличная еда", UserID: &userID, ParentID: &parentID}
+		mockRepo.EXPECT().GetCategoryByID(ctx, parentID).Return(&models.Category{ID: 3, Name: "Питание", Builtin: true}, nil)
+		mockRepo.EXPECT().CreateCategory(ctx, category).Return(nil)
+
+		err := svc.CreateCategory(ctx, category)
+		assert.NoError(t, err)
+	})
+
+	t.Run("another user's parent", func(t *testing.T) {
+		category := &models.Category{Name: "Уличная еда", UserID: &userID, ParentID: &parentID}
+		mockRepo.EXPECT().GetCategoryByID(ctx, parentID).Return(&models.Category{ID: 3, UserID: &otherID}, nil)
+
+		err := svc.CreateCategory(ctx, category)
+		assert.ErrorIs(t, err, services.ErrInvalidParentCategory)
+	})
+}
+
+func TestCategoryService_UpdateCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+	userID := uint(1)
+
+	t.Run("success", func(t *testing.T) {
+		cat := &models.Category{ID: 5, Name: "Coffee", UserID: &userID}
+		mockRepo.EXPECT().UpdateCategory(ctx, cat).Return(nil)
+
+		err := svc.UpdateCategory(ctx, cat, "  Кофе ", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "Кофе", cat.Name)
+	})
+
+	t.Run("name taken", func(t *testing.T) {
+		cat := &models.Category{ID: 5, Name: "Coffee", UserID: &userID}
+		mockRepo.EXPECT().UpdateCategory(ctx, cat).Return(repository.ErrCategoryExists)
+
+		err := svc.UpdateCategory(ctx, cat, "Чай", nil)
+		assert.ErrorIs(t, err, repository.ErrCategoryExists)
+	})
+
+	t.Run("builtin", func(t *testing.T) {
+		cat := &models.Category{ID: 1, Name: "Питание", Builtin: true}
+
+		err := svc.UpdateCategory(ctx, cat, "Еда", nil)
+		assert.ErrorIs(t, err, services.ErrBuiltinCategory)
+	})
+
+	t.Run("empty name", func(t *testing.T) {
+		cat := &models.Category{ID: 5, Name: "Coffee", UserID: &userID}
+
+		err := svc.UpdateCategory(ctx, cat, "   ", nil)
+		assert.ErrorIs(t, err, services.ErrInvalidCategoryName)
+	})
+
+	t.Run("move under own descendant", func(t *testing.T) {
+		cat := &models.Category{ID: 5, Name: "Coffee", UserID: &userID}
+		childID := uint(6)
+		mockRepo.EXPECT().GetCategoryByID(ctx, childID).Return(&models.Category{ID: 6, UserID: &userID, ParentID: &cat.ID}, nil)
+
+		err := svc.UpdateCategory(ctx, cat, "Coffee", &childID)
+		assert.ErrorIs(t, err, services.ErrInvalidParentCategory)
+	})
+
+	t.Run("move to root", func(t *testing.T) {
+		parentID := uint(3)
+		cat := &models.Category{ID: 5, Name: "Coffee", UserID: &userID, ParentID: &parentID}
+		root := uint(0)
+		mockRepo.EXPECT().UpdateCategory(ctx, cat).Return(nil)
+		mockPolicies.EXPECT().EvaluateUser(ctx, userID).Return(nil)
+
+		err := svc.UpdateCategory(ctx, cat, "Coffee", &root)
+		assert.NoError(t, err)
+		assert.Nil(t, cat.ParentID)
+	})
+}
+
+func TestCategoryService_MergeCategory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	svc := services.NewCategoryService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+	userID, otherID := uint(1), uint(2)
+
+	t.Run("into builtin", func(t *testing.T) {
+		source := &models.Category{ID: 5, Name: "Coffee", UserID: &userID}
+		target := &models.Category{ID: 1, Name: "Питание", Builtin: true}
+		mockRepo.EXPECT().GetCategoryByID(ctx, uint(1)).Return(target, nil)
+		mockRepo.EXPECT().MergeCategory(ctx, source, target).Return(nil)
+		mockPolicies.EXPECT().EvaluateUser(ctx, userID).Return(nil)
+		mockInsights.EXPECT().Schedule(userID)
+
+		res, err := svc.MergeCategory(ctx, source, 1)
+		assert.NoError(t, err)
+		assert.Equal(t, target, res)
+	})
+
+	t.Run("into own descendant", func(t *testing.T) {
+		rootID := uint(3)
+		source := &models.Category{ID: 5, Name: "Питание в поездке", UserID: &userID, ParentID: &rootID}
+		child := &models.Category{ID: 6, UserID: &userID, ParentID: &source.ID}
+		mockRepo.EXPECT().GetCategoryByID(ctx, uint(6)).Return(child, nil)
+		mockRepo.EXPECT().MergeCategory(ctx, source, child).Return(nil)
+		mockPolicies.EXPECT().EvaluateUser(ctx, userID).Return(nil)
+		mockInsights.EXPECT().Schedule(userID)
+
+		_, err := svc.MergeCategory(ctx, source, 6)
+		assert.NoError(t, err)
+		assert.Equal(t, &rootID, child.ParentID)
+	})
+
+	t.Run("expenses in approved report", func(t *testing.T) {
+		source := &models.Category{ID: 5, Name: "Coffee", UserID: &userID}
+		target := &models.Category{ID: 1, Name: "Питание", Builtin: true}
+		mockRepo.EXPECT().GetCategoryByID(ctx, uint(1)).Return(target, nil)
+		mockRepo.EXPECT().MergeCategory(ctx, source, target).Return(repository.ErrExpenseLocked)
+
+		_, err := svc.MergeCategory(ctx, source, 1)
+		assert.ErrorIs(t, err, services.ErrExpenseLocked)
+	})
+
+	t.Run("another user's target", func(t *testing.T) {
+		source := &models.Category{ID: 5, UserID: &userID}
+		mockRepo.EXPECT().GetCategoryByID(ctx, uint(7)).Return(&models.Category{ID: 7, UserID: &otherID}, nil)
+
+		_, err := svc.MergeCategory(ctx, source, 7)
+		assert.ErrorIs(t, err, services.ErrInvalidMergeTarget)
+	})
+
+	t.Run("into itself", func(t *testing.T) {
+		source := &models.Category{ID: 5, UserID: &userID}
+
+		_, err := svc.MergeCategory(ctx, source, 5)
+		assert.ErrorIs(t, err, services.ErrInvalidMergeTarget)
+	})
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+	"wanderwallet/internal/charts"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+)
+
+// Виды графиков
+const (
+	ChartCategories = "categories" // круговая диаграмма по категориям верхнего уровня
+	ChartDaily      = "daily"      // столбцы по дням (или неделям, месяцам)
+	ChartCumulative = "cumulative" // нарастающий итог с линией бюджета
+)
+
+const (
+	ChartFormatSVG = "svg"
+	ChartFormatPNG = "png"
+)
+
+// maxPieSlices — сколько категорий показывается отдельно; остальные объединяются в «Прочее»
+const maxPieSlices = 8
+
+var (
+	ErrInvalidChart       = errors.New("chart must be categories, daily or cumulative")
+	ErrInvalidChartFormat = errors.New("format must be svg or png")
+	ErrInvalidChartSize   = errors.New("chart size is out of range")
+	ErrInvalidChartTheme  = errors.New("theme must be light or dark")
+)
+
+type ChartService struct {
+	analytics AnalyticsServiceInterfase
+}
+
+func NewChartService(analytics AnalyticsServiceInterfase) *ChartService {
+	return &ChartService{analytics: analytics}
+}
+
+// Render строит график kind по результату AnalyticsService.Aggregate для поездки
+// и возвращает изображение с его MIME-типом. Суммы — чистые траты.
+func (s *ChartService) Render(ctx context.Context, travel *models.Travel, kind string, req dto.ChartRequest, from, to time.Time, loc i18n.Locale) ([]byte, string, error) {
+	if kind != ChartCategories && kind != ChartDaily && kind != ChartCumulative {
+		return nil, "", ErrInvalidChart
+	}
+	if req.Format == "" {
+		req.Format = ChartFormatSVG
+	}
+	if req.Format != ChartFormatSVG && req.Format != ChartFormatPNG {
+		return nil, "", ErrInvalidChartFormat
+	}
+	if req.Width == 0 {
+		req.Width = charts.DefaultWidth
+	}
+	if req.Height == 0 {
+		req.Height = charts.DefaultHeight
+	}
+	if req.Width < charts.MinWidth || req.Width > charts.MaxWidth || req.Height < charts.MinHeight || req.Height > charts.MaxHeight {
+		return nil, "", ErrInvalidChartSize
+	}
+	theme, ok := charts.ThemeByName(req.Theme)
+	if !ok {
+		return nil, "", ErrInvalidChartTheme
+	}
+	granularity := req.Granularity
+	if kind == ChartCategories || granularity == "" {
+		granularity = GranularityDay
+	}
+
+	data, err := s.analytics.Aggregate(ctx, travel.UserID, travel.ID, from, to, granularity, req.Tz, loc)
+	if err != nil {
+		return nil, "", err
+	}
+
+	opts := charts.Options{Width: req.Width, Height: req.Height, Theme: theme}
+	canvas := buildChart(kind, data, opts, req.Style, travel.Budget, loc)
+
+	if req.Format == ChartFormatPNG {
+		image, err := canvas.PNG()
+		return image, "image/png", err
+	}
+	return canvas.SVG(), "image/svg+xml", nil
+}
+
+// buildChart собирает холст графика kind по данным аналитики; подписи переводятся на язык loc
+func buildChart(kind string, data *dto.AnalyticsResponse, opts charts.Options, style string, budget *float64, loc i18n.Locale) *charts.Canvas {
+	opts.Empty = i18n.T(loc, "No data")
+	switch kind {
+	case ChartCategories:
+		opts.Title = i18n.T(loc, "Spending by category")
+		return charts.Pie(opts, categorySlices(data.CategoryTree, loc), style != "pie")
+	case ChartDaily:
+		opts.Title = i18n.T(loc, "Spending by period")
+		values := make([]charts.Value, 0, len(data.Series))
+		for _, b := range data.Series {
+			values = append(values, charts.Value{Label: seriesLabel(b.Start, data.Granularity), Value: b.Net})
+		}
+		return charts.Bars(opts, values)
+	default:
+		opts.Title = i18n.T(loc, "Cumulative spending")
+		values := make([]charts.Value, 0, len(data.Series))
+		var sum float64
+		for _, b := range data.Series {
+			sum += b.Net
+			values = append(values, charts.Value{Label: seriesLabel(b.Start, data.Granularity), Value: roundMoney(sum)})
+		}
+		return charts.Line(opts, values, budget, i18n.T(loc, "Budget"))
+	}
+}
+
+// categorySlices — чистые траты корневых категорий по убыванию; хвост сворачивается в «Прочее»
+func categorySlices(tree []dto.CategoryNode, loc i18n.Locale) []charts.Value {
+	values := make([]charts.Value, 0, len(tree))
+	for _, node := range tree {
+		if node.Total.Net <= 0 {
+			continue
+		}
+		label := node.Name
+		if label == "" {
+			label = i18n.T(loc, "Other")
+		}
+		values = append(values, charts.Value{Label: label, Value: node.Total.Net})
+	}
+	sort.SliceStable(values, func(i, j int) bool { return values[i].Value > values[j].Value })
+	if len(values) <= maxPieSlices {
+		return values
+	}
+	other := charts.Value{Label: i18n.T(loc, "Other")}
+	for _, v := range values[maxPieSlices-1:] {
+		other.Value += v.Value
+	}
+	return append(values[:maxPieSlices-1], other)
+}
+
+// seriesLabel сокращает дату начала периода для подписи оси: ММ-ДД или ГГГГ-ММ для месяцев
+func seriesLabel(start string, granularity string) string {
+	if len(start) < len("2006-01-02") {
+		return start
+	}
+	if granularity == GranularityMonth {
+		return start[:7]
+	}
+	return start[5:]
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"image/png"
+	"testing"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChartService_Render(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockAnalytics := mocks.NewMockAnalyticsServiceInterfase(ctrl)
+	service := NewChartService(mockAnalytics)
+	ctx := context.Background()
+
+	budget := 500.0
+	travel := &models.Travel{ID: 7, UserID: 1, Budget: &budget}
+	data := &dto.AnalyticsResponse{
+		Granularity: GranularityDay,
+		CategoryTree: []dto.CategoryNode{
+			{Name: "Food", Total: dto.AmountSummary{Net: 300}},
+			{Name: "", Total: dto.AmountSummary{Net: 20}},
+		},
+		Series: []dto.TimeBucket{
+			{Start: "2024-05-01", AmountSummary: dto.AmountSummary{Net: 120}},
+			{Start: "2024-05-02", AmountSummary: dto.AmountSummary{Net: 200}},
+		},
+	}
+
+	t.Run("cumulative svg with budget", func(t *testing.T) {
+		mockAnalytics.EXPECT().Aggregate(ctx, uint(1), uint(7), time.Time{}, time.Time{}, GranularityDay, "", i18n.EN).Return(data, nil)
+
+		image, contentType, err := service.Render(ctx, travel, ChartCumulative, dto.ChartRequest{}, time.Time{}, time.Time{}, i18n.EN)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/svg+xml", contentType)
+		assert.Contains(t, string(image), "Cumulative spending")
+		assert.Contains(t, string(image), "Budget 500")
+	})
+
+	t.Run("categories png", func(t *testing.T) {
+		mockAnalytics.EXPECT().Aggregate(ctx, uint(1), uint(7), time.Time{}, time.Time{}, GranularityDay, "", i18n.RU).Return(data, nil)
+
+		req := dto.ChartRequest{Format: ChartFormatPNG, Width: 300, Height: 200, Theme: "dark", Granularity: GranularityMonth}
+		image, contentType, err := service.Render(ctx, travel, ChartCategories, req, time.Time{}, time.Time{}, i18n.RU)
+		assert.NoError(t, err)
+		assert.Equal(t, "image/png", contentType)
+		img, err := png.Decode(bytes.NewReader(image))
+		assert.NoError(t, err)
+		assert.Equal(t, 300, img.Bounds().Dx())
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		_, _, err := service.Render(ctx, travel, "radar", dto.ChartRequest{}, time.Time{}, time.Time{}, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidChart)
+
+		_, _, err = service.Render(ctx, travel, ChartDaily, dto.ChartRequest{Format: "gif"}, time.Time{}, time.Time{}, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidChartFormat)
+
+		_, _, err = service.Render(ctx, travel, ChartDaily, dto.ChartRequest{Width: 10000}, time.Time{}, time.Time{}, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidChartSize)
+
+		_, _, err = service.Render(ctx, travel, ChartDaily, dto.ChartRequest{Theme: "sepia"}, time.Time{}, time.Time{}, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidChartTheme)
+	})
+}
+
+func TestCategorySlices(t *testing.T) {
+	tree := make([]dto.CategoryNode, 0)
+	for i := 1; i <= 10; i++ {
+		tree = append(tree, dto.CategoryNode{Name: string(rune('A' + i - 1)), Total: dto.AmountSummary{Net: float64(i)}})
+	}
+	slices := categorySlices(tree, i18n.EN)
+	assert.Len(t, slices, maxPieSlices)
+	assert.Equal(t, "J", slices[0].Label)
+	assert.Equal(t, "Other", slices[maxPieSlices-1].Label)
+	assert.Equal(t, 6.0, slices[maxPieSlices-1].Value) // 1 + 2 + 3
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+var (
+	ErrEmptyReport            = errors.New("report has no reimbursable expenses")
+	ErrExpenseAlreadyReported = errors.New("expense is already included in another report")
+	ErrInvalidTransition      = repository.ErrInvalidTransition
+	ErrNotApprover            = errors.New("only an approver can perform this action")
+	ErrNotReportOwner         = errors.New("only the report owner can perform this action")
+	ErrReportLineNotFound     = errors.New("report line not found")
+	ErrReportNotEditable      = errors.New("report can only be changed in draft state")
+)
+
+// Допустимые переходы статусов отчёта
+var reportTransitions = map[models.ReportStatus][]models.ReportStatus{
+	models.ReportDraft:     {models.ReportSubmitted},
+	models.ReportSubmitted: {models.ReportApproved, models.ReportRejected},
+	models.ReportRejected:  {models.ReportSubmitted},
+	models.ReportApproved:  {models.ReportPaid},
+}
+
+type ExpenseReportService struct {
+	repo        repository.ExpenseReportRepositoryInterface
+	expenseRepo repository.ExpenseRepositoryInterface
+}
+
+func NewExpenseReportService(repo repository.ExpenseReportRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface) *ExpenseReportService {
+	return &ExpenseReportService{
+		repo:        repo,
+		expenseRepo: expenseRepo,
+	}
+}
+
+// CreateReport собирает черновик отчёта по поездке. Если expenseIDs пуст, в отчёт
+// попадают все расходы поездки, ещё не включённые в другие отчёты.
+// В отчёт попадают расходы и начисления по нормативу (суточные, пробег);
+// возвраты и доходы не возмещаются и в отчёт не включаются.
+func (s *ExpenseReportService) CreateReport(ctx context.Context, userID uint, travelID uint, title string, expenseIDs []uint) (*models.ExpenseReport, error) {
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, err
+	}
+	reportedIDs, err := s.repo.GetReportedExpenseIDs(ctx, travelID)
+	if err != nil {
+		return nil, err
+	}
+	reported := make(map[uint]bool, len(reportedIDs))
+	for _, id := range reportedIDs {
+		reported[id] = true
+	}
+
+	requested := make(map[uint]bool, len(expenseIDs))
+	for _, id := range expenseIDs {
+		if reported[id] {
+			return nil, ErrExpenseAlreadyReported
+		}
+		requested[id] = true
+	}
+
+	report := &models.ExpenseReport{
+		UserID:   userID,
+		TravelID: travelID,
+		Title:    title,
+		Status:   models.ReportDraft,
+	}
+	for _, e := range expenses {
+		if (e.Kind != models.KindExpense && !e.Kind.IsAllowance()) || reported[e.ID] {
+			continue
+		}
+		if len(requested) > 0 && !requested[e.ID] {
+			continue
+		}
+		delete(requested, e.ID)
+		report.Lines = append(report.Lines, models.ExpenseReportLine{ExpenseID: e.ID})
+	}
+	if len(requested) > 0 || len(report.Lines) == 0 {
+		return nil, ErrEmptyReport
+	}
+
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+	return s.repo.GetReportByID(ctx, report.ID)
+}
+
+func (s *ExpenseReportService) GetReportByID(ctx context.Context, reportID uint) (*models.ExpenseReport, error) {
+	return s.repo.GetReportByID(ctx, reportID)
+}
+
+func (s *ExpenseReportService) GetReportsByUserID(ctx context.Context, userID uint) ([]models.ExpenseReport, error) {
+	return s.repo.GetReportsByUserID(ctx, userID)
+}
+
+// GetPendingReports — очередь отчётов, ожидающих решения утверждающего
+func (s *ExpenseReportService) GetPendingReports(ctx context.Context, actor models.User) ([]models.ExpenseReport, error) {
+	if !actor.IsApprover() {
+		return nil, ErrNotApprover
+	}
+	return s.repo.GetReportsByStatus(ctx, models.ReportSubmitted)
+}
+
+// Submit отправляет отчёт на рассмотрение. Расходы отклонённого отчёта освобождаются и
+// могут попасть в новый отчёт, поэтому отклонённый отчёт нельзя отправить повторно,
+// пока какой-то его расход входит в другой действующий отчёт.
+func (s *ExpenseReportService) Submit(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	if report.UserID != actor.ID {
+		return ErrNotReportOwner
+	}
+	if report.Status == models.ReportRejected {
+		reportedIDs, err := s.repo.GetReportedExpenseIDs(ctx, report.TravelID)
+		if err != nil {
+			return err
+		}
+		reported := make(map[uint]bool, len(reportedIDs))
+		for _, id := range reportedIDs {
+			reported[id] = true
+		}
+		for _, line := range report.Lines {
+			if reported[line.ExpenseID] {
+				return ErrExpenseAlreadyReported
+			}
+		}
+	}
+	return s.transition(ctx, report, actor, models.ReportSubmitted, comment)
+}
+
+func (s *ExpenseReportService) Approve(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	if err := s.checkApprover(report, actor); err != nil {
+		return err
+	}
+	return s.transition(ctx, report, actor, models.ReportApproved, comment)
+}
+
+func (s *ExpenseReportService) Reject(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	if err := s.checkApprover(report, actor); err != nil {
+		return err
+	}
+	return s.transition(ctx, report, actor, models.ReportRejected, comment)
+}
+
+func (s *ExpenseReportService) MarkPaid(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error {
+	if err := s.checkApprover(report, actor); err != nil {
+		return err
+	}
+	return s.transition(ctx, report, actor, models.ReportPaid, comment)
+}
+
+// CommentLine сохраняет комментарий к строке отчёта. Владелец комментирует
+// черновик, утверждающий — отчёт на рассмотрении.
+func (s *ExpenseReportService) CommentLine(ctx context.Context, report *models.ExpenseReport, lineID uint, actor models.User, comment string) error {
+	var line *models.ExpenseReportLine
+	for i := range report.Lines {
+		if report.Lines[i].ID == lineID {
+			line = &report.Lines[i]
+			break
+		}
+	}
+	if line == nil {
+		return ErrReportLineNotFound
+	}
+
+	switch {
+	case report.UserID == actor.ID:
+		if report.Status != models.ReportDraft && report.Status != models.ReportRejected {
+			return ErrReportNotEditable
+		}
+		line.Comment = comment
+	case actor.IsApprover():
+		if report.Status != models.ReportSubmitted {
+			return ErrInvalidTransition
+		}
+		line.ApproverComment = comment
+	default:
+		return ErrNotReportOwner
+	}
+	return s.repo.UpdateLine(ctx, line)
+}
+
+func (s *ExpenseReportService) DeleteReport(ctx context.Context, report *models.ExpenseReport, actor models.User) error {
+	if report.UserID != actor.ID {
+		return ErrNotReportOwner
+	}
+	if report.Status != models.ReportDraft {
+		return ErrReportNotEditable
+	}
+	return s.repo.DeleteReport(ctx, report.ID)
+}
+
+// checkApprover — решение по отчёту принимает утверждающий, и не по своему отчёту
+func (s *ExpenseReportService) checkApprover(report *models.ExpenseReport, actor models.User) error {
+	if !actor.IsApprover() || report.UserID == actor.ID {
+		return ErrNotApprover
+	}
+	return nil
+}
+
+func (s *ExpenseReportService) transition(ctx context.Context, report *models.ExpenseReport, actor models.User, to models.ReportStatus, comment string) error {
+	allowed := false
+	for _, next := range reportTransitions[report.Status] {
+		if next == to {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return ErrInvalidTransition
+	}
+
+	transition := &models.ExpenseReportTransition{
+		ReportID:   report.ID,
+		FromStatus: report.Status,
+		ToStatus:   to,
+		ActorID:    actor.ID,
+		Comment:    comment,
+	}
+	if err := s.repo.UpdateStatus(ctx, report.ID, transition); err != nil {
+		return err
+	}
+	report.Status = to
+	report.History = append(report.History, *transition)
+	return nil
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpenseReportService_CreateReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseReportRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	service := NewExpenseReportService(mockRepo, mockExpenseRepo)
+	ctx := context.Background()
+
+	expenses := []models.Expense{
+		{ID: 1, Amount: 100, Kind: models.KindExpense},
+		{ID: 2, Amount: 50, Kind: models.KindRefund},
+		{ID: 3, Amount: 70, Kind: models.KindExpense},
+		{ID: 4, Amount: 30, Kind: models.KindExpense},
+	}
+
+	t.Run("all reimbursable expenses", func(t *testing.T) {
+		mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(2)).Return(expenses, nil)
+		mockRepo.EXPECT().GetReportedExpenseIDs(ctx, uint(2)).Return([]uint{4}, nil)
+		mockRepo.EXPECT().CreateReport(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, report *models.ExpenseReport) error {
+			assert.Equal(t, models.ReportDraft, report.Status)
+			assert.Len(t, report.Lines, 2)
+			assert.Equal(t, uint(1), report.Lines[0].ExpenseID)
+			assert.Equal(t, uint(3), report.Lines[1].ExpenseID)
+			report.ID = 9
+			return nil
+		})
+		mockRepo.EXPECT().GetReportByID(ctx, uint(9)).Return(&models.ExpenseReport{ID: 9}, nil)
+
+		report, err := service.CreateReport(ctx, 1, 2, "Конференция", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(9), report.ID)
+	})
+
+	t.Run("expense already reported", func(t *testing.T) {
+		mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(2)).Return(expenses, nil)
+		mockRepo.EXPECT().GetReportedExpenseIDs(ctx, uint(2)).Return([]uint{4}, nil)
+
+		_, err := service.CreateReport(ctx, 1, 2, "Конференция", []uint{3, 4})
+		assert.ErrorIs(t, err, ErrExpenseAlreadyReported)
+	})
+
+	t.Run("refund is not reimbursable", func(t *testing.T) {
+		mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(2)).Return(expenses, nil)
+		mockRepo.EXPECT().GetReportedExpenseIDs(ctx, uint(2)).Return(nil, nil)
+
+		_, err := service.CreateReport(ctx, 1, 2, "Конференция", []uint{2})
+		assert.ErrorIs(t, err, ErrEmptyReport)
+	})
+}
+
+func TestExpenseReportService_Workflow(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseReportRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	service := NewExpenseReportService(mockRepo, mockExpenseRepo)
+	ctx := context.Background()
+
+	owner := models.User{Login: "traveller"}
+	owner.ID = 1
+	approver := models.User{Login: "boss", Role: models.RoleApprover}
+	approver.ID = 2
+	report := &models.ExpenseReport{ID: 5, UserID: 1, Status: models.ReportDraft}
+
+	t.Run("approver cannot approve draft", func(t *testing.T) {
+		err := service.Approve(ctx, report, approver, "")
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+	})
+
+	t.Run("owner submits", func(t *testing.T) {
+		mockRepo.EXPECT().UpdateStatus(ctx, uint(5), gomock.Any()).Return(nil)
+
+		err := service.Submit(ctx, report, owner, "")
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReportSubmitted, report.Status)
+	})
+
+	t.Run("owner cannot approve own report", func(t *testing.T) {
+		err := service.Approve(ctx, report, owner, "")
+		assert.ErrorIs(t, err, ErrNotApprover)
+	})
+
+	t.Run("approver approves", func(t *testing.T) {
+		mockRepo.EXPECT().UpdateStatus(ctx, uint(5), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uint, tr *models.ExpenseReportTransition) error {
+				assert.Equal(t, models.ReportSubmitted, tr.FromStatus)
+				assert.Equal(t, models.ReportApproved, tr.ToStatus)
+				assert.Equal(t, uint(2), tr.ActorID)
+				return nil
+			})
+
+		err := service.Approve(ctx, report, approver, "ok")
+		assert.NoError(t, err)
+		assert.Equal(t, models.ReportApproved, report.Status)
+		assert.Len(t, report.History, 2)
+	})
+
+	t.Run("concurrent transition wins", func(t *testing.T) {
+		mockRepo.EXPECT().UpdateStatus(ctx, uint(5), gomock.Any()).Return(ErrInvalidTransition)
+
+		err := service.MarkPaid(ctx, report, approver, "")
+		assert.ErrorIs(t, err, ErrInvalidTransition)
+		assert.Equal(t, models.ReportApproved, report.Status)
+		assert.Len(t, report.History, 2)
+	})
+
+	t.Run("approved report cannot be deleted", func(t *testing.T) {
+		err := service.DeleteReport(ctx, report, owner)
+		assert.ErrorIs(t, err, ErrReportNotEditable)
+	})
+
+	t.Run("rejected report is not resubmitted with expenses reported again", func(t *testing.T) {
+		rejected := &models.ExpenseReport{ID: 6, UserID: 1, TravelID: 2, Status: models.ReportRejected,
+			Lines: []models.ExpenseReportLine{{ExpenseID: 3}, {ExpenseID: 4}}}
+		mockRepo.EXPECT().GetReportedExpenseIDs(ctx, uint(2)).Return([]uint{4}, nil)
+
+		err := service.Submit(ctx, rejected, owner, "")
+		assert.ErrorIs(t, err, ErrExpenseAlreadyReported)
+		assert.Equal(t, models.ReportRejected, rejected.Status)
+
+		mockRepo.EXPECT().GetReportedExpenseIDs(ctx, uint(2)).Return(nil, nil)
+		mockRepo.EXPECT().UpdateStatus(ctx, uint(6), gomock.Any()).Return(nil)
+		assert.NoError(t, service.Submit(ctx, rejected, owner, ""))
+	})
+}
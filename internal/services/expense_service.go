@@ -2,23 +2,170 @@ package services
 
 import (
 	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
 	"time"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/repository"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPossibleDuplicate = errors.New("possible duplicate expense")
+	ErrInvalidAmount     = errors.New("amount must be positive")
+	ErrInvalidKind       = errors.New("unknown expense kind")
+	ErrInvalidRefundLink = errors.New("refund must reference an expense of the same travel")
+	ErrExpenseLocked     = repository.ErrExpenseLocked
+	ErrExpenseReported   = repository.ErrExpenseReported
+	ErrInvalidDistance   = errors.New("mileage distance must be positive")
 )
 
+// Порог похожести комментариев, начиная с которого расходы считаются дублями
+const duplicateCommentSimilarity = 0.5
+
 type ExpenseService struct {
-	repo repository.ExpenseRepositoryInterface
+	repo            repository.ExpenseRepositoryInterface
+	policies        PolicyServiceInterface
+	insights        InsightServiceInterface
+	duplicateWindow time.Duration
 }
 
-func NewExpenseService(repo repository.ExpenseRepositoryInterface) *ExpenseService {
+func NewExpenseService(repo repository.ExpenseRepositoryInterface, policies PolicyServiceInterface, insights InsightServiceInterface, duplicateWindow time.Duration) *ExpenseService {
 	return &ExpenseService{
-		repo: repo,
+		repo:            repo,
+		policies:        policies,
+		insights:        insights,
+		duplicateWindow: duplicateWindow,
+	}
+}
+
+// CreateExpense сохраняет расход. Если force не задан и найдены вероятные дубли,
+// расход не создаётся: возвращаются их ID и ErrPossibleDuplicate.
+func (s *ExpenseService) CreateExpense(ctx context.Context, expense *models.Expense, force bool) ([]uint, error) {
+	if err := normalizeEntry(ctx, s.repo, expense); err != nil {
+		return nil, err
+	}
+	if !force {
+		duplicates, err := s.FindDuplicates(ctx, expense)
+		if err != nil {
+			return nil, err
+		}
+		if len(duplicates) > 0 {
+			ids := make([]uint, 0, len(duplicates))
+			for _, d := range duplicates {
+				ids = append(ids, d.ID)
+			}
+			return ids, ErrPossibleDuplicate
+		}
+	}
+	if err := s.repo.CreateExpense(ctx, expense); err != nil {
+		return nil, err
+	}
+	s.afterChange(ctx, expense.UserID, expense.TravelID)
+	return nil, nil
+}
+
+// FindDuplicates возвращает расходы той же поездки с той же суммой, датой в пределах
+// окна и похожим комментарием.
+func (s *ExpenseService) FindDuplicates(ctx context.Context, expense *models.Expense) ([]models.Expense, error) {
+	from := expense.CreatedAt.Add(-s.duplicateWindow)
+	to := expense.CreatedAt.Add(s.duplicateWindow)
+	candidates, err := s.repo.FindSameAmountExpenses(ctx, expense.UserID, expense.TravelID, expense.Amount, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	duplicates := make([]models.Expense, 0)
+	for _, c := range candidates {
+		if c.ID == expense.ID || c.Kind != expense.Kind {
+			continue
+		}
+		if similarComments(c.Description, expense.Description) {
+			duplicates = append(duplicates, c)
+		}
+	}
+	return duplicates, nil
+}
+
+// ScanDuplicates группирует вероятные дубли внутри поездки.
+// Группа — связная компонента из попарно похожих расходов; одиночки не возвращаются.
+func (s *ExpenseService) ScanDuplicates(ctx context.Context, userID uint, travelID uint) ([][]models.Expense, error) {
+	expenses, err := s.repo.GetExpensesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, err
+	}
+
+	parent := make([]int, len(expenses))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for i := range expenses {
+		for j := i + 1; j < len(expenses); j++ {
+			if s.isDuplicatePair(expenses[i], expenses[j]) {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	byRoot := make(map[int][]models.Expense)
+	var roots []int
+	for i, e := range expenses {
+		root := find(i)
+		if _, ok := byRoot[root]; !ok {
+			roots = append(roots, root)
+		}
+		byRoot[root] = append(byRoot[root], e)
+	}
+	sort.Ints(roots)
+
+	groups := make([][]models.Expense, 0)
+	for _, root := range roots {
+		if len(byRoot[root]) > 1 {
+			groups = append(groups, byRoot[root])
+		}
+	}
+	return groups, nil
+}
+
+func (s *ExpenseService) isDuplicatePair(a, b models.Expense) bool {
+	if a.Kind != b.Kind {
+		return false
+	}
+	if math.Round(a.Amount*100) != math.Round(b.Amount*100) {
+		return false
+	}
+	diff := a.CreatedAt.Sub(b.CreatedAt)
+	if diff < 0 {
+		diff = -diff
 	}
+	if diff > s.duplicateWindow {
+		return false
+	}
+	return similarComments(a.Description, b.Description)
 }
 
-func (s *ExpenseService) CreateExpense(ctx context.Context, expense *models.Expense) error {
-	return s.repo.CreateExpense(ctx, expense)
+func similarComments(a, b string) bool {
+	a = strings.ToLower(strings.TrimSpace(a))
+	b = strings.ToLower(strings.TrimSpace(b))
+	if a == "" || b == "" {
+		return a == b
+	}
+	if strings.Contains(a, b) || strings.Contains(b, a) {
+		return true
+	}
+	return textSimilarity(a, b) >= duplicateCommentSimilarity
 }
 
 func (s *ExpenseService) GetExpenseByID(ctx context.Context, expenseID uint) (*models.Expense, error) {
@@ -44,9 +191,117 @@ func (s *ExpenseService) ExistsByCategoryID(ctx context.Context, categoryID uint
 }
 
 func (s *ExpenseService) UpdateExpense(ctx context.Context, expense *models.Expense) error {
-	return s.repo.UpdateExpense(ctx, expense)
+	if err := checkNotReported(ctx, s.repo, expense.ID); err != nil {
+		return err
+	}
+	if err := normalizeEntry(ctx, s.repo, expense); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateExpense(ctx, expense); err != nil {
+		return err
+	}
+	s.afterChange(ctx, expense.UserID, expense.TravelID)
+	return nil
+}
+
+// normalizeEntry приводит запись к правилам знака: сумма хранится положительной,
+// а направление задаёт вид. Отрицательная сумма без вида считается возвратом.
+// Возврат, привязанный к расходу, наследует его категорию.
+func normalizeEntry(ctx context.Context, repo repository.ExpenseRepositoryInterface, expense *models.Expense) error {
+	if expense.Kind == "" {
+		expense.Kind = models.KindExpense
+		if expense.Amount < 0 {
+			expense.Kind = models.KindRefund
+			expense.Amount = -expense.Amount
+		}
+	}
+	if !expense.Kind.Valid() {
+		return ErrInvalidKind
+	}
+	if expense.Amount <= 0 {
+		return ErrInvalidAmount
+	}
+	if expense.Kind == models.KindMileage && expense.Distance <= 0 {
+		return ErrInvalidDistance
+	}
+
+	if expense.RefundOfID == nil {
+		return nil
+	}
+	if !expense.Kind.IsRefund() || *expense.RefundOfID == expense.ID {
+		return ErrInvalidRefundLink
+	}
+	original, err := repo.GetExpenseByID(ctx, *expense.RefundOfID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidRefundLink
+		}
+		return err
+	}
+	if original.UserID != expense.UserID || original.TravelID != expense.TravelID || original.Kind != models.KindExpense {
+		return ErrInvalidRefundLink
+	}
+	expense.CategoryID = original.CategoryID
+	return nil
 }
 
 func (s *ExpenseService) DeleteExpense(ctx context.Context, id uint) error {
-	return s.repo.DeleteExpense(ctx, id)
+	if err := checkNotReported(ctx, s.repo, id); err != nil {
+		return err
+	}
+	expense, err := s.repo.GetExpenseByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.DeleteExpense(ctx, id); err != nil {
+		return err
+	}
+	s.afterChange(ctx, expense.UserID, expense.TravelID)
+	return nil
+}
+
+// ReplacePerDiem заменяет начисленные суточные поездки новыми записями. Если прежние
+// суточные уже входят в отчёт, возвращается ErrExpenseReported.
+func (s *ExpenseService) ReplacePerDiem(ctx context.Context, userID uint, travelID uint, entries []models.Expense) error {
+	for i := range entries {
+		if err := normalizeEntry(ctx, s.repo, &entries[i]); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.ReplacePerDiem(ctx, userID, travelID, entries); err != nil {
+		return err
+	}
+	s.afterChange(ctx, userID, travelID)
+	return nil
+}
+
+// afterChange перепроверяет правила трат поездки и ставит в очередь обновление ленты
+// наблюдений после записи расхода. Сам расход к этому моменту уже сохранён, поэтому
+// ошибки только логируются.
+func (s *ExpenseService) afterChange(ctx context.Context, userID uint, travelID uint) {
+	if err := s.policies.EvaluateTravel(ctx, userID, travelID); err != nil {
+		log.Printf("Failed to evaluate policies for travel %d: %v\n", travelID, err)
+	}
+	s.insights.Schedule(userID, travelID)
+}
+
+// checkNotReported — расход меняется и удаляется, только пока он не входит в действующий
+// отчёт: утверждённый или оплаченный отчёт его блокирует (ErrExpenseLocked), а черновик
+// или отчёт на рассмотрении ссылаются на него (ErrExpenseReported). Отклонённые отчёты
+// расход не держат.
+func checkNotReported(ctx context.Context, repo repository.ExpenseRepositoryInterface, expenseID uint) error {
+	statuses, err := repo.GetReportStatuses(ctx, expenseID)
+	if err != nil {
+		return err
+	}
+	err = nil
+	for _, status := range statuses {
+		switch {
+		case status.Locks():
+			return ErrExpenseLocked
+		case status != models.ReportRejected:
+			err = ErrExpenseReported
+		}
+	}
+	return err
 }
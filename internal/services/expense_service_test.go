@@ -17,31 +17,167 @@ func TestExpenseService_CreateExpense(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	service := NewExpenseService(mockRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
 
 	expense := &models.Expense{ID: 1, Amount: 100}
 	ctx := context.Background()
 	t.Run("success", func(t *testing.T) {
 		mockRepo.EXPECT().CreateExpense(ctx, expense).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, expense.UserID, expense.TravelID).Return(nil)
+		mockInsights.EXPECT().Schedule(expense.UserID, expense.TravelID)
 
-		err := service.CreateExpense(ctx, expense)
+		_, err := service.CreateExpense(ctx, expense, true)
 		assert.NoError(t, err)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
 		mockRepo.EXPECT().CreateExpense(ctx, expense).Return(errors.New("db error"))
 
-		err := service.CreateExpense(ctx, expense)
+		_, err := service.CreateExpense(ctx, expense, true)
 		assert.EqualError(t, err, "db error")
 	})
 }
 
+func TestExpenseService_CreateExpense_Duplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
+	ctx := context.Background()
+
+	date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	expense := &models.Expense{UserID: 1, TravelID: 2, Amount: 450, CreatedAt: date, Description: "Такси до отеля"}
+	from := date.Add(-24 * time.Hour)
+	to := date.Add(24 * time.Hour)
+
+	t.Run("duplicate found", func(t *testing.T) {
+		mockRepo.EXPECT().
+			FindSameAmountExpenses(ctx, uint(1), uint(2), 450.0, from, to).
+			Return([]models.Expense{
+				{ID: 5, Amount: 450, CreatedAt: date, Description: "такси до отеля", Kind: models.KindExpense},
+				{ID: 6, Amount: 450, CreatedAt: date, Description: "музей", Kind: models.KindExpense},
+				{ID: 7, Amount: 450, CreatedAt: date, Description: "такси до отеля", Kind: models.KindRefund},
+			}, nil)
+
+		ids, err := service.CreateExpense(ctx, expense, false)
+		assert.ErrorIs(t, err, ErrPossibleDuplicate)
+		assert.Equal(t, []uint{5}, ids)
+	})
+
+	t.Run("no duplicates", func(t *testing.T) {
+		mockRepo.EXPECT().
+			FindSameAmountExpenses(ctx, uint(1), uint(2), 450.0, from, to).
+			Return([]models.Expense{{ID: 6, Amount: 450, CreatedAt: date, Description: "музей", Kind: models.KindExpense}}, nil)
+		mockRepo.EXPECT().CreateExpense(ctx, expense).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, expense.UserID, expense.TravelID).Return(nil)
+		mockInsights.EXPECT().Schedule(expense.UserID, expense.TravelID)
+
+		ids, err := service.CreateExpense(ctx, expense, false)
+		assert.NoError(t, err)
+		assert.Empty(t, ids)
+	})
+}
+
+func TestExpenseService_ScanDuplicates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
+	ctx := context.Background()
+
+	date := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	mockRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(2)).Return([]models.Expense{
+		{ID: 1, Amount: 100, CreatedAt: date, Description: "coffee"},
+		{ID: 2, Amount: 100, CreatedAt: date.Add(2 * time.Hour), Description: "Coffee"},
+		{ID: 3, Amount: 100, CreatedAt: date.AddDate(0, 0, 5), Description: "coffee"},
+		{ID: 4, Amount: 250, CreatedAt: date, Description: "lunch"},
+	}, nil)
+
+	groups, err := service.ScanDuplicates(ctx, 1, 2)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+	assert.Len(t, groups[0], 2)
+	assert.Equal(t, uint(1), groups[0][0].ID)
+	assert.Equal(t, uint(2), groups[0][1].ID)
+}
+
+func TestExpenseService_CreateExpense_Kinds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
+	ctx := context.Background()
+
+	t.Run("negative amount becomes refund", func(t *testing.T) {
+		expense := &models.Expense{UserID: 1, TravelID: 2, Amount: -150}
+		mockRepo.EXPECT().CreateExpense(ctx, expense).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, expense.UserID, expense.TravelID).Return(nil)
+		mockInsights.EXPECT().Schedule(expense.UserID, expense.TravelID)
+
+		_, err := service.CreateExpense(ctx, expense, true)
+		assert.NoError(t, err)
+		assert.Equal(t, models.KindRefund, expense.Kind)
+		assert.Equal(t, 150.0, expense.Amount)
+	})
+
+	t.Run("negative amount with explicit kind", func(t *testing.T) {
+		expense := &models.Expense{UserID: 1, TravelID: 2, Amount: -150, Kind: models.KindIncome}
+
+		_, err := service.CreateExpense(ctx, expense, true)
+		assert.ErrorIs(t, err, ErrInvalidAmount)
+	})
+
+	t.Run("unknown kind", func(t *testing.T) {
+		expense := &models.Expense{UserID: 1, TravelID: 2, Amount: 150, Kind: "gift"}
+
+		_, err := service.CreateExpense(ctx, expense, true)
+		assert.ErrorIs(t, err, ErrInvalidKind)
+	})
+
+	t.Run("refund inherits category of original", func(t *testing.T) {
+		originalID := uint(9)
+		expense := &models.Expense{UserID: 1, TravelID: 2, Amount: 300, Kind: models.KindRefund, RefundOfID: &originalID}
+		mockRepo.EXPECT().GetExpenseByID(ctx, originalID).
+			Return(&models.Expense{ID: 9, UserID: 1, TravelID: 2, CategoryID: 4, Kind: models.KindExpense}, nil)
+		mockRepo.EXPECT().CreateExpense(ctx, expense).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, expense.UserID, expense.TravelID).Return(nil)
+		mockInsights.EXPECT().Schedule(expense.UserID, expense.TravelID)
+
+		_, err := service.CreateExpense(ctx, expense, true)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(4), expense.CategoryID)
+	})
+
+	t.Run("refund of another travel", func(t *testing.T) {
+		originalID := uint(9)
+		expense := &models.Expense{UserID: 1, TravelID: 2, Amount: 300, Kind: models.KindRefund, RefundOfID: &originalID}
+		mockRepo.EXPECT().GetExpenseByID(ctx, originalID).
+			Return(&models.Expense{ID: 9, UserID: 1, TravelID: 3, Kind: models.KindExpense}, nil)
+
+		_, err := service.CreateExpense(ctx, expense, true)
+		assert.ErrorIs(t, err, ErrInvalidRefundLink)
+	})
+}
+
 func TestExpenseService_GetExpensesByUserID(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	service := NewExpenseService(mockRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
 
 	expected := []models.Expense{{ID: 1, Amount: 200}}
 	ctx := context.Background()
@@ -68,7 +204,9 @@ func TestExpenseService_GetExpensesByUserTimeAndCategory(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	service := NewExpenseService(mockRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
 
 	userID := uint(1)
 	from := time.Now().Add(-24 * time.Hour)
@@ -104,24 +242,44 @@ func TestExpenseService_UpdateExpense(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	service := NewExpenseService(mockRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
 
 	expense := &models.Expense{ID: 1, Amount: 300}
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return(nil, nil)
 		mockRepo.EXPECT().UpdateExpense(ctx, expense).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, expense.UserID, expense.TravelID).Return(nil)
+		mockInsights.EXPECT().Schedule(expense.UserID, expense.TravelID)
 
 		err := service.UpdateExpense(ctx, expense)
 		assert.NoError(t, err)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return(nil, nil)
 		mockRepo.EXPECT().UpdateExpense(ctx, expense).Return(errors.New("db error"))
 
 		err := service.UpdateExpense(ctx, expense)
 		assert.EqualError(t, err, "db error")
 	})
+
+	t.Run("locked by approved report", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return([]models.ReportStatus{models.ReportRejected, models.ReportApproved}, nil)
+
+		err := service.UpdateExpense(ctx, expense)
+		assert.ErrorIs(t, err, ErrExpenseLocked)
+	})
+
+	t.Run("in draft report", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return([]models.ReportStatus{models.ReportDraft}, nil)
+
+		err := service.UpdateExpense(ctx, expense)
+		assert.ErrorIs(t, err, ErrExpenseReported)
+	})
 }
 
 func TestExpenseService_DeleteExpense(t *testing.T) {
@@ -129,20 +287,82 @@ func TestExpenseService_DeleteExpense(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
-	service := NewExpenseService(mockRepo)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
 	ctx := context.Background()
 
 	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return(nil, nil)
+		mockRepo.EXPECT().GetExpenseByID(ctx, uint(1)).Return(&models.Expense{ID: 1, UserID: 1, TravelID: 2}, nil)
 		mockRepo.EXPECT().DeleteExpense(ctx, uint(1)).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, uint(1), uint(2)).Return(nil)
+		mockInsights.EXPECT().Schedule(uint(1), uint(2))
 
 		err := service.DeleteExpense(ctx, 1)
 		assert.NoError(t, err)
 	})
 
 	t.Run("repo error", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return(nil, nil)
+		mockRepo.EXPECT().GetExpenseByID(ctx, uint(1)).Return(&models.Expense{ID: 1, UserID: 1, TravelID: 2}, nil)
 		mockRepo.EXPECT().DeleteExpense(ctx, uint(1)).Return(errors.New("db error"))
 
 		err := service.DeleteExpense(ctx, 1)
 		assert.EqualError(t, err, "db error")
 	})
+
+	t.Run("locked by approved report", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return([]models.ReportStatus{models.ReportRejected, models.ReportApproved}, nil)
+
+		err := service.DeleteExpense(ctx, 1)
+		assert.ErrorIs(t, err, ErrExpenseLocked)
+	})
+
+	t.Run("in submitted report", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return([]models.ReportStatus{models.ReportSubmitted}, nil)
+
+		err := service.DeleteExpense(ctx, 1)
+		assert.ErrorIs(t, err, ErrExpenseReported)
+	})
+
+	t.Run("only in rejected report", func(t *testing.T) {
+		mockRepo.EXPECT().GetReportStatuses(ctx, uint(1)).Return([]models.ReportStatus{models.ReportRejected}, nil)
+		mockRepo.EXPECT().GetExpenseByID(ctx, uint(1)).Return(&models.Expense{ID: 1, UserID: 1, TravelID: 2}, nil)
+		mockRepo.EXPECT().DeleteExpense(ctx, uint(1)).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, uint(1), uint(2)).Return(nil)
+		mockInsights.EXPECT().Schedule(uint(1), uint(2))
+
+		err := service.DeleteExpense(ctx, 1)
+		assert.NoError(t, err)
+	})
+}
+
+func TestExpenseService_ReplacePerDiem(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewExpenseService(mockRepo, mockPolicies, mockInsights, 24*time.Hour)
+	ctx := context.Background()
+
+	entries := []models.Expense{{UserID: 1, TravelID: 2, Amount: 2500, Kind: models.KindPerDiem}}
+
+	t.Run("success", func(t *testing.T) {
+		mockRepo.EXPECT().ReplacePerDiem(ctx, uint(1), uint(2), entries).Return(nil)
+		mockPolicies.EXPECT().EvaluateTravel(ctx, uint(1), uint(2)).Return(nil)
+		mockInsights.EXPECT().Schedule(uint(1), uint(2))
+
+		err := service.ReplacePerDiem(ctx, 1, 2, entries)
+		assert.NoError(t, err)
+	})
+
+	t.Run("entries in a report", func(t *testing.T) {
+		mockRepo.EXPECT().ReplacePerDiem(ctx, uint(1), uint(2), entries).Return(ErrExpenseReported)
+
+		err := service.ReplacePerDiem(ctx, 1, 2, entries)
+		assert.ErrorIs(t, err, ErrExpenseReported)
+	})
 }
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+// Состояние поездки на момент прогноза
+const (
+	ForecastNotStarted = "not_started"
+	ForecastActive     = "active"
+	ForecastFinished   = "finished"
+)
+
+// forecastZ — квантиль нормального распределения для 90% интервала прогноза
+const forecastZ = 1.645
+
+type ForecastService struct {
+	expenseRepo repository.ExpenseRepositoryInterface
+	travelRepo  repository.TravelRepositoryInterface
+}
+
+func NewForecastService(expenseRepo repository.ExpenseRepositoryInterface, travelRepo repository.TravelRepositoryInterface) *ForecastService {
+	return &ForecastService{
+		expenseRepo: expenseRepo,
+		travelRepo:  travelRepo,
+	}
+}
+
+// Forecast прогнозирует итоговые чистые траты поездки на дату now.
+// Дневные ставки считаются по категориям за прошедшие дни поездки; записи с датой
+// вне прошедших дней (например, оплаченное заранее жильё) входят в потраченное,
+// но не в ставку. Разброс интервала оценивается по колебаниям трат между днями.
+func (s *ForecastService) Forecast(ctx context.Context, travel *models.Travel, now time.Time, loc i18n.Locale) (*dto.ForecastResponse, error) {
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, travel.UserID, travel.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	start := dateOf(travel.StartDate)
+	end := dateOf(travel.EndDate)
+	today := dateOf(now)
+	days := int(end.Sub(start).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+	elapsed := int(today.Sub(start).Hours()/24) + 1
+	status := ForecastActive
+	switch {
+	case elapsed <= 0:
+		elapsed, status = 0, ForecastNotStarted
+	case elapsed >= days:
+		elapsed, status = days, ForecastFinished
+	}
+	remaining := days - elapsed
+
+	type categoryTotals struct {
+		id     uint
+		name   string
+		spent  float64
+		inDays float64 // траты за прошедшие дни поездки
+	}
+	byCategory := make(map[uint]*categoryTotals)
+	daily := make([]float64, elapsed)
+	var spent float64
+	for _, e := range expenses {
+		amount := netAmount(e)
+		if amount == 0 {
+			continue
+		}
+		spent += amount
+		c, ok := byCategory[e.CategoryID]
+		if !ok {
+			c = &categoryTotals{id: e.CategoryID, name: i18n.CategoryName(loc, e.Category.Key, e.Category.Name)}
+			byCategory[e.CategoryID] = c
+		}
+		c.spent += amount
+		if day := int(dateOf(e.CreatedAt).Sub(start).Hours() / 24); day >= 0 && day < elapsed {
+			c.inDays += amount
+			daily[day] += amount
+		}
+	}
+
+	resp := &dto.ForecastResponse{
+		TravelID:      fmt.Sprintf("%v", travel.ID),
+		Status:        status,
+		Days:          days,
+		ElapsedDays:   elapsed,
+		RemainingDays: remaining,
+		Spent:         roundMoney(spent),
+		Confidence:    0.9,
+		Categories:    make([]dto.ForecastCategory, 0, len(byCategory)),
+	}
+
+	projected := spent
+	for _, c := range byCategory {
+		rate := 0.0
+		if elapsed > 0 {
+			rate = c.inDays / float64(elapsed)
+		}
+		categoryProjected := c.spent + rate*float64(remaining)
+		projected += rate * float64(remaining)
+		resp.Categories = append(resp.Categories, dto.ForecastCategory{
+			CategoryID: fmt.Sprintf("%v", c.id),
+			Category:   c.name,
+			Spent:      roundMoney(c.spent),
+			DailyRate:  roundMoney(rate),
+			Projected:  roundMoney(categoryProjected),
+		})
+	}
+	sort.Slice(resp.Categories, func(i, j int) bool {
+		if resp.Categories[i].Projected != resp.Categories[j].Projected {
+			return resp.Categories[i].Projected > resp.Categories[j].Projected
+		}
+		return resp.Categories[i].Category < resp.Categories[j].Category
+	})
+
+	mean, sd := meanAndDeviation(daily)
+	spread := forecastZ * sd * math.Sqrt(float64(remaining))
+	resp.BurnRate = roundMoney(mean)
+	resp.ProjectedTotal = roundMoney(projected)
+	resp.ProjectedLow = roundMoney(math.Max(spent, projected-spread))
+	resp.ProjectedHigh = roundMoney(projected + spread)
+
+	if budget := travel.Budget; budget != nil {
+		b := roundMoney(*budget)
+		left := roundMoney(*budget - spent)
+		overrun := roundMoney(projected - *budget)
+		resp.Budget = &b
+		resp.BudgetLeft = &left
+		resp.ProjectedOverBudget = &overrun
+		resp.WillExceedBudget = projected > *budget
+		if remaining > 0 {
+			safe := roundMoney(math.Max(0, *budget-spent) / float64(remaining))
+			resp.SafeToSpendPerDay = &safe
+		}
+		return resp, nil
+	}
+
+	estimate, err := s.historyEstimate(ctx, travel, days)
+	if err != nil {
+		return nil, err
+	}
+	if estimate != nil {
+		e := roundMoney(*estimate)
+		resp.HistoryEstimate = &e
+	}
+	return resp, nil
+}
+
+// historyEstimate оценивает траты поездки такой длительности по средним тратам в день
+// в других поездках пользователя. Это не бюджет: с ним ничего не сравнивается.
+func (s *ForecastService) historyEstimate(ctx context.Context, travel *models.Travel, days int) (*float64, error) {
+	travels, err := s.travelRepo.GetTravelsByUserID(ctx, travel.UserID)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := s.expenseRepo.SumByTravelAndCategory(ctx, travel.UserID)
+	if err != nil {
+		return nil, err
+	}
+	byTravel := groupByTravel(sums)
+
+	var history []dto.TripMetrics
+	for _, t := range travels {
+		if t.ID != travel.ID && len(byTravel[t.ID]) > 0 {
+			history = append(history, tripMetrics(t, byTravel[t.ID]))
+		}
+	}
+	if len(history) == 0 {
+		return nil, nil
+	}
+	estimate := averageTrip(history).PerDay * float64(days)
+	return &estimate, nil
+}
+
+// netAmount — вклад записи в чистые траты: расход увеличивает, возврат уменьшает,
+// доходы и начисления по нормативу не учитываются
+func netAmount(e models.Expense) float64 {
+	switch {
+	case e.Kind == models.KindExpense:
+		return e.Amount
+	case e.Kind.IsRefund():
+		return -e.Amount
+	}
+	return 0
+}
+
+// meanAndDeviation — среднее и выборочное стандартное отклонение дневных трат.
+// По одному дню разброс оценить нельзя, поэтому отклонение принимается равным среднему.
+func meanAndDeviation(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+	if len(values) == 1 {
+		return mean, math.Abs(mean)
+	}
+	var sq float64
+	for _, v := range values {
+		sq += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(sq / float64(len(values)-1))
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForecastService_Forecast(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockTravelRepo := mocks.NewMockTravelRepositoryInterface(ctrl)
+	service := NewForecastService(mockRepo, mockTravelRepo)
+	ctx := context.Background()
+
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	food := models.Category{ID: 2, Name: "Питание", Key: "food"}
+	lodging := models.Category{ID: 3, Name: "Жильё", Key: "lodging"}
+	expenses := []models.Expense{
+		{ID: 1, CategoryID: 3, Category: lodging, Amount: 500, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, -10)},
+		{ID: 2, CategoryID: 2, Category: food, Amount: 100, Kind: models.KindExpense, CreatedAt: start},
+		{ID: 3, CategoryID: 2, Category: food, Amount: 60, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, 1)},
+		{ID: 4, CategoryID: 2, Category: food, Amount: 20, Kind: models.KindRefund, CreatedAt: start.AddDate(0, 0, 1)},
+		{ID: 5, CategoryID: 2, Category: food, Amount: 1000, Kind: models.KindIncome, CreatedAt: start},
+	}
+	now := start.AddDate(0, 0, 1).Add(15 * time.Hour)
+
+	t.Run("with budget", func(t *testing.T) {
+		budget := 1000.0
+		travel := &models.Travel{ID: 1, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 9), Budget: &budget}
+		mockRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(1)).Return(expenses, nil)
+
+		resp, err := service.Forecast(ctx, travel, now, i18n.EN)
+		assert.NoError(t, err)
+		assert.Equal(t, ForecastActive, resp.Status)
+		assert.Equal(t, 10, resp.Days)
+		assert.Equal(t, 2, resp.ElapsedDays)
+		assert.Equal(t, 8, resp.RemainingDays)
+		assert.Equal(t, 640.0, resp.Spent) // 500 + 100 + 60 - 20, доход не учитывается
+		assert.Equal(t, 70.0, resp.BurnRate)
+		assert.Equal(t, 1200.0, resp.ProjectedTotal) // жильё оплачено заранее и в ставку не входит
+		assert.Less(t, resp.ProjectedLow, resp.ProjectedTotal)
+		assert.GreaterOrEqual(t, resp.ProjectedLow, resp.Spent)
+		assert.Greater(t, resp.ProjectedHigh, resp.ProjectedTotal)
+
+		assert.Nil(t, resp.HistoryEstimate)
+		assert.Equal(t, 360.0, *resp.BudgetLeft)
+		assert.Equal(t, 45.0, *resp.SafeToSpendPerDay)
+		assert.Equal(t, 200.0, *resp.ProjectedOverBudget)
+		assert.True(t, resp.WillExceedBudget)
+
+		assert.Len(t, resp.Categories, 2)
+		assert.Equal(t, "Food", resp.Categories[0].Category)
+		assert.Equal(t, 70.0, resp.Categories[0].DailyRate)
+		assert.Equal(t, 700.0, resp.Categories[0].Projected)
+		assert.Equal(t, 500.0, resp.Categories[1].Projected)
+	})
+
+	t.Run("estimate from history", func(t *testing.T) {
+		travel := &models.Travel{ID: 1, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 9)}
+		past := models.Travel{ID: 2, UserID: 1, StartDate: start.AddDate(-1, 0, 0), EndDate: start.AddDate(-1, 0, 4)}
+		mockRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(1)).Return(expenses, nil)
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return([]models.Travel{*travel, past}, nil)
+		mockRepo.EXPECT().SumByTravelAndCategory(ctx, uint(1)).Return([]repository.TravelCategorySummary{
+			{TravelID: 1, CategorySummary: repository.CategorySummary{CategoryID: 2, AmountSummary: repository.AmountSummary{Gross: 640}}},
+			{TravelID: 2, CategorySummary: repository.CategorySummary{CategoryID: 2, AmountSummary: repository.AmountSummary{Gross: 750}}},
+		}, nil)
+
+		resp, err := service.Forecast(ctx, travel, now, i18n.RU)
+		assert.NoError(t, err)
+		assert.Equal(t, 1500.0, *resp.HistoryEstimate) // 150 в день × 10 дней
+		// Оценка не выдаётся за бюджет
+		assert.Nil(t, resp.Budget)
+		assert.Nil(t, resp.ProjectedOverBudget)
+		assert.False(t, resp.WillExceedBudget)
+		assert.Nil(t, resp.SafeToSpendPerDay)
+	})
+
+	t.Run("no budget and finished", func(t *testing.T) {
+		travel := &models.Travel{ID: 1, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 1)}
+		mockRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(1)).Return(expenses, nil)
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return([]models.Travel{*travel}, nil)
+		mockRepo.EXPECT().SumByTravelAndCategory(ctx, uint(1)).Return(nil, nil)
+
+		resp, err := service.Forecast(ctx, travel, start.AddDate(0, 1, 0), i18n.RU)
+		assert.NoError(t, err)
+		assert.Equal(t, ForecastFinished, resp.Status)
+		assert.Equal(t, 0, resp.RemainingDays)
+		assert.Equal(t, resp.Spent, resp.ProjectedTotal)
+		assert.Equal(t, resp.Spent, resp.ProjectedHigh)
+		assert.Nil(t, resp.HistoryEstimate)
+		assert.Nil(t, resp.Budget)
+		assert.Nil(t, resp.SafeToSpendPerDay)
+	})
+}
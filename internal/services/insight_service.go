@@ -0,0 +1,408 @@
+package services
+
+import (
+	"context"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+// Пороги обнаружения необычных трат
+const (
+	insightMinSamples    = 5    // сколько записей или дней нужно в истории, чтобы судить об «обычном»
+	insightRatio         = 3.0  // во сколько раз выше обычного, чтобы попасть в ленту
+	insightCriticalRatio = 5.0  // начиная с этого отношения наблюдение критическое
+	insightShareShift    = 0.15 // на сколько должна сместиться доля категории относительно прошлых поездок
+)
+
+// Время на один фоновый пересчёт наблюдений
+const insightTimeout = time.Minute
+
+type InsightService struct {
+	repo        repository.InsightRepositoryInterface
+	expenseRepo repository.ExpenseRepositoryInterface
+	travelRepo  repository.TravelRepositoryInterface
+
+	mu      sync.Mutex
+	pending map[insightKey]bool // идущие пересчёты; true — после текущего нужен ещё один
+	wg      sync.WaitGroup
+}
+
+// insightKey — что пересчитывать в фоне; travelID == 0 — все поездки пользователя
+type insightKey struct {
+	userID   uint
+	travelID uint
+}
+
+func NewInsightService(repo repository.InsightRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface, travelRepo repository.TravelRepositoryInterface) *InsightService {
+	return &InsightService{
+		repo:        repo,
+		expenseRepo: expenseRepo,
+		travelRepo:  travelRepo,
+		pending:     make(map[insightKey]bool),
+	}
+}
+
+// GetInsights возвращает ленту наблюдений пользователя, по всем поездкам или по одной
+func (s *InsightService) GetInsights(ctx context.Context, userID uint, travelID *uint) ([]models.Insight, error) {
+	return s.repo.GetInsights(ctx, userID, travelID)
+}
+
+// Schedule пересчитывает наблюдения поездок travelIDs в фоне; без travelIDs — всех поездок
+// пользователя. Запись расходов пересчёта не ждёт. Пока поездка пересчитывается, новые
+// запросы по ней схлопываются в один повторный прогон после текущего.
+func (s *InsightService) Schedule(userID uint, travelIDs ...uint) {
+	if len(travelIDs) == 0 {
+		travelIDs = []uint{0}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, travelID := range travelIDs {
+		key := insightKey{userID: userID, travelID: travelID}
+		if _, running := s.pending[key]; running {
+			s.pending[key] = true
+			continue
+		}
+		s.pending[key] = false
+		s.wg.Add(1)
+		go s.run(key)
+	}
+}
+
+// run пересчитывает наблюдения, пока за время прогона приходят новые запросы.
+// Контекст запроса к этому моменту уже завершён, поэтому у прогона свой контекст.
+func (s *InsightService) run(key insightKey) {
+	defer s.wg.Done()
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), insightTimeout)
+		var err error
+		if key.travelID == 0 {
+			err = s.RefreshUser(ctx, key.userID)
+		} else {
+			err = s.RefreshTravel(ctx, key.userID, key.travelID)
+		}
+		cancel()
+		if err != nil {
+			log.Printf("Failed to refresh insights for user %d, travel %d: %v\n", key.userID, key.travelID, err)
+		}
+
+		s.mu.Lock()
+		again := s.pending[key]
+		if again {
+			s.pending[key] = false
+		} else {
+			delete(s.pending, key)
+		}
+		s.mu.Unlock()
+		if !again {
+			return
+		}
+	}
+}
+
+// Wait дожидается фоновых пересчётов; вызывается при остановке сервера
+func (s *InsightService) Wait() {
+	s.wg.Wait()
+}
+
+// RefreshTravel заново анализирует траты поездки на фоне всей истории пользователя
+// и заменяет сохранённые наблюдения. Наблюдения других поездок не пересчитываются.
+func (s *InsightService) RefreshTravel(ctx context.Context, userID uint, travelID uint) error {
+	travels, err := s.travelRepo.GetTravelsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, travel := range travels {
+		if travel.ID != travelID {
+			continue
+		}
+		history, err := s.loadHistory(ctx, userID, travels)
+		if err != nil {
+			return err
+		}
+		return s.repo.ReplaceInsights(ctx, userID, travelID, history.analyze(travel))
+	}
+	return s.repo.ReplaceInsights(ctx, userID, travelID, nil)
+}
+
+// RefreshUser пересчитывает наблюдения всех поездок пользователя, загружая историю один раз.
+// Нужен после изменений, затрагивающих сразу несколько поездок, например слияния категорий.
+func (s *InsightService) RefreshUser(ctx context.Context, userID uint) error {
+	travels, err := s.travelRepo.GetTravelsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	history, err := s.loadHistory(ctx, userID, travels)
+	if err != nil {
+		return err
+	}
+	for _, travel := range travels {
+		if err := s.repo.ReplaceInsights(ctx, userID, travel.ID, history.analyze(travel)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insightHistory — всё, на фоне чего анализируются траты поездки
+type insightHistory struct {
+	travels  []models.Travel
+	expenses []models.Expense
+	sums     []repository.TravelCategorySummary
+}
+
+func (s *InsightService) loadHistory(ctx context.Context, userID uint, travels []models.Travel) (*insightHistory, error) {
+	expenses, err := s.expenseRepo.GetExpensesByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	sums, err := s.expenseRepo.SumByTravelAndCategory(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &insightHistory{travels: travels, expenses: expenses, sums: sums}, nil
+}
+
+func (h *insightHistory) analyze(travel models.Travel) []models.Insight {
+	insights := detectOutliers(travel.ID, h.expenses)
+	insights = append(insights, detectUnusualDays(travel.ID, h.expenses, insights)...)
+	insights = append(insights, detectCategoryShifts(travel, h.travels, h.sums)...)
+	for i := range insights {
+		insights[i].UserID = travel.UserID
+		insights[i].TravelID = travel.ID
+	}
+	return insights
+}
+
+// detectOutliers отмечает расходы поездки, которые в insightRatio раз и более дороже
+// медианы остальных расходов пользователя в той же категории
+func detectOutliers(travelID uint, expenses []models.Expense) []models.Insight {
+	byCategory := make(map[uint][]float64)
+	for _, e := range expenses {
+		if e.Kind == models.KindExpense {
+			byCategory[e.CategoryID] = append(byCategory[e.CategoryID], e.Amount)
+		}
+	}
+	for _, amounts := range byCategory {
+		sort.Float64s(amounts)
+	}
+
+	insights := make([]models.Insight, 0)
+	for _, e := range expenses {
+		if e.TravelID != travelID || e.Kind != models.KindExpense {
+			continue
+		}
+		amounts := byCategory[e.CategoryID]
+		if len(amounts)-1 < insightMinSamples {
+			continue
+		}
+		if insight, ok := newRatioInsight(models.InsightOutlierExpense, e.Amount, medianWithout(amounts, e.Amount)); ok {
+			id, categoryID := e.ID, e.CategoryID
+			insight.ExpenseID = &id
+			insight.CategoryID = &categoryID
+			insights = append(insights, insight)
+		}
+	}
+	return insights
+}
+
+// detectUnusualDays сравнивает траты каждого дня поездки — всего и по категориям —
+// с медианой остальных дней пользователя, в которые были траты. День категории,
+// целиком состоящий из уже отмеченного расхода, повторно не отмечается.
+func detectUnusualDays(travelID uint, expenses []models.Expense, outliers []models.Insight) []models.Insight {
+	type dayKey struct {
+		day      string
+		category uint // 0 — все категории
+	}
+	totals := make(map[dayKey]float64)
+	entries := make(map[dayKey][]uint)
+	inTravel := make(map[dayKey]bool)
+	for _, e := range expenses {
+		if e.Kind != models.KindExpense {
+			continue
+		}
+		day := e.CreatedAt.Format("2006-01-02")
+		for _, k := range []dayKey{{day, 0}, {day, e.CategoryID}} {
+			totals[k] += e.Amount
+			entries[k] = append(entries[k], e.ID)
+			if e.TravelID == travelID {
+				inTravel[k] = true
+			}
+		}
+	}
+
+	// суммы дней по каждой категории (0 — по всем), отсортированные для медианы
+	byCategory := make(map[uint][]float64)
+	for k, total := range totals {
+		byCategory[k.category] = append(byCategory[k.category], total)
+	}
+	for _, days := range byCategory {
+		sort.Float64s(days)
+	}
+
+	flagged := make(map[uint]bool)
+	for _, o := range outliers {
+		flagged[*o.ExpenseID] = true
+	}
+
+	keys := make([]dayKey, 0, len(inTravel))
+	for k := range inTravel {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].day != keys[j].day {
+			return keys[i].day < keys[j].day
+		}
+		return keys[i].category < keys[j].category
+	})
+
+	insights := make([]models.Insight, 0)
+	for _, k := range keys {
+		if k.category != 0 && len(entries[k]) == 1 && flagged[entries[k][0]] {
+			continue
+		}
+		days := byCategory[k.category]
+		if len(days)-1 < insightMinSamples {
+			continue
+		}
+		insight, ok := newRatioInsight(models.InsightUnusualDay, totals[k], medianWithout(days, totals[k]))
+		if !ok {
+			continue
+		}
+		day, _ := time.Parse("2006-01-02", k.day)
+		insight.Day = &day
+		if k.category != 0 {
+			categoryID := k.category
+			insight.CategoryID = &categoryID
+		}
+		insights = append(insights, insight)
+	}
+	return insights
+}
+
+// detectCategoryShifts сравнивает доли категорий в чистых тратах поездки со средними
+// долями в поездках, начавшихся раньше. Категория, которой в поездке нет, имеет долю 0.
+func detectCategoryShifts(travel models.Travel, travels []models.Travel, sums []repository.TravelCategorySummary) []models.Insight {
+	shares := make(map[uint]map[uint]float64)
+	for travelID, categories := range groupByTravel(sums) {
+		var net float64
+		for _, c := range categories {
+			net += c.Net()
+		}
+		if net <= 0 {
+			continue
+		}
+		shares[travelID] = make(map[uint]float64, len(categories))
+		for _, c := range categories {
+			shares[travelID][c.CategoryID] = c.Net() / net
+		}
+	}
+
+	current, ok := shares[travel.ID]
+	if !ok {
+		return nil
+	}
+	usual := make(map[uint]float64)
+	previous := 0
+	for _, t := range travels {
+		if t.ID == travel.ID || !t.StartDate.Before(travel.StartDate) || shares[t.ID] == nil {
+			continue
+		}
+		previous++
+		for categoryID, share := range shares[t.ID] {
+			usual[categoryID] += share
+		}
+	}
+	if previous == 0 {
+		return nil
+	}
+
+	categoryIDs := make([]uint, 0, len(usual)+len(current))
+	for categoryID := range usual {
+		usual[categoryID] /= float64(previous)
+		categoryIDs = append(categoryIDs, categoryID)
+	}
+	for categoryID := range current {
+		if _, ok := usual[categoryID]; !ok {
+			categoryIDs = append(categoryIDs, categoryID)
+		}
+	}
+	sort.Slice(categoryIDs, func(i, j int) bool { return categoryIDs[i] < categoryIDs[j] })
+
+	insights := make([]models.Insight, 0)
+	for _, categoryID := range categoryIDs {
+		value, baseline := current[categoryID], usual[categoryID]
+		shift := math.Abs(value - baseline)
+		if shift < insightShareShift {
+			continue
+		}
+		severity := models.SeverityInfo
+		if shift >= 2*insightShareShift {
+			severity = models.SeverityWarning
+		}
+		id := categoryID
+		insight := models.Insight{
+			Type:       models.InsightCategoryShift,
+			Severity:   severity,
+			CategoryID: &id,
+			Value:      roundShare(value),
+			Baseline:   roundShare(baseline),
+		}
+		if baseline > 0 {
+			insight.Ratio = roundShare(value / baseline)
+		}
+		insights = append(insights, insight)
+	}
+	return insights
+}
+
+func newRatioInsight(kind models.InsightType, value, baseline float64) (models.Insight, bool) {
+	if baseline <= 0 || value < insightRatio*baseline {
+		return models.Insight{}, false
+	}
+	ratio := value / baseline
+	severity := models.SeverityWarning
+	if ratio >= insightCriticalRatio {
+		severity = models.SeverityCritical
+	}
+	return models.Insight{
+		Type:     kind,
+		Severity: severity,
+		Value:    roundMoney(value),
+		Baseline: roundMoney(baseline),
+		Ratio:    math.Round(ratio*10) / 10,
+	}, true
+}
+
+func groupByTravel(sums []repository.TravelCategorySummary) map[uint][]repository.CategorySummary {
+	byTravel := make(map[uint][]repository.CategorySummary)
+	for _, sum := range sums {
+		byTravel[sum.TravelID] = append(byTravel[sum.TravelID], sum.CategorySummary)
+	}
+	return byTravel
+}
+
+// medianWithout возвращает медиану отсортированных sorted без одного вхождения v,
+// не копируя срез: индексы начиная с позиции v сдвигаются на единицу
+func medianWithout(sorted []float64, v float64) float64 {
+	n := len(sorted) - 1
+	if n <= 0 {
+		return 0
+	}
+	skip := sort.SearchFloat64s(sorted, v)
+	at := func(i int) float64 {
+		if i >= skip {
+			return sorted[i+1]
+		}
+		return sorted[i]
+	}
+	mid := n / 2
+	if n%2 == 0 {
+		return (at(mid-1) + at(mid)) / 2
+	}
+	return at(mid)
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsightService_RefreshTravel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockInsightRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockTravelRepo := mocks.NewMockTravelRepositoryInterface(ctrl)
+	service := NewInsightService(mockRepo, mockExpenseRepo, mockTravelRepo)
+	ctx := context.Background()
+
+	past := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	travels := []models.Travel{
+		{ID: 1, UserID: 1, StartDate: past, EndDate: past.AddDate(0, 0, 5)},
+		{ID: 2, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 5)},
+	}
+	food, transport := uint(2), uint(3)
+	var expenses []models.Expense
+	// прошлая поездка: каждый день еда 100 и транспорт 20
+	for i := 0; i < 6; i++ {
+		day := past.AddDate(0, 0, i)
+		expenses = append(expenses,
+			models.Expense{ID: uint(10 + i), UserID: 1, TravelID: 1, CategoryID: food, Amount: 100, Kind: models.KindExpense, CreatedAt: day},
+			models.Expense{ID: uint(20 + i), UserID: 1, TravelID: 1, CategoryID: transport, Amount: 20, Kind: models.KindExpense, CreatedAt: day},
+		)
+	}
+	expenses = append(expenses,
+		models.Expense{ID: 30, UserID: 1, TravelID: 2, CategoryID: food, Amount: 100, Kind: models.KindExpense, CreatedAt: start},
+		// такси 90 при обычных 20 и день на 190 вместо обычных 120
+		models.Expense{ID: 31, UserID: 1, TravelID: 2, CategoryID: transport, Amount: 90, Kind: models.KindExpense, CreatedAt: start},
+		// возврат не анализируется
+		models.Expense{ID: 32, UserID: 1, TravelID: 2, CategoryID: transport, Amount: 500, Kind: models.KindRefund, CreatedAt: start},
+	)
+	sums := []repository.TravelCategorySummary{
+		{TravelID: 1, CategorySummary: repository.CategorySummary{CategoryID: food, AmountSummary: repository.AmountSummary{Gross: 600}}},
+		{TravelID: 1, CategorySummary: repository.CategorySummary{CategoryID: transport, AmountSummary: repository.AmountSummary{Gross: 120}}},
+		{TravelID: 2, CategorySummary: repository.CategorySummary{CategoryID: food, AmountSummary: repository.AmountSummary{Gross: 100}}},
+		{TravelID: 2, CategorySummary: repository.CategorySummary{CategoryID: transport, AmountSummary: repository.AmountSummary{Gross: 90}}},
+	}
+
+	t.Run("detects outliers and shifts", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockExpenseRepo.EXPECT().GetExpensesByUserID(ctx, uint(1)).Return(expenses, nil)
+		mockExpenseRepo.EXPECT().SumByTravelAndCategory(ctx, uint(1)).Return(sums, nil)
+
+		var saved []models.Insight
+		mockRepo.EXPECT().ReplaceInsights(ctx, uint(1), uint(2), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ uint, _ uint, insights []models.Insight) error {
+				saved = insights
+				return nil
+			})
+
+		err := service.RefreshTravel(ctx, 1, 2)
+		assert.NoError(t, err)
+
+		byType := make(map[models.InsightType][]models.Insight)
+		for _, ins := range saved {
+			assert.Equal(t, uint(1), ins.UserID)
+			assert.Equal(t, uint(2), ins.TravelID)
+			byType[ins.Type] = append(byType[ins.Type], ins)
+		}
+
+		assert.Len(t, byType[models.InsightOutlierExpense], 1)
+		outlier := byType[models.InsightOutlierExpense][0]
+		assert.Equal(t, uint(31), *outlier.ExpenseID)
+		assert.Equal(t, 4.5, outlier.Ratio)
+		assert.Equal(t, models.SeverityWarning, outlier.Severity)
+
+		// день транспорта из одной отмеченной записи повторно не попадает, общий день — ниже порога
+		assert.Empty(t, byType[models.InsightUnusualDay])
+
+		// доля транспорта: 90/190 ≈ 0.47 против 120/720 ≈ 0.17
+		assert.Len(t, byType[models.InsightCategoryShift], 2)
+		shift := byType[models.InsightCategoryShift][1]
+		assert.Equal(t, transport, *shift.CategoryID)
+		assert.Equal(t, 0.4737, shift.Value)
+		assert.Equal(t, 0.1667, shift.Baseline)
+		assert.Equal(t, models.SeverityWarning, shift.Severity)
+	})
+
+	t.Run("unknown travel clears insights", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockRepo.EXPECT().ReplaceInsights(ctx, uint(1), uint(42), nil).Return(nil)
+
+		err := service.RefreshTravel(ctx, 1, 42)
+		assert.NoError(t, err)
+	})
+
+	t.Run("schedule refreshes in background", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(gomock.Any(), uint(1)).Return(travels, nil)
+		mockExpenseRepo.EXPECT().GetExpensesByUserID(gomock.Any(), uint(1)).Return(expenses, nil)
+		mockExpenseRepo.EXPECT().SumByTravelAndCategory(gomock.Any(), uint(1)).Return(sums, nil)
+		mockRepo.EXPECT().ReplaceInsights(gomock.Any(), uint(1), uint(2), gomock.Any()).Return(nil)
+
+		service.Schedule(1, 2)
+		service.Wait()
+	})
+
+	t.Run("schedule without travels refreshes all of them", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(gomock.Any(), uint(1)).Return(travels, nil)
+		mockExpenseRepo.EXPECT().GetExpensesByUserID(gomock.Any(), uint(1)).Return(expenses, nil)
+		mockExpenseRepo.EXPECT().SumByTravelAndCategory(gomock.Any(), uint(1)).Return(sums, nil)
+		mockRepo.EXPECT().ReplaceInsights(gomock.Any(), uint(1), uint(1), gomock.Any()).Return(nil)
+		mockRepo.EXPECT().ReplaceInsights(gomock.Any(), uint(1), uint(2), gomock.Any()).Return(nil)
+
+		service.Schedule(1)
+		service.Wait()
+	})
+}
+
+func TestMedianWithout(t *testing.T) {
+	assert.Equal(t, 2.5, medianWithout([]float64{1, 2, 3, 4, 100}, 100))
+	assert.Equal(t, 3.5, medianWithout([]float64{1, 2, 3, 4, 100}, 2))
+	assert.Equal(t, 1.0, medianWithout([]float64{1, 1, 1}, 1))
+	assert.Equal(t, 0.0, medianWithout([]float64{5}, 5))
+}
+
+func TestDetectUnusualDays(t *testing.T) {
+	base := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	var expenses []models.Expense
+	for i := 0; i < 5; i++ {
+		expenses = append(expenses, models.Expense{ID: uint(i + 1), TravelID: 1, CategoryID: 2, Amount: 50, Kind: models.KindExpense, CreatedAt: base.AddDate(0, 0, i)})
+	}
+	// три обычных расхода в один день: каждый по отдельности не выбивается, но день — в 3 раза дороже
+	for i := 0; i < 3; i++ {
+		expenses = append(expenses, models.Expense{ID: uint(10 + i), TravelID: 2, CategoryID: 2, Amount: 50, Kind: models.KindExpense, CreatedAt: base.AddDate(0, 1, 0)})
+	}
+
+	insights := detectUnusualDays(2, expenses, detectOutliers(2, expenses))
+	assert.Len(t, insights, 2)
+	assert.Nil(t, insights[0].CategoryID) // сначала сумма дня по всем категориям
+	assert.Equal(t, uint(2), *insights[1].CategoryID)
+	assert.Equal(t, "2024-06-01", insights[1].Day.Format("2006-01-02"))
+	assert.Equal(t, 150.0, insights[1].Value)
+	assert.Equal(t, 50.0, insights[1].Baseline)
+	assert.Equal(t, 3.0, insights[1].Ratio)
+}
@@ -4,34 +4,125 @@ import (
 	"context"
 	"time"
 	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
 	"wanderwallet/internal/models"
 )
 
 type UserServiceInterface interface {
-	Register(ctx context.Context, login, password string) (*dto.RegisterResponse, error)
-	Login(ctx context.Context, login, password string) (*dto.LoginResponse, error)
+	Register(ctx context.Context, login, password string, device dto.Device) (*dto.RegisterResponse, error)
+	Login(ctx context.Context, login, password string, device dto.Device) (*dto.LoginResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*dto.Tokens, error)
 	GetUserByID(ctx context.Context, id uint) (*models.User, error)
+	SetLocale(ctx context.Context, userID uint, locale string) (string, error)
+	Logout(ctx context.Context, sessionID uint) error
+	GetSessions(ctx context.Context, userID uint) ([]models.Session, error)
+	RevokeSession(ctx context.Context, userID uint, sessionID uint) error
+	RevokeOtherSessions(ctx context.Context, userID uint, currentID uint) (int64, error)
+	SetEmail(ctx context.Context, userID uint, email string) (string, error)
+	ChangePassword(ctx context.Context, user *models.User, sessionID uint, current, password string) error
+	RequestPasswordReset(ctx context.Context, loginOrEmail string) error
+	ResetPassword(ctx context.Context, token, password string) error
 }
 
+var _ UserServiceInterface = (*UserService)(nil)
+
 type TravelServiceInterface interface {
-	CreateTravel(ctx context.Context, userID uint, title string, start, end time.Time) (*models.Travel, error)
+	CreateTravel(ctx context.Context, userID uint, title string, start, end time.Time, participants int, budget *float64, country string) (*models.Travel, error)
+	SetBudget(ctx context.Context, travel *models.Travel, budget *float64) error
 	GetTravelByID(ctx context.Context, travelID uint) (*models.Travel, error)
 }
 
 type ExpenseServiceInterface interface {
-	CreateExpense(ctx context.Context, expense *models.Expense) (*models.Expense, error)
+	CreateExpense(ctx context.Context, expense *models.Expense, force bool) ([]uint, error)
+	FindDuplicates(ctx context.Context, expense *models.Expense) ([]models.Expense, error)
+	ScanDuplicates(ctx context.Context, userID uint, travelID uint) ([][]models.Expense, error)
 	GetExpensesByUserID(ctx context.Context, id uint) ([]models.Expense, error)
 	UpdateExpense(ctx context.Context, expense *models.Expense) error
 	DeleteExpense(ctx context.Context, id uint) error
+	ReplacePerDiem(ctx context.Context, userID uint, travelID uint, entries []models.Expense) error
 }
 
 type CategoryServiceInterface interface {
 	GetCategoryByID(ctx context.Context, id uint) (*models.Category, error)
-	GetCategoryByName(ctx context.Context, name string) (*models.Category, error)
+	GetCategoryByName(ctx context.Context, userID uint, name string) (*models.Category, error)
+	ResolveCategory(ctx context.Context, userID uint, categoryID *uint, name string) (*models.Category, error)
 	CreateCategory(ctx context.Context, category *models.Category) error
-	DeleteCategory(ctx context.Context, categoryID uint) error
+	UpdateCategory(ctx context.Context, category *models.Category, name string, parentID *uint) error
+	DeleteCategory(ctx context.Context, categoryID uint, children string) error
+	MergeCategory(ctx context.Context, source *models.Category, targetID uint) (*models.Category, error)
+	ReorderCategories(ctx context.Context, userID uint, categoryIDs []uint) ([]models.Category, error)
+	SetArchived(ctx context.Context, userID uint, category *models.Category, archived bool) error
 }
 
 type AnalyticsServiceInterfase interface {
-	Aggregate(ctx context.Context, userID uint, travelID uint, from time.Time, to time.Time) (*dto.AnalyticsResponse, error)
+	CompareTravels(ctx context.Context, userID uint, travelIDs []uint, loc i18n.Locale) (*dto.TravelComparisonResponse, error)
+	Aggregate(ctx context.Context, userID uint, travelID uint, from time.Time, to time.Time, granularity string, tz string, loc i18n.Locale) (*dto.AnalyticsResponse, error)
+}
+
+type ReconciliationServiceInterface interface {
+	ImportTransactions(ctx context.Context, transactions []models.BankTransaction) error
+	GetMatchByID(ctx context.Context, matchID uint) (*models.ReconciliationMatch, error)
+	GetMatches(ctx context.Context, userID uint, travelID uint) ([]models.ReconciliationMatch, error)
+	ProposeMatches(ctx context.Context, userID uint, travelID uint) ([]models.ReconciliationMatch, error)
+	ConfirmMatch(ctx context.Context, match *models.ReconciliationMatch) error
+	RejectMatch(ctx context.Context, match *models.ReconciliationMatch) error
+	MergeMatch(ctx context.Context, match *models.ReconciliationMatch) error
+	GetUnreconciled(ctx context.Context, userID uint, travelID uint) ([]models.BankTransaction, []models.Expense, error)
+}
+
+type ExpenseReportServiceInterface interface {
+	CreateReport(ctx context.Context, userID uint, travelID uint, title string, expenseIDs []uint) (*models.ExpenseReport, error)
+	GetReportByID(ctx context.Context, reportID uint) (*models.ExpenseReport, error)
+	GetReportsByUserID(ctx context.Context, userID uint) ([]models.ExpenseReport, error)
+	GetPendingReports(ctx context.Context, actor models.User) ([]models.ExpenseReport, error)
+	Submit(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error
+	Approve(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error
+	Reject(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error
+	MarkPaid(ctx context.Context, report *models.ExpenseReport, actor models.User, comment string) error
+	CommentLine(ctx context.Context, report *models.ExpenseReport, lineID uint, actor models.User, comment string) error
+	DeleteReport(ctx context.Context, report *models.ExpenseReport, actor models.User) error
+}
+
+type PolicyServiceInterface interface {
+	CreatePolicy(ctx context.Context, policy *models.SpendingPolicy) error
+	GetPolicyByID(ctx context.Context, policyID uint) (*models.SpendingPolicy, error)
+	GetPoliciesByUserID(ctx context.Context, userID uint) ([]models.SpendingPolicy, error)
+	DeletePolicy(ctx context.Context, policy *models.SpendingPolicy) error
+	EvaluateTravel(ctx context.Context, userID uint, travelID uint) error
+	EvaluateUser(ctx context.Context, userID uint) error
+	GetCompliance(ctx context.Context, userID uint, travelID uint) ([]models.PolicyViolation, int, error)
+}
+
+type AllowanceServiceInterface interface {
+	GetRates(ctx context.Context, country string) ([]models.PerDiemRate, error)
+	GeneratePerDiem(ctx context.Context, travel *models.Travel, country string, city string, meals []models.MealsProvided, loc i18n.Locale) ([]models.Expense, error)
+	CreateMileage(ctx context.Context, expense *models.Expense, rate float64, country string, city string) error
+}
+
+type ForecastServiceInterface interface {
+	Forecast(ctx context.Context, travel *models.Travel, now time.Time, loc i18n.Locale) (*dto.ForecastResponse, error)
+}
+
+type InsightServiceInterface interface {
+	GetInsights(ctx context.Context, userID uint, travelID *uint) ([]models.Insight, error)
+	RefreshTravel(ctx context.Context, userID uint, travelID uint) error
+	Schedule(userID uint, travelIDs ...uint)
+}
+
+type StatisticsServiceInterface interface {
+	Statistics(ctx context.Context, userID uint, travelID *uint, top int, tz string, now time.Time, loc i18n.Locale) (*dto.StatisticsResponse, error)
+}
+
+type ReviewServiceInterface interface {
+	Review(ctx context.Context, userID uint, from, to time.Time, loc i18n.Locale) (*dto.ReviewResponse, error)
+}
+
+type ChartServiceInterface interface {
+	Render(ctx context.Context, travel *models.Travel, kind string, req dto.ChartRequest, from, to time.Time, loc i18n.Locale) ([]byte, string, error)
+}
+
+type TripReportServiceInterface interface {
+	Report(ctx context.Context, travel *models.Travel, async bool, now time.Time, loc i18n.Locale) ([]byte, *models.TripReportJob, error)
+	GetJob(ctx context.Context, jobID uint, now time.Time) (*models.TripReportJob, error)
+	JobFile(ctx context.Context, job *models.TripReportJob) ([]byte, error)
 }
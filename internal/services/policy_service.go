@@ -0,0 +1,235 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+var (
+	ErrInvalidPolicy = errors.New("invalid policy")
+)
+
+type PolicyService struct {
+	repo         repository.PolicyRepositoryInterface
+	expenseRepo  repository.ExpenseRepositoryInterface
+	travelRepo   repository.TravelRepositoryInterface
+	categoryRepo repository.CategoryRepositoryInterface
+}
+
+func NewPolicyService(repo repository.PolicyRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface, travelRepo repository.TravelRepositoryInterface, categoryRepo repository.CategoryRepositoryInterface) *PolicyService {
+	return &PolicyService{
+		repo:         repo,
+		expenseRepo:  expenseRepo,
+		travelRepo:   travelRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+// CreatePolicy сохраняет правило и сразу перепроверяет поездки, на которые оно действует
+func (s *PolicyService) CreatePolicy(ctx context.Context, policy *models.SpendingPolicy) error {
+	if policy.Severity == "" {
+		policy.Severity = models.SeverityWarning
+	}
+	if err := validatePolicy(policy); err != nil {
+		return err
+	}
+	if err := s.repo.CreatePolicy(ctx, policy); err != nil {
+		return err
+	}
+	return s.evaluatePolicyScope(ctx, policy)
+}
+
+func (s *PolicyService) GetPolicyByID(ctx context.Context, policyID uint) (*models.SpendingPolicy, error) {
+	return s.repo.GetPolicyByID(ctx, policyID)
+}
+
+func (s *PolicyService) GetPoliciesByUserID(ctx context.Context, userID uint) ([]models.SpendingPolicy, error) {
+	return s.repo.GetPoliciesByUserID(ctx, userID)
+}
+
+func (s *PolicyService) DeletePolicy(ctx context.Context, policy *models.SpendingPolicy) error {
+	if err := s.repo.DeletePolicy(ctx, policy.ID); err != nil {
+		return err
+	}
+	return s.evaluatePolicyScope(ctx, policy)
+}
+
+// EvaluateTravel заново проверяет все расходы поездки по действующим правилам
+// и заменяет сохранённые нарушения.
+func (s *PolicyService) EvaluateTravel(ctx context.Context, userID uint, travelID uint) error {
+	policies, err := s.repo.GetPoliciesForTravel(ctx, userID, travelID)
+	if err != nil {
+		return err
+	}
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return err
+	}
+	categories, err := s.categoryRepo.GetAllCategories(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.ReplaceViolations(ctx, userID, travelID, evaluatePolicies(policies, expenses, categories))
+}
+
+// GetCompliance возвращает нарушения поездки и число проверенных расходов
+func (s *PolicyService) GetCompliance(ctx context.Context, userID uint, travelID uint) ([]models.PolicyViolation, int, error) {
+	violations, err := s.repo.GetViolationsByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, 0, err
+	}
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, 0, err
+	}
+	checked := 0
+	for _, e := range expenses {
+		if e.Kind == models.KindExpense {
+			checked++
+		}
+	}
+	return violations, checked, nil
+}
+
+// EvaluateUser перепроверяет все поездки пользователя
+func (s *PolicyService) EvaluateUser(ctx context.Context, userID uint) error {
+	travels, err := s.travelRepo.GetTravelsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, t := range travels {
+		if err := s.EvaluateTravel(ctx, userID, t.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PolicyService) evaluatePolicyScope(ctx context.Context, policy *models.SpendingPolicy) error {
+	if policy.TravelID != nil {
+		return s.EvaluateTravel(ctx, policy.UserID, *policy.TravelID)
+	}
+	return s.EvaluateUser(ctx, policy.UserID)
+}
+
+func validatePolicy(policy *models.SpendingPolicy) error {
+	if strings.TrimSpace(policy.Name) == "" || !policy.RuleType.Valid() || !policy.Severity.Valid() {
+		return ErrInvalidPolicy
+	}
+	switch policy.RuleType {
+	case models.RuleForbiddenCategory:
+		if policy.CategoryID == nil {
+			return ErrInvalidPolicy
+		}
+	case models.RuleMaxPerExpense, models.RuleMaxPerDay:
+		if policy.Limit <= 0 {
+			return ErrInvalidPolicy
+		}
+	}
+	return nil
+}
+
+// evaluatePolicies проверяет расходы по правилам. Возвраты и доходы не проверяются.
+// Правило с категорией действует и на её подкатегории любой глубины.
+// Для дневного лимита нарушение получает каждый расход дня, превысившего лимит.
+func evaluatePolicies(policies []models.SpendingPolicy, expenses []models.Expense, categories []models.Category) []models.PolicyViolation {
+	parents := make(map[uint]uint, len(categories))
+	for _, c := range categories {
+		if c.ParentID != nil {
+			parents[c.ID] = *c.ParentID
+		}
+	}
+	violations := make([]models.PolicyViolation, 0)
+	for _, p := range policies {
+		var matched []models.Expense
+		for _, e := range expenses {
+			if e.Kind != models.KindExpense {
+				continue
+			}
+			if p.TravelID != nil && *p.TravelID != e.TravelID {
+				continue
+			}
+			if p.CategoryID != nil && !inCategory(parents, e.CategoryID, *p.CategoryID) {
+				continue
+			}
+			matched = append(matched, e)
+		}
+
+		switch p.RuleType {
+		case models.RuleForbiddenCategory:
+			for _, e := range matched {
+				violations = append(violations, newViolation(p, e, "category is not allowed by policy"))
+			}
+
+		case models.RuleMaxPerExpense:
+			for _, e := range matched {
+				if e.Amount > p.Limit && !excusedByNote(p, e) {
+					violations = append(violations, newViolation(p, e,
+						fmt.Sprintf("amount %.2f exceeds limit %.2f", e.Amount, p.Limit)))
+				}
+			}
+
+		case models.RuleMaxPerDay:
+			byDay := make(map[string][]models.Expense)
+			var days []string
+			for _, e := range matched {
+				day := e.CreatedAt.Format("2006-01-02")
+				if _, ok := byDay[day]; !ok {
+					days = append(days, day)
+				}
+				byDay[day] = append(byDay[day], e)
+			}
+			sort.Strings(days)
+			for _, day := range days {
+				total := 0.0
+				for _, e := range byDay[day] {
+					total += e.Amount
+				}
+				if total <= p.Limit {
+					continue
+				}
+				for _, e := range byDay[day] {
+					if excusedByNote(p, e) {
+						continue
+					}
+					violations = append(violations, newViolation(p, e,
+						fmt.Sprintf("daily total %.2f on %s exceeds limit %.2f", total, day, p.Limit)))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// inCategory — категория categoryID совпадает с ancestorID или вложена в неё.
+// parents — родитель каждой категории; цикл в дереве не зацикливает обход.
+func inCategory(parents map[uint]uint, categoryID, ancestorID uint) bool {
+	visited := make(map[uint]bool)
+	for id, ok := categoryID, true; ok && !visited[id]; id, ok = parents[id] {
+		if id == ancestorID {
+			return true
+		}
+		visited[id] = true
+	}
+	return false
+}
+
+func excusedByNote(p models.SpendingPolicy, e models.Expense) bool {
+	return p.RequiresNote && strings.TrimSpace(e.Description) != ""
+}
+
+func newViolation(p models.SpendingPolicy, e models.Expense, message string) models.PolicyViolation {
+	return models.PolicyViolation{
+		UserID:    e.UserID,
+		TravelID:  e.TravelID,
+		ExpenseID: e.ID,
+		PolicyID:  p.ID,
+		Severity:  p.Severity,
+		Message:   fmt.Sprintf("%s: %s", p.Name, message),
+	}
+}
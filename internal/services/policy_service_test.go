@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluatePolicies(t *testing.T) {
+	day := time.Date(2024, 5, 10, 10, 0, 0, 0, time.UTC)
+	categoryID := uint(3)
+	expenses := []models.Expense{
+		{ID: 1, UserID: 1, TravelID: 2, CategoryID: 3, Amount: 500, CreatedAt: day, Kind: models.KindExpense},
+		{ID: 2, UserID: 1, TravelID: 2, CategoryID: 4, Amount: 1500, CreatedAt: day, Kind: models.KindExpense},
+		{ID: 3, UserID: 1, TravelID: 2, CategoryID: 4, Amount: 2000, CreatedAt: day.Add(time.Hour), Kind: models.KindExpense, Description: "ужин с клиентом"},
+		{ID: 4, UserID: 1, TravelID: 2, CategoryID: 4, Amount: 5000, CreatedAt: day, Kind: models.KindRefund},
+		{ID: 5, UserID: 1, TravelID: 2, CategoryID: 4, Amount: 100, CreatedAt: day.AddDate(0, 0, 1), Kind: models.KindExpense},
+	}
+
+	t.Run("forbidden category", func(t *testing.T) {
+		policies := []models.SpendingPolicy{
+			{ID: 1, Name: "no bars", RuleType: models.RuleForbiddenCategory, CategoryID: &categoryID, Severity: models.SeverityCritical},
+		}
+
+		violations := evaluatePolicies(policies, expenses, nil)
+		assert.Len(t, violations, 1)
+		assert.Equal(t, uint(1), violations[0].ExpenseID)
+		assert.Equal(t, models.SeverityCritical, violations[0].Severity)
+	})
+
+	t.Run("per expense limit excused by note", func(t *testing.T) {
+		policies := []models.SpendingPolicy{
+			{ID: 2, Name: "meal", RuleType: models.RuleMaxPerExpense, Limit: 1000, RequiresNote: true, Severity: models.SeverityWarning},
+		}
+
+		violations := evaluatePolicies(policies, expenses, nil)
+		assert.Len(t, violations, 1)
+		assert.Equal(t, uint(2), violations[0].ExpenseID)
+	})
+
+	t.Run("daily limit", func(t *testing.T) {
+		policies := []models.SpendingPolicy{
+			{ID: 3, Name: "daily", RuleType: models.RuleMaxPerDay, Limit: 3000, Severity: models.SeverityInfo},
+		}
+
+		violations := evaluatePolicies(policies, expenses, nil)
+		assert.Len(t, violations, 3)
+		for _, v := range violations {
+			assert.NotEqual(t, uint(5), v.ExpenseID)
+			assert.Equal(t, uint(3), v.PolicyID)
+		}
+	})
+
+	t.Run("subcategories", func(t *testing.T) {
+		// 3 → 6 → 7: правила по категории 3 действуют на расходы во вложенных категориях
+		child, grandchild := uint(6), uint(7)
+		categories := []models.Category{
+			{ID: 3},
+			{ID: 6, ParentID: &categoryID},
+			{ID: 7, ParentID: &child},
+			{ID: 8},
+		}
+		nested := []models.Expense{
+			{ID: 10, UserID: 1, TravelID: 2, CategoryID: grandchild, Amount: 700, CreatedAt: day, Kind: models.KindExpense},
+			{ID: 11, UserID: 1, TravelID: 2, CategoryID: child, Amount: 400, CreatedAt: day, Kind: models.KindExpense},
+			{ID: 12, UserID: 1, TravelID: 2, CategoryID: 8, Amount: 900, CreatedAt: day, Kind: models.KindExpense},
+		}
+		policies := []models.SpendingPolicy{
+			{ID: 1, Name: "no bars", RuleType: models.RuleForbiddenCategory, CategoryID: &categoryID, Severity: models.SeverityCritical},
+			{ID: 2, Name: "bar tab", RuleType: models.RuleMaxPerExpense, CategoryID: &categoryID, Limit: 500, Severity: models.SeverityWarning},
+			{ID: 3, Name: "bars daily", RuleType: models.RuleMaxPerDay, CategoryID: &categoryID, Limit: 1000, Severity: models.SeverityInfo},
+			{ID: 4, Name: "cocktails", RuleType: models.RuleForbiddenCategory, CategoryID: &grandchild, Severity: models.SeverityInfo},
+		}
+
+		byPolicy := make(map[uint][]uint)
+		for _, v := range evaluatePolicies(policies, nested, categories) {
+			byPolicy[v.PolicyID] = append(byPolicy[v.PolicyID], v.ExpenseID)
+		}
+		assert.ElementsMatch(t, []uint{10, 11}, byPolicy[1])
+		assert.Equal(t, []uint{10}, byPolicy[2])
+		assert.ElementsMatch(t, []uint{10, 11}, byPolicy[3]) // 700 + 400 без расхода в чужой категории
+		assert.Equal(t, []uint{10}, byPolicy[4])             // правило подкатегории не действует на родителя
+	})
+}
+
+func TestPolicyService_CreatePolicy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockPolicyRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockTravelRepo := mocks.NewMockTravelRepositoryInterface(ctrl)
+	mockCategoryRepo := mocks.NewMockCategoryRepositoryInterface(ctrl)
+	service := NewPolicyService(mockRepo, mockExpenseRepo, mockTravelRepo, mockCategoryRepo)
+	ctx := context.Background()
+
+	t.Run("invalid policy", func(t *testing.T) {
+		policy := &models.SpendingPolicy{UserID: 1, Name: "limit", RuleType: models.RuleMaxPerExpense}
+
+		err := service.CreatePolicy(ctx, policy)
+		assert.ErrorIs(t, err, ErrInvalidPolicy)
+	})
+
+	t.Run("travel policy evaluates travel", func(t *testing.T) {
+		travelID := uint(2)
+		policy := &models.SpendingPolicy{UserID: 1, TravelID: &travelID, Name: "limit", RuleType: models.RuleMaxPerExpense, Limit: 100}
+		expenses := []models.Expense{{ID: 1, UserID: 1, TravelID: 2, Amount: 150, Kind: models.KindExpense}}
+
+		mockRepo.EXPECT().CreatePolicy(ctx, policy).Return(nil)
+		mockRepo.EXPECT().GetPoliciesForTravel(ctx, uint(1), uint(2)).Return([]models.SpendingPolicy{*policy}, nil)
+		mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(2)).Return(expenses, nil)
+		mockCategoryRepo.EXPECT().GetAllCategories(ctx, uint(1)).Return(nil, nil)
+		mockRepo.EXPECT().
+			ReplaceViolations(ctx, uint(1), uint(2), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _, _ uint, violations []models.PolicyViolation) error {
+				assert.Len(t, violations, 1)
+				assert.Equal(t, uint(1), violations[0].ExpenseID)
+				return nil
+			})
+
+		err := service.CreatePolicy(ctx, policy)
+		assert.NoError(t, err)
+		assert.Equal(t, models.SeverityWarning, policy.Severity)
+	})
+}
@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+var (
+	ErrMatchNotProposed = errors.New("match is not in proposed state")
+	// ErrMatchDirection — списание сопоставлено с поступлением или наоборот
+	ErrMatchDirection = errors.New("transaction direction does not match the entry kind")
+)
+
+const (
+	// Допустимое относительное расхождение сумм (5%)
+	matchAmountTolerance = 0.05
+	// Максимальная разница в днях между датой расхода и датой списания
+	matchMaxDayDistance = 3
+	// Пары с меньшей оценкой не предлагаются
+	matchMinScore = 0.5
+
+	matchAmountWeight      = 0.5
+	matchDateWeight        = 0.3
+	matchDescriptionWeight = 0.2
+)
+
+type ReconciliationService struct {
+	repo        repository.ReconciliationRepositoryInterface
+	expenseRepo repository.ExpenseRepositoryInterface
+	policies    PolicyServiceInterface
+	insights    InsightServiceInterface
+}
+
+func NewReconciliationService(repo repository.ReconciliationRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface, policies PolicyServiceInterface, insights InsightServiceInterface) *ReconciliationService {
+	return &ReconciliationService{
+		repo:        repo,
+		expenseRepo: expenseRepo,
+		policies:    policies,
+		insights:    insights,
+	}
+}
+
+// ImportTransactions сохраняет строки выписки. Положительная сумма — списание, отрицательная —
+// поступление (возврат, компенсация или доход); нулевые суммы не принимаются.
+func (s *ReconciliationService) ImportTransactions(ctx context.Context, transactions []models.BankTransaction) error {
+	for _, t := range transactions {
+		if t.Amount == 0 || math.IsNaN(t.Amount) || math.IsInf(t.Amount, 0) {
+			return ErrInvalidAmount
+		}
+	}
+	return s.repo.CreateTransactions(ctx, transactions)
+}
+
+func (s *ReconciliationService) GetMatchByID(ctx context.Context, matchID uint) (*models.ReconciliationMatch, error) {
+	return s.repo.GetMatchByID(ctx, matchID)
+}
+
+func (s *ReconciliationService) GetMatches(ctx context.Context, userID uint, travelID uint) ([]models.ReconciliationMatch, error) {
+	return s.repo.GetMatchesByTravelID(ctx, userID, travelID)
+}
+
+// ProposeMatches пересчитывает предложения по поездке: каждая свободная банковская
+// операция получает не более одного расхода с наилучшей оценкой.
+func (s *ReconciliationService) ProposeMatches(ctx context.Context, userID uint, travelID uint) ([]models.ReconciliationMatch, error) {
+	transactions, err := s.repo.GetTransactionsByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repo.GetMatchesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, err
+	}
+
+	usedTransactions := make(map[uint]bool)
+	usedExpenses := make(map[uint]bool)
+	rejected := make(map[[2]uint]bool)
+	for _, m := range existing {
+		switch m.Status {
+		case models.MatchConfirmed:
+			usedTransactions[m.TransactionID] = true
+			usedExpenses[m.ExpenseID] = true
+		case models.MatchRejected:
+			rejected[[2]uint{m.TransactionID, m.ExpenseID}] = true
+		}
+	}
+	for _, t := range transactions {
+		if t.ExpenseID != nil {
+			usedTransactions[t.ID] = true
+			usedExpenses[*t.ExpenseID] = true
+		}
+	}
+
+	var candidates []models.ReconciliationMatch
+	for _, t := range transactions {
+		if usedTransactions[t.ID] {
+			continue
+		}
+		for _, e := range expenses {
+			if usedExpenses[e.ID] || rejected[[2]uint{t.ID, e.ID}] {
+				continue
+			}
+			score := matchScore(t, e)
+			if score < matchMinScore {
+				continue
+			}
+			candidates = append(candidates, models.ReconciliationMatch{
+				UserID:        userID,
+				TravelID:      travelID,
+				TransactionID: t.ID,
+				ExpenseID:     e.ID,
+				Score:         score,
+				Status:        models.MatchProposed,
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+
+	proposed := make([]models.ReconciliationMatch, 0)
+	for _, c := range candidates {
+		if usedTransactions[c.TransactionID] || usedExpenses[c.ExpenseID] {
+			continue
+		}
+		usedTransactions[c.TransactionID] = true
+		usedExpenses[c.ExpenseID] = true
+		proposed = append(proposed, c)
+	}
+
+	if err := s.repo.ReplaceProposedMatches(ctx, userID, travelID, proposed); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMatchesByTravelID(ctx, userID, travelID)
+}
+
+func (s *ReconciliationService) ConfirmMatch(ctx context.Context, match *models.ReconciliationMatch) error {
+	if match.Status != models.MatchProposed {
+		return ErrMatchNotProposed
+	}
+	return s.repo.ConfirmMatch(ctx, match, nil)
+}
+
+func (s *ReconciliationService) RejectMatch(ctx context.Context, match *models.ReconciliationMatch) error {
+	if match.Status != models.MatchProposed {
+		return ErrMatchNotProposed
+	}
+	return s.repo.UpdateMatchStatus(ctx, match.ID, models.MatchRejected)
+}
+
+// MergeMatch подтверждает пару и переносит в расход данные банка:
+// сумму и дату списания, а также описание, если в расходе его не было.
+// Сумма переносится по модулю: направление по-прежнему задаёт вид записи.
+func (s *ReconciliationService) MergeMatch(ctx context.Context, match *models.ReconciliationMatch) error {
+	if match.Status != models.MatchProposed {
+		return ErrMatchNotProposed
+	}
+	if err := checkNotReported(ctx, s.expenseRepo, match.ExpenseID); err != nil {
+		return err
+	}
+	expense, err := s.expenseRepo.GetExpenseByID(ctx, match.ExpenseID)
+	if err != nil {
+		return err
+	}
+	if !matchesDirection(match.Transaction, *expense) {
+		return ErrMatchDirection
+	}
+	expense.Amount = math.Abs(match.Transaction.Amount)
+	expense.CreatedAt = match.Transaction.Date
+	expense.HasTime = false // в выписке только дата
+	if strings.TrimSpace(expense.Description) == "" {
+		expense.Description = match.Transaction.Description
+	}
+	if err := normalizeEntry(ctx, s.expenseRepo, expense); err != nil {
+		return err
+	}
+	if err := s.repo.ConfirmMatch(ctx, match, expense); err != nil {
+		return err
+	}
+	// сумма и дата расхода могли измениться — перепроверяем правила трат и наблюдения.
+	// Пара уже подтверждена, поэтому ошибки только логируются.
+	if err := s.policies.EvaluateTravel(ctx, expense.UserID, expense.TravelID); err != nil {
+		log.Printf("Failed to evaluate policies for travel %d: %v\n", expense.TravelID, err)
+	}
+	s.insights.Schedule(expense.UserID, expense.TravelID)
+	return nil
+}
+
+// GetUnreconciled возвращает операции без расхода и расходы без операции.
+func (s *ReconciliationService) GetUnreconciled(ctx context.Context, userID uint, travelID uint) ([]models.BankTransaction, []models.Expense, error) {
+	transactions, err := s.repo.GetTransactionsByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, nil, err
+	}
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, userID, travelID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	unmatchedTransactions := make([]models.BankTransaction, 0)
+	unmatchedExpenses := make([]models.Expense, 0)
+	matchedExpenses := make(map[uint]bool)
+	for _, t := range transactions {
+		if t.ExpenseID == nil {
+			unmatchedTransactions = append(unmatchedTransactions, t)
+			continue
+		}
+		matchedExpenses[*t.ExpenseID] = true
+	}
+	for _, e := range expenses {
+		if !matchedExpenses[e.ID] {
+			unmatchedExpenses = append(unmatchedExpenses, e)
+		}
+	}
+	return unmatchedTransactions, unmatchedExpenses, nil
+}
+
+// matchesDirection — списание сопоставляется только с расходом, поступление — с возвратом,
+// компенсацией или доходом. Начисления по нормативу через банк не проходят.
+func matchesDirection(t models.BankTransaction, e models.Expense) bool {
+	if t.Amount > 0 {
+		return e.Kind == models.KindExpense
+	}
+	return e.Kind.IsRefund() || e.Kind == models.KindIncome
+}
+
+// matchScore оценивает пару от 0 до 1 по сумме, близости дат и похожести описаний.
+// Если направления не совпадают или сумма либо дата выходят за допустимые пределы,
+// возвращается 0.
+func matchScore(t models.BankTransaction, e models.Expense) float64 {
+	if !matchesDirection(t, e) {
+		return 0
+	}
+	amount := math.Abs(t.Amount)
+	maxAmount := math.Max(amount, e.Amount)
+	amountDiff := 0.0
+	if maxAmount > 0 {
+		amountDiff = math.Abs(amount-e.Amount) / maxAmount
+	}
+	if amountDiff > matchAmountTolerance {
+		return 0
+	}
+	amountScore := 1 - amountDiff/matchAmountTolerance
+
+	days := math.Abs(dayOf(t.Date).Sub(dayOf(e.CreatedAt)).Hours() / 24)
+	if days > matchMaxDayDistance {
+		return 0
+	}
+	dateScore := 1 - days/(matchMaxDayDistance+1)
+
+	descScore := textSimilarity(t.Description, e.Description)
+
+	return matchAmountWeight*amountScore + matchDateWeight*dateScore + matchDescriptionWeight*descScore
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// textSimilarity — коэффициент Жаккара по словам без учёта регистра
+func textSimilarity(a, b string) float64 {
+	wordsA := tokenize(a)
+	wordsB := tokenize(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range wordsA {
+		if wordsB[w] {
+			common++
+		}
+	}
+	union := len(wordsA) + len(wordsB) - common
+	return float64(common) / float64(union)
+}
+
+func tokenize(s string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	res := make(map[string]bool, len(words))
+	for _, w := range words {
+		res[w] = true
+	}
+	return res
+}
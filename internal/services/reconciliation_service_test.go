@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconciliationService_ProposeMatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockReconciliationRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewReconciliationService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+
+	day := time.Date(2024, 5, 10, 0, 0, 0, 0, time.UTC)
+	transactions := []models.BankTransaction{
+		{ID: 1, Amount: 1200, Date: day, Description: "TAXI MOSCOW"},
+		{ID: 2, Amount: 300, Date: day.AddDate(0, 0, 1), Description: "Coffee shop"},
+	}
+	expenses := []models.Expense{
+		{ID: 10, Kind: models.KindExpense, Amount: 1200, CreatedAt: day, Description: "taxi to airport"},
+		{ID: 11, Kind: models.KindExpense, Amount: 1190, CreatedAt: day.AddDate(0, 0, 2), Description: "dinner"},
+		{ID: 12, Kind: models.KindExpense, Amount: 300, CreatedAt: day.AddDate(0, 0, 1), Description: "coffee"},
+		// возврат той же суммы не подходит к списанию
+		{ID: 13, Kind: models.KindRefund, Amount: 1200, CreatedAt: day, Description: "taxi refund"},
+	}
+	existing := []models.ReconciliationMatch{
+		{TransactionID: 2, ExpenseID: 12, Status: models.MatchRejected},
+	}
+
+	mockRepo.EXPECT().GetTransactionsByTravelID(ctx, uint(1), uint(7)).Return(transactions, nil)
+	mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(7)).Return(expenses, nil)
+	mockRepo.EXPECT().GetMatchesByTravelID(ctx, uint(1), uint(7)).Return(existing, nil)
+	mockRepo.EXPECT().
+		ReplaceProposedMatches(ctx, uint(1), uint(7), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _, _ uint, matches []models.ReconciliationMatch) error {
+			assert.Len(t, matches, 1)
+			assert.Equal(t, uint(1), matches[0].TransactionID)
+			assert.Equal(t, uint(10), matches[0].ExpenseID)
+			assert.Equal(t, models.MatchProposed, matches[0].Status)
+			return nil
+		})
+	mockRepo.EXPECT().GetMatchesByTravelID(ctx, uint(1), uint(7)).Return(nil, nil)
+
+	_, err := service.ProposeMatches(ctx, 1, 7)
+	assert.NoError(t, err)
+}
+
+func TestReconciliationService_ConfirmMatch_NotProposed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockReconciliationRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewReconciliationService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+
+	match := &models.ReconciliationMatch{ID: 1, Status: models.MatchRejected}
+
+	err := service.ConfirmMatch(context.Background(), match)
+	assert.ErrorIs(t, err, ErrMatchNotProposed)
+}
+
+func TestReconciliationService_MergeMatch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockReconciliationRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewReconciliationService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+
+	bankDate := time.Date(2024, 5, 11, 0, 0, 0, 0, time.UTC)
+	match := &models.ReconciliationMatch{
+		ID:            3,
+		TransactionID: 1,
+		ExpenseID:     10,
+		Status:        models.MatchProposed,
+		Transaction:   models.BankTransaction{ID: 1, Amount: 1210.5, Date: bankDate, Description: "TAXI"},
+	}
+	expense := &models.Expense{ID: 10, UserID: 1, TravelID: 7, Kind: models.KindExpense, Amount: 1200, CreatedAt: bankDate.AddDate(0, 0, -1)}
+
+	mockExpenseRepo.EXPECT().GetReportStatuses(ctx, uint(10)).Return(nil, nil)
+	mockExpenseRepo.EXPECT().GetExpenseByID(ctx, uint(10)).Return(expense, nil)
+	mockRepo.EXPECT().ConfirmMatch(ctx, match, expense).Return(nil)
+	mockInsights.EXPECT().Schedule(uint(1), uint(7))
+	// пара уже сохранена: ошибка проверки правил не превращает слияние в отказ
+	mockPolicies.EXPECT().EvaluateTravel(ctx, uint(1), uint(7)).Return(errors.New("db error"))
+
+	err := service.MergeMatch(ctx, match)
+	assert.NoError(t, err)
+	assert.Equal(t, 1210.5, expense.Amount)
+	assert.Equal(t, bankDate, expense.CreatedAt)
+	assert.Equal(t, "TAXI", expense.Description)
+
+	t.Run("credit keeps the refund positive", func(t *testing.T) {
+		credit := &models.ReconciliationMatch{ID: 4, TransactionID: 2, ExpenseID: 11, Status: models.MatchProposed,
+			Transaction: models.BankTransaction{ID: 2, Amount: -500, Date: bankDate}}
+		refund := &models.Expense{ID: 11, UserID: 1, TravelID: 7, Kind: models.KindRefund, Amount: 480, Description: "return"}
+
+		mockExpenseRepo.EXPECT().GetReportStatuses(ctx, uint(11)).Return(nil, nil)
+		mockExpenseRepo.EXPECT().GetExpenseByID(ctx, uint(11)).Return(refund, nil)
+		mockRepo.EXPECT().ConfirmMatch(ctx, credit, refund).Return(nil)
+		mockInsights.EXPECT().Schedule(uint(1), uint(7))
+		mockPolicies.EXPECT().EvaluateTravel(ctx, uint(1), uint(7)).Return(nil)
+
+		assert.NoError(t, service.MergeMatch(ctx, credit))
+		assert.Equal(t, 500.0, refund.Amount)
+		assert.Equal(t, models.KindRefund, refund.Kind)
+	})
+
+	t.Run("debit is not merged into income", func(t *testing.T) {
+		debit := &models.ReconciliationMatch{ID: 5, TransactionID: 3, ExpenseID: 12, Status: models.MatchProposed,
+			Transaction: models.BankTransaction{ID: 3, Amount: 500, Date: bankDate}}
+		income := &models.Expense{ID: 12, UserID: 1, TravelID: 7, Kind: models.KindIncome, Amount: 500}
+
+		mockExpenseRepo.EXPECT().GetReportStatuses(ctx, uint(12)).Return(nil, nil)
+		mockExpenseRepo.EXPECT().GetExpenseByID(ctx, uint(12)).Return(income, nil)
+
+		assert.ErrorIs(t, service.MergeMatch(ctx, debit), ErrMatchDirection)
+		assert.Equal(t, 500.0, income.Amount)
+	})
+}
+
+func TestReconciliationService_ImportTransactions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockReconciliationRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewReconciliationService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+
+	err := service.ImportTransactions(ctx, []models.BankTransaction{{Amount: 100}, {Amount: 0}})
+	assert.ErrorIs(t, err, ErrInvalidAmount)
+
+	transactions := []models.BankTransaction{{Amount: 100}, {Amount: -40}}
+	mockRepo.EXPECT().CreateTransactions(ctx, transactions).Return(nil)
+	assert.NoError(t, service.ImportTransactions(ctx, transactions))
+}
+
+func TestReconciliationService_GetUnreconciled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockReconciliationRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockPolicies := mocks.NewMockPolicyServiceInterface(ctrl)
+	mockInsights := mocks.NewMockInsightServiceInterface(ctrl)
+	service := NewReconciliationService(mockRepo, mockExpenseRepo, mockPolicies, mockInsights)
+	ctx := context.Background()
+
+	expenseID := uint(10)
+	mockRepo.EXPECT().GetTransactionsByTravelID(ctx, uint(1), uint(7)).Return([]models.BankTransaction{
+		{ID: 1, ExpenseID: &expenseID},
+		{ID: 2},
+	}, nil)
+	mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(7)).Return([]models.Expense{
+		{ID: 10},
+		{ID: 11},
+	}, nil)
+
+	transactions, expenses, err := service.GetUnreconciled(ctx, 1, 7)
+	assert.NoError(t, err)
+	assert.Len(t, transactions, 1)
+	assert.Equal(t, uint(2), transactions[0].ID)
+	assert.Len(t, expenses, 1)
+	assert.Equal(t, uint(11), expenses[0].ID)
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/repository"
+)
+
+// reviewTopCategories — сколько категорий попадает в итоги периода
+const reviewTopCategories = 5
+
+var ErrInvalidRange = errors.New("from date must be before to date")
+
+type ReviewService struct {
+	expenseRepo repository.ExpenseRepositoryInterface
+	travelRepo  repository.TravelRepositoryInterface
+}
+
+func NewReviewService(expenseRepo repository.ExpenseRepositoryInterface, travelRepo repository.TravelRepositoryInterface) *ReviewService {
+	return &ReviewService{
+		expenseRepo: expenseRepo,
+		travelRepo:  travelRepo,
+	}
+}
+
+// Review подводит итоги трат по всем поездкам пользователя за период с from по to
+// включительно. Суммы считаются в БД одним запросом; записи относятся к периоду по своей дате,
+// даже если поездка началась раньше или закончилась позже.
+func (s *ReviewService) Review(ctx context.Context, userID uint, from, to time.Time, loc i18n.Locale) (*dto.ReviewResponse, error) {
+	from = dateOf(from)
+	to = dateOf(to)
+	if to.Before(from) {
+		return nil, ErrInvalidRange
+	}
+	// последний день периода входит целиком, включая записи со временем
+	until := to.AddDate(0, 0, 1).Add(-time.Microsecond)
+
+	sums, err := s.expenseRepo.Summarize(ctx, repository.SummaryQuery{
+		UserID:   userID,
+		From:     &from,
+		To:       &until,
+		Unit:     GranularityMonth,
+		Timezone: "UTC",
+		ByTravel: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	byCategory, byTravel := sums.ByCategory, sums.ByTravel
+	travels, err := s.travelRepo.GetTravelsInRange(ctx, userID, from, until)
+	if err != nil {
+		return nil, err
+	}
+	days, err := s.travelRepo.CountTravelDays(ctx, userID, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	series, err := fillTimeSeries(sums.ByPeriod, GranularityMonth, from, to)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.ReviewResponse{
+		From:          from.Format("2006-01-02"),
+		To:            to.Format("2006-01-02"),
+		Total:         toAmountSummary(sums.Total),
+		Trips:         len(travels),
+		DaysTravelled: days,
+		Countries:     make([]string, 0),
+		TopCategories: make([]dto.ReviewCategory, 0, reviewTopCategories),
+		ByMonth:       series,
+	}
+
+	seen := make(map[string]bool)
+	for _, t := range travels {
+		if t.Country != "" && !seen[t.Country] {
+			seen[t.Country] = true
+			resp.Countries = append(resp.Countries, t.Country)
+		}
+	}
+	sort.Strings(resp.Countries)
+
+	net := sums.Total.Net()
+	sort.Slice(byCategory, func(i, j int) bool {
+		if byCategory[i].Net() != byCategory[j].Net() {
+			return byCategory[i].Net() > byCategory[j].Net()
+		}
+		return byCategory[i].CategoryID < byCategory[j].CategoryID
+	})
+	for _, c := range byCategory {
+		if len(resp.TopCategories) == reviewTopCategories || c.Net() <= 0 {
+			break
+		}
+		category := dto.ReviewCategory{
+			CategoryID: fmt.Sprintf("%v", c.CategoryID),
+			Category:   i18n.CategoryName(loc, c.CategoryKey, c.Category),
+			Net:        roundMoney(c.Net()),
+		}
+		if net > 0 {
+			category.Share = roundShare(c.Net() / net)
+		}
+		resp.TopCategories = append(resp.TopCategories, category)
+	}
+
+	// самая дорогая и самая дешёвая выбираются среди поездок с тратами за период
+	sort.Slice(byTravel, func(i, j int) bool {
+		if byTravel[i].Net() != byTravel[j].Net() {
+			return byTravel[i].Net() > byTravel[j].Net()
+		}
+		return byTravel[i].TravelID < byTravel[j].TravelID
+	})
+	if len(byTravel) > 0 {
+		resp.MostExpensiveTrip = toReviewTrip(byTravel[0])
+		resp.LeastExpensiveTrip = toReviewTrip(byTravel[len(byTravel)-1])
+	}
+	return resp, nil
+}
+
+func toReviewTrip(t repository.TravelSummary) *dto.ReviewTrip {
+	return &dto.ReviewTrip{
+		TravelID: fmt.Sprintf("%v", t.TravelID),
+		Title:    t.Title,
+		Net:      roundMoney(t.Net()),
+	}
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewService_Review(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockTravelRepo := mocks.NewMockTravelRepositoryInterface(ctrl)
+	service := NewReviewService(mockRepo, mockTravelRepo)
+	ctx := context.Background()
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Microsecond)
+
+	t.Run("year", func(t *testing.T) {
+		mockRepo.EXPECT().Summarize(ctx, repository.SummaryQuery{
+			UserID: 1, From: &from, To: &until, Unit: GranularityMonth, Timezone: "UTC", ByTravel: true,
+		}).Return(&repository.AnalyticsSums{
+			Total: repository.AmountSummary{Gross: 1100, Refunds: 100},
+			ByCategory: []repository.CategorySummary{
+				{CategoryID: 3, Category: "Жильё", CategoryKey: "lodging", AmountSummary: repository.AmountSummary{Gross: 600}},
+				{CategoryID: 2, Category: "Питание", CategoryKey: "food", AmountSummary: repository.AmountSummary{Gross: 500, Refunds: 100}},
+			},
+			ByPeriod: []repository.PeriodSummary{
+				{Start: "2024-03-01", AmountSummary: repository.AmountSummary{Gross: 1100, Refunds: 100}},
+			},
+			ByTravel: []repository.TravelSummary{
+				{TravelID: 1, Title: "Рим", AmountSummary: repository.AmountSummary{Gross: 300}},
+				{TravelID: 2, Title: "Токио", AmountSummary: repository.AmountSummary{Gross: 800, Refunds: 100}},
+			},
+		}, nil)
+		mockTravelRepo.EXPECT().GetTravelsInRange(ctx, uint(1), from, until).Return([]models.Travel{
+			{ID: 1, Country: "IT"}, {ID: 2, Country: "JP"}, {ID: 3, Country: "IT"}, {ID: 4},
+		}, nil)
+		mockTravelRepo.EXPECT().CountTravelDays(ctx, uint(1), from, until).Return(12, nil)
+
+		resp, err := service.Review(ctx, 1, from, to, i18n.EN)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-01-01", resp.From)
+		assert.Equal(t, "2024-12-31", resp.To)
+		assert.Equal(t, 1000.0, resp.Total.Net)
+		assert.Equal(t, 4, resp.Trips)
+		assert.Equal(t, 12, resp.DaysTravelled)
+		assert.Equal(t, []string{"IT", "JP"}, resp.Countries)
+
+		assert.Len(t, resp.TopCategories, 2)
+		assert.Equal(t, "Lodging", resp.TopCategories[0].Category)
+		assert.Equal(t, 0.6, resp.TopCategories[0].Share)
+
+		assert.Equal(t, "Токио", resp.MostExpensiveTrip.Title)
+		assert.Equal(t, 700.0, resp.MostExpensiveTrip.Net)
+		assert.Equal(t, "Рим", resp.LeastExpensiveTrip.Title)
+
+		assert.Len(t, resp.ByMonth, 12)
+		assert.Equal(t, "2024-03-01", resp.ByMonth[2].Start)
+		assert.Equal(t, 1000.0, resp.ByMonth[2].Net)
+		assert.Equal(t, 0.0, resp.ByMonth[11].Net)
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := service.Review(ctx, 1, to, from, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidRange)
+	})
+}
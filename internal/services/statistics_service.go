@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+// Размер списка самых крупных расходов
+const (
+	DefaultTopExpenses = 10
+	MaxTopExpenses     = 100
+)
+
+var ErrInvalidTop = errors.New("top must be between 1 and 100")
+
+type StatisticsService struct {
+	expenseRepo repository.ExpenseRepositoryInterface
+	travelRepo  repository.TravelRepositoryInterface
+}
+
+func NewStatisticsService(expenseRepo repository.ExpenseRepositoryInterface, travelRepo repository.TravelRepositoryInterface) *StatisticsService {
+	return &StatisticsService{
+		expenseRepo: expenseRepo,
+		travelRepo:  travelRepo,
+	}
+}
+
+// Statistics считает описательную статистику расходов поездки или, если travelID == nil,
+// всех поездок пользователя. Учитываются только записи вида expense.
+// День недели и час берутся в часовом поясе tz; у записей без времени — по дате как есть,
+// а в распределение по часам они не попадают. Дни без трат считаются по дням поездок
+// до now включительно.
+func (s *StatisticsService) Statistics(ctx context.Context, userID uint, travelID *uint, top int, tz string, now time.Time, loc i18n.Locale) (*dto.StatisticsResponse, error) {
+	if top == 0 {
+		top = DefaultTopExpenses
+	}
+	if top < 1 || top > MaxTopExpenses {
+		return nil, ErrInvalidTop
+	}
+	if tz == "" {
+		tz = "UTC"
+	}
+	location, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	travels, err := s.travelRepo.GetTravelsByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var expenses []models.Expense
+	if travelID != nil {
+		var travel *models.Travel
+		for i := range travels {
+			if travels[i].ID == *travelID {
+				travel = &travels[i]
+			}
+		}
+		if travel == nil {
+			return nil, ErrTravelNotFound
+		}
+		travels = []models.Travel{*travel}
+		expenses, err = s.expenseRepo.GetExpensesByTravelID(ctx, userID, *travelID)
+	} else {
+		expenses, err = s.expenseRepo.GetExpensesByUserTimeAndCategory(ctx, repository.ExpenseFilter{UserID: userID})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.StatisticsResponse{
+		Timezone:    tz,
+		TopExpenses: make([]dto.TopExpense, 0, top),
+		Categories:  make([]dto.CategoryStatistics, 0),
+		ByWeekday:   make([]dto.WeekdaySpending, 7),
+		ByHour:      make([]dto.HourSpending, 24),
+	}
+	if travelID != nil {
+		resp.TravelID = fmt.Sprintf("%v", *travelID)
+	}
+	for i := range resp.ByWeekday {
+		// неделя начинается с понедельника
+		resp.ByWeekday[i].Weekday = strings.ToLower(time.Weekday((i + 1) % 7).String())
+	}
+	for i := range resp.ByHour {
+		resp.ByHour[i].Hour = i
+	}
+
+	spending := make([]models.Expense, 0, len(expenses))
+	for _, e := range expenses {
+		if e.Kind == models.KindExpense {
+			spending = append(spending, e)
+		}
+	}
+	resp.Expenses = len(spending)
+
+	type categoryAmounts struct {
+		id      uint
+		name    string
+		amounts []float64
+	}
+	byCategory := make(map[uint]*categoryAmounts)
+	byDay := make(map[string]*dto.DaySpending)
+	for _, e := range spending {
+		resp.Total += e.Amount
+
+		c, ok := byCategory[e.CategoryID]
+		if !ok {
+			c = &categoryAmounts{id: e.CategoryID, name: i18n.CategoryName(loc, e.Category.Key, e.Category.Name)}
+			byCategory[e.CategoryID] = c
+		}
+		c.amounts = append(c.amounts, e.Amount)
+
+		at := e.CreatedAt.UTC()
+		if e.HasTime {
+			at = e.CreatedAt.In(location)
+			resp.TimedExpenses++
+			resp.ByHour[at.Hour()].Count++
+			resp.ByHour[at.Hour()].Total += e.Amount
+		}
+		weekday := (int(at.Weekday()) + 6) % 7
+		resp.ByWeekday[weekday].Count++
+		resp.ByWeekday[weekday].Total += e.Amount
+
+		day := at.Format("2006-01-02")
+		if byDay[day] == nil {
+			byDay[day] = &dto.DaySpending{Date: day}
+		}
+		byDay[day].Count++
+		byDay[day].Total += e.Amount
+	}
+	resp.Total = roundMoney(resp.Total)
+	for i := range resp.ByWeekday {
+		resp.ByWeekday[i].Total = roundMoney(resp.ByWeekday[i].Total)
+	}
+	for i := range resp.ByHour {
+		resp.ByHour[i].Total = roundMoney(resp.ByHour[i].Total)
+	}
+
+	sort.SliceStable(spending, func(i, j int) bool {
+		if spending[i].Amount != spending[j].Amount {
+			return spending[i].Amount > spending[j].Amount
+		}
+		return spending[i].CreatedAt.Before(spending[j].CreatedAt)
+	})
+	for _, e := range spending {
+		if len(resp.TopExpenses) == top {
+			break
+		}
+		resp.TopExpenses = append(resp.TopExpenses, dto.TopExpense{
+			ID:         fmt.Sprintf("%v", e.ID),
+			TravelID:   fmt.Sprintf("%v", e.TravelID),
+			CategoryID: fmt.Sprintf("%v", e.CategoryID),
+			Category:   byCategory[e.CategoryID].name,
+			Amount:     e.Amount,
+			Date:       e.CreatedAt.Format("2006-01-02"),
+			Comment:    e.Description,
+		})
+	}
+
+	for _, c := range byCategory {
+		sort.Float64s(c.amounts)
+		var total float64
+		for _, a := range c.amounts {
+			total += a
+		}
+		resp.Categories = append(resp.Categories, dto.CategoryStatistics{
+			CategoryID: fmt.Sprintf("%v", c.id),
+			Category:   c.name,
+			Count:      len(c.amounts),
+			Total:      roundMoney(total),
+			Mean:       roundMoney(total / float64(len(c.amounts))),
+			Median:     roundMoney(percentile(c.amounts, 0.5)),
+			P90:        roundMoney(percentile(c.amounts, 0.9)),
+		})
+	}
+	sort.Slice(resp.Categories, func(i, j int) bool {
+		if resp.Categories[i].Total != resp.Categories[j].Total {
+			return resp.Categories[i].Total > resp.Categories[j].Total
+		}
+		return resp.Categories[i].Category < resp.Categories[j].Category
+	})
+
+	for _, d := range byDay {
+		if resp.MostExpensiveDay == nil || d.Total > resp.MostExpensiveDay.Total ||
+			(d.Total == resp.MostExpensiveDay.Total && d.Date < resp.MostExpensiveDay.Date) {
+			resp.MostExpensiveDay = d
+		}
+	}
+	if resp.MostExpensiveDay != nil {
+		resp.MostExpensiveDay.Total = roundMoney(resp.MostExpensiveDay.Total)
+	}
+
+	today := dateOf(now.In(location))
+	tracked := make(map[string]bool)
+	for _, t := range travels {
+		end := dateOf(t.EndDate)
+		if end.After(today) {
+			end = today
+		}
+		for d := dateOf(t.StartDate); !d.After(end); d = d.AddDate(0, 0, 1) {
+			tracked[d.Format("2006-01-02")] = true
+		}
+	}
+	resp.TrackedDays = len(tracked)
+	for day := range tracked {
+		if byDay[day] == nil {
+			resp.ExpenseFreeDays++
+		}
+	}
+	return resp, nil
+}
+
+// percentile — перцентиль отсортированной выборки с линейной интерполяцией между соседними значениями
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
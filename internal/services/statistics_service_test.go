@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatisticsService_Statistics(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockTravelRepo := mocks.NewMockTravelRepositoryInterface(ctrl)
+	service := NewStatisticsService(mockRepo, mockTravelRepo)
+	ctx := context.Background()
+
+	// 2024-05-06 — понедельник
+	start := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	travels := []models.Travel{
+		{ID: 1, UserID: 1, StartDate: start, EndDate: start.AddDate(0, 0, 9)},
+		{ID: 2, UserID: 1, StartDate: start.AddDate(1, 0, 0), EndDate: start.AddDate(1, 0, 3)},
+	}
+	food := models.Category{ID: 2, Name: "Питание", Key: "food"}
+	transport := models.Category{ID: 3, Name: "Транспорт", Key: "transport"}
+	expenses := []models.Expense{
+		{ID: 1, TravelID: 1, CategoryID: 2, Category: food, Amount: 10, Kind: models.KindExpense, CreatedAt: start},
+		{ID: 2, TravelID: 1, CategoryID: 2, Category: food, Amount: 20, Kind: models.KindExpense, CreatedAt: start},
+		{ID: 3, TravelID: 1, CategoryID: 2, Category: food, Amount: 30, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, 1)},
+		{ID: 4, TravelID: 1, CategoryID: 2, Category: food, Amount: 100, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, 2)},
+		// 22:30 UTC — в Москве уже 01:30 следующего дня
+		{ID: 5, TravelID: 1, CategoryID: 3, Category: transport, Amount: 50, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, 1).Add(22*time.Hour + 30*time.Minute), HasTime: true},
+		{ID: 6, TravelID: 1, CategoryID: 3, Category: transport, Amount: 500, Kind: models.KindRefund, CreatedAt: start},
+	}
+	now := start.AddDate(0, 0, 4).Add(12 * time.Hour)
+
+	t.Run("travel", func(t *testing.T) {
+		travelID := uint(1)
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), travelID).Return(expenses, nil)
+
+		resp, err := service.Statistics(ctx, 1, &travelID, 2, "Europe/Moscow", now, i18n.EN)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", resp.TravelID)
+		assert.Equal(t, 5, resp.Expenses) // возврат не учитывается
+		assert.Equal(t, 210.0, resp.Total)
+
+		assert.Len(t, resp.TopExpenses, 2)
+		assert.Equal(t, "4", resp.TopExpenses[0].ID)
+		assert.Equal(t, "5", resp.TopExpenses[1].ID)
+
+		foodStats := resp.Categories[0]
+		assert.Equal(t, "Food", foodStats.Category)
+		assert.Equal(t, 4, foodStats.Count)
+		assert.Equal(t, 40.0, foodStats.Mean)
+		assert.Equal(t, 25.0, foodStats.Median)
+		assert.Equal(t, 79.0, foodStats.P90)
+
+		assert.Equal(t, "monday", resp.ByWeekday[0].Weekday)
+		assert.Equal(t, 30.0, resp.ByWeekday[0].Total)
+		assert.Equal(t, 30.0, resp.ByWeekday[1].Total)
+		assert.Equal(t, 150.0, resp.ByWeekday[2].Total) // 100 за среду и такси после полуночи по Москве
+		assert.Equal(t, 1, resp.TimedExpenses)
+		assert.Equal(t, 50.0, resp.ByHour[1].Total)
+
+		assert.Equal(t, "2024-05-08", resp.MostExpensiveDay.Date)
+		assert.Equal(t, 150.0, resp.MostExpensiveDay.Total)
+		assert.Equal(t, 5, resp.TrackedDays)
+		assert.Equal(t, 2, resp.ExpenseFreeDays)
+	})
+
+	t.Run("all travels", func(t *testing.T) {
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockRepo.EXPECT().GetExpensesByUserTimeAndCategory(ctx, repository.ExpenseFilter{UserID: 1}).Return(expenses, nil)
+
+		resp, err := service.Statistics(ctx, 1, nil, 0, "", now, i18n.RU)
+		assert.NoError(t, err)
+		assert.Equal(t, "", resp.TravelID)
+		assert.Equal(t, "UTC", resp.Timezone)
+		assert.Len(t, resp.TopExpenses, 5)
+		assert.Equal(t, 5, resp.TrackedDays) // будущая поездка ещё не началась
+	})
+
+	t.Run("purchase at midnight UTC", func(t *testing.T) {
+		// покупка ровно в 00:00 UTC вторника — в Нью-Йорке это 20:00 понедельника;
+		// запись без времени в ту же полночь остаётся на своей дате
+		travelID := uint(1)
+		midnight := []models.Expense{
+			{ID: 1, TravelID: 1, CategoryID: 2, Category: food, Amount: 40, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, 1), HasTime: true},
+			{ID: 2, TravelID: 1, CategoryID: 2, Category: food, Amount: 15, Kind: models.KindExpense, CreatedAt: start.AddDate(0, 0, 1)},
+		}
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+		mockRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), travelID).Return(midnight, nil)
+
+		resp, err := service.Statistics(ctx, 1, &travelID, 0, "America/New_York", now, i18n.EN)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, resp.TimedExpenses)
+		assert.Equal(t, 40.0, resp.ByHour[20].Total)
+		assert.Equal(t, 40.0, resp.ByWeekday[0].Total)
+		assert.Equal(t, 15.0, resp.ByWeekday[1].Total)
+		assert.Equal(t, "2024-05-06", resp.MostExpensiveDay.Date)
+	})
+
+	t.Run("unknown travel", func(t *testing.T) {
+		travelID := uint(42)
+		mockTravelRepo.EXPECT().GetTravelsByUserID(ctx, uint(1)).Return(travels, nil)
+
+		_, err := service.Statistics(ctx, 1, &travelID, 0, "", now, i18n.RU)
+		assert.ErrorIs(t, err, ErrTravelNotFound)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		_, err := service.Statistics(ctx, 1, nil, MaxTopExpenses+1, "", now, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidTop)
+
+		_, err = service.Statistics(ctx, 1, nil, 0, "Mars/Olympus", now, i18n.RU)
+		assert.ErrorIs(t, err, ErrInvalidTimezone)
+	})
+}
@@ -2,11 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"time"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/repository"
 )
 
+var (
+	ErrInvalidBudget  = errors.New("budget must not be negative")
+	ErrInvalidCountry = errors.New("country must be a two-letter ISO code")
+)
+
 type TravelService struct {
 	repo repository.TravelRepositoryInterface
 }
@@ -17,11 +24,50 @@ func NewTravelService(travelRepo repository.TravelRepositoryInterface) *TravelSe
 	}
 }
 
-func (s *TravelService) CreateTravel(ctx context.Context, userID uint, title string, start, end time.Time) (*models.Travel, error) {
-	travel := &models.Travel{UserID: userID, Title: title, StartDate: start, EndDate: end}
+// CreateTravel создаёт поездку; participants < 1 означает одного путешественника,
+// budget = nil — поездку без бюджета, пустая country — страна неизвестна
+func (s *TravelService) CreateTravel(ctx context.Context, userID uint, title string, start, end time.Time, participants int, budget *float64, country string) (*models.Travel, error) {
+	if participants < 1 {
+		participants = 1
+	}
+	if budget != nil && *budget < 0 {
+		return nil, ErrInvalidBudget
+	}
+	country = strings.ToUpper(strings.TrimSpace(country))
+	if !validCountry(country) {
+		return nil, ErrInvalidCountry
+	}
+	travel := &models.Travel{UserID: userID, Title: title, StartDate: start, EndDate: end, Participants: participants, Budget: budget, Country: country}
 	return travel, s.repo.CreateTravel(ctx, travel)
 }
 
 func (s *TravelService) GetTravelByID(ctx context.Context, travelID uint) (*models.Travel, error) {
 	return s.repo.GetTravelByID(ctx, travelID)
 }
+
+// SetBudget задаёт бюджет поездки; nil снимает бюджет
+func (s *TravelService) SetBudget(ctx context.Context, travel *models.Travel, budget *float64) error {
+	if budget != nil && *budget < 0 {
+		return ErrInvalidBudget
+	}
+	if err := s.repo.UpdateBudget(ctx, travel.ID, budget); err != nil {
+		return err
+	}
+	travel.Budget = budget
+	return nil
+}
+
+func validCountry(country string) bool {
+	if country == "" {
+		return true
+	}
+	if len(country) != 2 {
+		return false
+	}
+	for _, r := range country {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
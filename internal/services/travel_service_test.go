@@ -28,7 +28,7 @@ func TestTravelService_CreateTravel_Success(t *testing.T) {
 		travel.ID = 1 // Имитируем автоинкремент
 	})
 
-	result, err := service.CreateTravel(ctx, userID, title, startDate, endDate)
+	result, err := service.CreateTravel(ctx, userID, title, startDate, endDate, 0, nil, "")
 
 	assert.NoError(t, err)
 	assert.NotNil(t, result)
@@ -36,6 +36,7 @@ func TestTravelService_CreateTravel_Success(t *testing.T) {
 	assert.Equal(t, title, result.Title)
 	assert.Equal(t, startDate, result.StartDate)
 	assert.Equal(t, endDate, result.EndDate)
+	assert.Equal(t, 1, result.Participants)
 }
 
 func TestTravelService_CreateTravel_RepositoryError(t *testing.T) {
@@ -49,7 +50,7 @@ func TestTravelService_CreateTravel_RepositoryError(t *testing.T) {
 	expectedError := errors.New("database error")
 	mockRepo.EXPECT().CreateTravel(ctx, gomock.Any()).Return(expectedError)
 
-	result, err := service.CreateTravel(ctx, 1, "Test", time.Now(), time.Now().Add(24*time.Hour))
+	result, err := service.CreateTravel(ctx, 1, "Test", time.Now(), time.Now().Add(24*time.Hour), 2, nil, "")
 
 	assert.Error(t, err)
 	assert.Equal(t, expectedError, err)
@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+	"time"
+	"wanderwallet/internal/charts"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/pdf"
+)
+
+// Вёрстка отчёта в пунктах
+const (
+	reportMargin   = 40.0
+	reportBottom   = pdf.PageHeight - 50
+	reportWidth    = pdf.PageWidth - 2*reportMargin
+	reportRight    = pdf.PageWidth - reportMargin
+	reportText     = 9.0
+	reportRow      = 13.0
+	reportHeading  = 13.0
+	reportChartGap = 15.0
+)
+
+var (
+	reportInk    = color.RGBA{0x22, 0x22, 0x22, 255}
+	reportMuted  = color.RGBA{0x77, 0x77, 0x77, 255}
+	reportRule   = color.RGBA{0xcc, 0xcc, 0xcc, 255}
+	reportShaded = color.RGBA{0xf2, 0xf2, 0xf2, 255}
+)
+
+// generate собирает PDF: шапку поездки, итоги, таблицу категорий, графики
+// и список записей по дням с комментариями
+func (s *TripReportService) generate(ctx context.Context, travel *models.Travel, expenses []models.Expense, now time.Time, loc i18n.Locale) ([]byte, error) {
+	data, err := s.analytics.Aggregate(ctx, travel.UserID, travel.ID, time.Time{}, time.Time{}, reportGranularity(travel), "", loc)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &reportWriter{doc: pdf.New(travel.Title, now), loc: loc}
+	w.newPage()
+	w.header(travel, now)
+	w.summary(travel, data.Total)
+	w.categories(data)
+	w.charts(data, travel.Budget)
+	w.expenses(expenses)
+	w.footers(travel.Title)
+	return w.doc.Bytes()
+}
+
+// reportGranularity — шаг графика нарастающего итога по длительности поездки
+func reportGranularity(travel *models.Travel) string {
+	days := dateOf(travel.EndDate).Sub(dateOf(travel.StartDate)).Hours() / 24
+	switch {
+	case days > 366:
+		return GranularityMonth
+	case days > 62:
+		return GranularityWeek
+	}
+	return GranularityDay
+}
+
+type reportWriter struct {
+	doc  *pdf.Document
+	page *pdf.Page
+	y    float64 // базовая линия следующей строки
+	loc  i18n.Locale
+}
+
+func (w *reportWriter) t(message string) string {
+	return i18n.T(w.loc, message)
+}
+
+func (w *reportWriter) newPage() {
+	w.page = w.doc.AddPage()
+	w.y = reportMargin + 12
+}
+
+// ensure начинает новую страницу, если height не помещается на текущей
+func (w *reportWriter) ensure(height float64) {
+	if w.y+height > reportBottom {
+		w.newPage()
+	}
+}
+
+func (w *reportWriter) heading(text string) {
+	w.ensure(reportHeading + 3*reportRow)
+	w.y += 10
+	w.page.Text(reportMargin, w.y, text, reportHeading, reportInk, true)
+	w.y += 5
+	w.page.Line(reportMargin, w.y, reportRight, w.y, 0.8, reportInk)
+	w.y += reportRow + 2
+}
+
+func (w *reportWriter) header(travel *models.Travel, now time.Time) {
+	for _, line := range pdf.Wrap(travel.Title, reportWidth, 20, true) {
+		w.page.Text(reportMargin, w.y+8, line, 20, reportInk, true)
+		w.y += 24
+	}
+	period := travel.StartDate.Format("2006-01-02") + " — " + travel.EndDate.Format("2006-01-02")
+	if travel.Country != "" {
+		period += " · " + travel.Country
+	}
+	w.page.Text(reportMargin, w.y+4, period, 11, reportInk, false)
+	w.y += 18
+	generated := fmt.Sprintf(w.t("Generated on %s"), now.UTC().Format("2006-01-02 15:04")+" UTC")
+	w.page.Text(reportMargin, w.y, generated, 8, reportMuted, false)
+	w.y += reportRow
+}
+
+func (w *reportWriter) summary(travel *models.Travel, total dto.AmountSummary) {
+	w.heading(w.t("Summary"))
+
+	days := int(dateOf(travel.EndDate).Sub(dateOf(travel.StartDate)).Hours()/24) + 1
+	if days < 1 {
+		days = 1
+	}
+	participants := travel.Participants
+	if participants < 1 {
+		participants = 1
+	}
+	rows := [][2]string{
+		{w.t("Spent"), formatMoney(total.Gross)},
+		{w.t("Refunds"), formatMoney(total.Refunds)},
+		{w.t("Net spending"), formatMoney(total.Net)},
+		{w.t("Income"), formatMoney(total.Income)},
+		{w.t("Allowances"), formatMoney(total.Allowances)},
+	}
+	if travel.Budget != nil {
+		rows = append(rows,
+			[2]string{w.t("Budget"), formatMoney(*travel.Budget)},
+			[2]string{w.t("Budget left"), formatMoney(*travel.Budget - total.Net)},
+		)
+	}
+	rows = append(rows,
+		[2]string{w.t("Days"), fmt.Sprintf("%d", days)},
+		[2]string{w.t("Per day"), formatMoney(total.Net / float64(days))},
+	)
+	if participants > 1 {
+		rows = append(rows,
+			[2]string{w.t("Participants"), fmt.Sprintf("%d", participants)},
+			[2]string{w.t("Per person per day"), formatMoney(total.Net / float64(days*participants))},
+		)
+	}
+
+	// Две колонки «название — значение»
+	half := (len(rows) + 1) / 2
+	columnWidth := reportWidth / 2
+	for i, row := range rows {
+		x := reportMargin
+		y := w.y + float64(i)*reportRow
+		if i >= half {
+			x += columnWidth + 10
+			y = w.y + float64(i-half)*reportRow
+		}
+		w.page.Text(x, y, row[0], reportText+1, reportMuted, false)
+		w.page.TextRight(x+columnWidth-20, y, row[1], reportText+1, reportInk, true)
+	}
+	w.y += float64(half)*reportRow + 4
+}
+
+func (w *reportWriter) categories(data *dto.AnalyticsResponse) {
+	w.heading(w.t("Spending by category"))
+
+	columns := []float64{reportRight - 180, reportRight - 120, reportRight - 50, reportRight}
+	row := func(name string, values []string, bold bool, shaded bool) {
+		w.ensure(reportRow)
+		if shaded {
+			w.page.Rect(reportMargin, w.y-reportRow+3.5, reportWidth, reportRow, reportShaded)
+		}
+		w.page.Text(reportMargin+2, w.y, pdf.Fit(name, columns[0]-reportMargin-80, reportText, bold), reportText, reportInk, bold)
+		for i, v := range values {
+			w.page.TextRight(columns[i]-2, w.y, v, reportText, reportInk, bold)
+		}
+		w.y += reportRow
+	}
+	row(w.t("Category"), []string{w.t("Spent"), w.t("Refunds"), w.t("Net"), w.t("Share")}, true, true)
+
+	var walk func(nodes []dto.CategoryNode, depth int)
+	walk = func(nodes []dto.CategoryNode, depth int) {
+		for _, node := range nodes {
+			if node.Total.Gross == 0 && node.Total.Refunds == 0 {
+				continue
+			}
+			name := node.Name
+			if name == "" {
+				name = w.t("Other")
+			}
+			row(strings.Repeat("    ", depth)+name, []string{
+				formatMoney(node.Total.Gross),
+				formatMoney(node.Total.Refunds),
+				formatMoney(node.Total.Net),
+				formatShare(node.Total.Net, data.Total.Net),
+			}, depth == 0, false)
+			walk(node.Children, depth+1)
+		}
+	}
+	walk(data.CategoryTree, 0)
+
+	w.page.Line(reportMargin, w.y-reportRow+5, reportRight, w.y-reportRow+5, 0.5, reportRule)
+	w.y += 2
+	row(w.t("Total"), []string{
+		formatMoney(data.Total.Gross),
+		formatMoney(data.Total.Refunds),
+		formatMoney(data.Total.Net),
+		formatShare(data.Total.Net, data.Total.Net),
+	}, true, false)
+}
+
+// charts выводит рядом круговую диаграмму по категориям и нарастающий итог с бюджетом
+func (w *reportWriter) charts(data *dto.AnalyticsResponse, budget *float64) {
+	theme, _ := charts.ThemeByName("light")
+	opts := charts.Options{Width: 400, Height: 300, Theme: theme}
+	width := (reportWidth - reportChartGap) / 2
+	height := width * float64(opts.Height) / float64(opts.Width)
+
+	w.y += 6
+	w.ensure(height)
+	top := w.y - reportRow + 3
+	w.page.Chart(buildChart(ChartCategories, data, opts, "donut", budget, w.loc), reportMargin, top, width)
+	w.page.Chart(buildChart(ChartCumulative, data, opts, "", budget, w.loc), reportMargin+width+reportChartGap, top, width)
+	w.y = top + height + reportRow
+}
+
+// expenses — записи по дням: время, категория, комментарий и сумма; возвраты со знаком минус
+func (w *reportWriter) expenses(expenses []models.Expense) {
+	w.heading(w.t("Expenses by day"))
+	if len(expenses) == 0 {
+		w.page.Text(reportMargin, w.y, w.t("No expenses"), reportText, reportMuted, false)
+		w.y += reportRow
+		return
+	}
+
+	const (
+		timeX     = reportMargin
+		categoryX = reportMargin + 32
+		commentX  = reportMargin + 170
+		amountX   = reportRight
+	)
+	commentWidth := amountX - 70 - commentX
+
+	for start := 0; start < len(expenses); {
+		day := dateOf(expenses[start].CreatedAt)
+		end := start
+		var dayTotal float64
+		for end < len(expenses) && dateOf(expenses[end].CreatedAt).Equal(day) {
+			dayTotal += netAmount(expenses[end])
+			end++
+		}
+
+		// Заголовок дня не остаётся внизу страницы без первой записи
+		w.ensure(3 * reportRow)
+		w.page.Rect(reportMargin, w.y-reportRow+3.5, reportWidth, reportRow, reportShaded)
+		w.page.Text(reportMargin+2, w.y, day.Format("2006-01-02"), reportText, reportInk, true)
+		w.page.TextRight(amountX-2, w.y, formatMoney(dayTotal), reportText, reportInk, true)
+		w.y += reportRow
+
+		for _, e := range expenses[start:end] {
+			comment := pdf.Wrap(e.Description, commentWidth, reportText, false)
+			if len(comment) == 0 {
+				comment = []string{""}
+			}
+			w.ensure(float64(len(comment)) * reportRow)
+			if e.HasTime {
+				w.page.Text(timeX+2, w.y, e.CreatedAt.UTC().Format("15:04"), reportText, reportMuted, false)
+			}
+			category := i18n.CategoryName(w.loc, e.Category.Key, e.Category.Name)
+			if e.Kind != models.KindExpense {
+				category += " (" + w.t(kindLabels[e.Kind]) + ")"
+			}
+			w.page.Text(categoryX, w.y, pdf.Fit(category, commentX-categoryX-6, reportText, false), reportText, reportInk, false)
+			w.page.TextRight(amountX-2, w.y, formatMoney(signedAmount(e)), reportText, reportInk, false)
+			for i, line := range comment {
+				if i > 0 {
+					w.ensure(reportRow)
+				}
+				w.page.Text(commentX, w.y, line, reportText, reportInk, false)
+				w.y += reportRow
+			}
+		}
+		start = end
+	}
+}
+
+// footers подписывает страницы названием поездки и номером
+func (w *reportWriter) footers(title string) {
+	pages := w.doc.Pages()
+	y := pdf.PageHeight - 28
+	for i, page := range pages {
+		page.Line(reportMargin, y-10, reportRight, y-10, 0.5, reportRule)
+		page.Text(reportMargin, y, pdf.Fit(title, reportWidth-100, 8, false), 8, reportMuted, false)
+		page.TextRight(reportRight, y, fmt.Sprintf(w.t("Page %d of %d"), i+1, len(pages)), 8, reportMuted, false)
+	}
+}
+
+var kindLabels = map[models.ExpenseKind]string{
+	models.KindRefund:        "refund",
+	models.KindReimbursement: "reimbursement",
+	models.KindIncome:        "income",
+	models.KindPerDiem:       "per diem",
+	models.KindMileage:       "mileage",
+}
+
+// signedAmount — сумма записи в списке: возвраты уменьшают траты и выводятся со знаком минус
+func signedAmount(e models.Expense) float64 {
+	if e.Kind.IsRefund() {
+		return -e.Amount
+	}
+	return e.Amount
+}
+
+// formatMoney — сумма с двумя знаками и разделением разрядов пробелом
+func formatMoney(v float64) string {
+	s := fmt.Sprintf("%.2f", math.Abs(roundMoney(v)))
+	whole, fraction := s[:len(s)-3], s[len(s)-3:]
+	var b strings.Builder
+	if roundMoney(v) < 0 {
+		b.WriteByte('-')
+	}
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(digit)
+	}
+	return b.String() + fraction
+}
+
+func formatShare(part, total float64) string {
+	if total == 0 {
+		return "—"
+	}
+	return fmt.Sprintf("%.1f%%", part/total*100)
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
+)
+
+const (
+	// TripReportSyncLimit — отчёт по поездке с большим числом записей строится в фоне
+	TripReportSyncLimit = 300
+	// tripReportWorkers — сколько отчётов строится одновременно; остальные ждут в очереди
+	tripReportWorkers = 2
+	tripReportTimeout = 5 * time.Minute
+	// tripReportStale — незавершённое за это время задание считается прерванным
+	// (например, перезапуском сервера)
+	tripReportStale = time.Hour
+	// tripReportTTL — сколько хранятся задания и готовые файлы
+	tripReportTTL = 24 * time.Hour
+)
+
+var (
+	ErrTripReportNotReady = errors.New("report is not ready yet")
+	ErrTripReportFailed   = errors.New("report generation failed")
+)
+
+type TripReportService struct {
+	repo        repository.TripReportRepositoryInterface
+	expenseRepo repository.ExpenseRepositoryInterface
+	analytics   AnalyticsServiceInterfase
+
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+func NewTripReportService(repo repository.TripReportRepositoryInterface, expenseRepo repository.ExpenseRepositoryInterface, analytics AnalyticsServiceInterfase) *TripReportService {
+	return &TripReportService{
+		repo:        repo,
+		expenseRepo: expenseRepo,
+		analytics:   analytics,
+		slots:       make(chan struct{}, tripReportWorkers),
+	}
+}
+
+// Report строит PDF-отчёт по поездке. Если записей больше TripReportSyncLimit или
+// запрошено async, создаётся фоновое задание и возвращается оно вместо файла.
+func (s *TripReportService) Report(ctx context.Context, travel *models.Travel, async bool, now time.Time, loc i18n.Locale) ([]byte, *models.TripReportJob, error) {
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, travel.UserID, travel.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !async && len(expenses) <= TripReportSyncLimit {
+		file, err := s.generate(ctx, travel, expenses, now, loc)
+		return file, nil, err
+	}
+
+	if err := s.repo.DeleteJobsBefore(ctx, now.Add(-tripReportTTL)); err != nil {
+		return nil, nil, err
+	}
+	job := &models.TripReportJob{
+		UserID:   travel.UserID,
+		TravelID: travel.ID,
+		Status:   models.TripReportPending,
+		Locale:   string(loc),
+	}
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, nil, err
+	}
+
+	s.wg.Add(1)
+	go s.run(*job, *travel, now, loc)
+	return nil, job, nil
+}
+
+// run строит отчёт задания в фоне. Контекст запроса к этому моменту уже завершён,
+// поэтому у задания свой контекст с ограничением по времени.
+func (s *TripReportService) run(job models.TripReportJob, travel models.Travel, now time.Time, loc i18n.Locale) {
+	defer s.wg.Done()
+	s.slots <- struct{}{}
+	defer func() { <-s.slots }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), tripReportTimeout)
+	defer cancel()
+
+	job.Status = models.TripReportRunning
+	if err := s.repo.UpdateJob(ctx, &job); err != nil {
+		log.Printf("Failed to start trip report job %d: %v\n", job.ID, err)
+		return
+	}
+
+	file, err := s.buildReport(ctx, &travel, now, loc)
+	finished := time.Now()
+	job.FinishedAt = &finished
+	if err != nil {
+		log.Printf("Failed to build trip report for travel %d: %v\n", travel.ID, err)
+		job.Status, job.Error = models.TripReportFailed, ErrTripReportFailed.Error()
+	} else {
+		job.Status, job.File = models.TripReportDone, file
+	}
+	if err := s.repo.UpdateJob(context.Background(), &job); err != nil {
+		log.Printf("Failed to save trip report job %d: %v\n", job.ID, err)
+	}
+}
+
+func (s *TripReportService) buildReport(ctx context.Context, travel *models.Travel, now time.Time, loc i18n.Locale) ([]byte, error) {
+	expenses, err := s.expenseRepo.GetExpensesByTravelID(ctx, travel.UserID, travel.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.generate(ctx, travel, expenses, now, loc)
+}
+
+// Wait дожидается фоновых заданий, уже принятых в работу; вызывается при остановке
+// сервера, чтобы отчёты не обрывались на середине
+func (s *TripReportService) Wait() {
+	s.wg.Wait()
+}
+
+// GetJob возвращает задание без файла. Задание, не завершённое за tripReportStale,
+// отдаётся как неудавшееся.
+func (s *TripReportService) GetJob(ctx context.Context, jobID uint, now time.Time) (*models.TripReportJob, error) {
+	job, err := s.repo.GetJobByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	unfinished := job.Status == models.TripReportPending || job.Status == models.TripReportRunning
+	if unfinished && now.Sub(job.CreatedAt) > tripReportStale {
+		job.Status, job.Error = models.TripReportFailed, ErrTripReportFailed.Error()
+	}
+	return job, nil
+}
+
+// JobFile возвращает готовый PDF задания
+func (s *TripReportService) JobFile(ctx context.Context, job *models.TripReportJob) ([]byte, error) {
+	switch job.Status {
+	case models.TripReportDone:
+		return s.repo.GetJobFile(ctx, job.ID)
+	case models.TripReportFailed:
+		return nil, ErrTripReportFailed
+	}
+	return nil, ErrTripReportNotReady
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mocks"
+	"wanderwallet/internal/models"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTripReportService_Report(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTripReportRepositoryInterface(ctrl)
+	mockExpenseRepo := mocks.NewMockExpenseRepositoryInterface(ctrl)
+	mockAnalytics := mocks.NewMockAnalyticsServiceInterfase(ctrl)
+	service := NewTripReportService(mockRepo, mockExpenseRepo, mockAnalytics)
+	ctx := context.Background()
+
+	budget := 1000.0
+	travel := &models.Travel{
+		ID: 7, UserID: 1, Title: "Казань", Participants: 2, Budget: &budget,
+		StartDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 5, 3, 0, 0, 0, 0, time.UTC),
+	}
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	food := models.Category{ID: 3, Name: "Еда"}
+	expenses := []models.Expense{
+		{ID: 1, Amount: 250, Kind: models.KindExpense, Category: food, Description: "Ужин в ресторане у набережной",
+			CreatedAt: time.Date(2024, 5, 1, 19, 30, 0, 0, time.UTC)},
+		{ID: 2, Amount: 50, Kind: models.KindRefund, Category: food, CreatedAt: time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC)},
+	}
+	data := &dto.AnalyticsResponse{
+		Total:        dto.AmountSummary{Gross: 250, Refunds: 50, Net: 200},
+		Granularity:  GranularityDay,
+		CategoryTree: []dto.CategoryNode{{ID: "3", Name: "Еда", Total: dto.AmountSummary{Gross: 250, Refunds: 50, Net: 200}}},
+		Series: []dto.TimeBucket{
+			{Start: "2024-05-01", End: "2024-05-01", AmountSummary: dto.AmountSummary{Net: 250}},
+			{Start: "2024-05-02", End: "2024-05-02", AmountSummary: dto.AmountSummary{Net: -50}},
+		},
+	}
+
+	t.Run("small trip is rendered synchronously", func(t *testing.T) {
+		mockExpenseRepo.EXPECT().GetExpensesByTravelID(ctx, uint(1), uint(7)).Return(expenses, nil)
+		mockAnalytics.EXPECT().Aggregate(ctx, uint(1), uint(7), time.Time{}, time.Time{}, GranularityDay, "", i18n.RU).Return(data, nil)
+
+		file, job, err := service.Report(ctx, travel, false, now, i18n.RU)
+		assert.NoError(t, err)
+		assert.Nil(t, job)
+		assert.True(t, bytes.HasPrefix(file, []byte("%PDF-1.4")))
+		assert.True(t, bytes.HasSuffix(file, []byte("%%EOF\n")))
+	})
+
+	t.Run("large trip is queued", func(t *testing.T) {
+		many := make([]models.Expense, TripReportSyncLimit+1)
+		for i := range many {
+			many[i] = models.Expense{ID: uint(i + 1), Amount: 10, Kind: models.KindExpense, Category: food, CreatedAt: travel.StartDate}
+		}
+		mockExpenseRepo.EXPECT().GetExpensesByTravelID(gomock.Any(), uint(1), uint(7)).Return(many, nil).Times(2)
+		mockAnalytics.EXPECT().Aggregate(gomock.Any(), uint(1), uint(7), time.Time{}, time.Time{}, GranularityDay, "", i18n.EN).Return(data, nil)
+		mockRepo.EXPECT().DeleteJobsBefore(ctx, now.Add(-tripReportTTL)).Return(nil)
+		mockRepo.EXPECT().CreateJob(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, job *models.TripReportJob) error {
+			job.ID = 5
+			return nil
+		})
+		var statuses []models.TripReportStatus
+		var saved models.TripReportJob
+		mockRepo.EXPECT().UpdateJob(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, job *models.TripReportJob) error {
+			statuses = append(statuses, job.Status)
+			saved = *job
+			return nil
+		}).Times(2)
+
+		file, job, err := service.Report(ctx, travel, false, now, i18n.EN)
+		assert.NoError(t, err)
+		assert.Nil(t, file)
+		assert.Equal(t, uint(5), job.ID)
+		assert.Equal(t, models.TripReportPending, job.Status)
+		assert.Equal(t, "en", job.Locale)
+
+		service.wg.Wait()
+		assert.Equal(t, []models.TripReportStatus{models.TripReportRunning, models.TripReportDone}, statuses)
+		assert.True(t, bytes.HasPrefix(saved.File, []byte("%PDF")))
+		assert.NotNil(t, saved.FinishedAt)
+	})
+}
+
+func TestTripReportService_Jobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockTripReportRepositoryInterface(ctrl)
+	service := NewTripReportService(mockRepo, nil, nil)
+	ctx := context.Background()
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("stale job is reported as failed", func(t *testing.T) {
+		job := &models.TripReportJob{ID: 1, Status: models.TripReportRunning}
+		job.CreatedAt = now.Add(-2 * tripReportStale)
+		mockRepo.EXPECT().GetJobByID(ctx, uint(1)).Return(job, nil)
+
+		got, err := service.GetJob(ctx, 1, now)
+		assert.NoError(t, err)
+		assert.Equal(t, models.TripReportFailed, got.Status)
+	})
+
+	t.Run("file", func(t *testing.T) {
+		_, err := service.JobFile(ctx, &models.TripReportJob{ID: 2, Status: models.TripReportPending})
+		assert.ErrorIs(t, err, ErrTripReportNotReady)
+
+		_, err = service.JobFile(ctx, &models.TripReportJob{ID: 2, Status: models.TripReportFailed})
+		assert.ErrorIs(t, err, ErrTripReportFailed)
+
+		mockRepo.EXPECT().GetJobFile(ctx, uint(2)).Return([]byte("%PDF"), nil)
+		file, err := service.JobFile(ctx, &models.TripReportJob{ID: 2, Status: models.TripReportDone})
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("%PDF"), file)
+	})
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "0.00", formatMoney(0))
+	assert.Equal(t, "999.50", formatMoney(999.5))
+	assert.Equal(t, "1 234 567.89", formatMoney(1234567.891))
+	assert.Equal(t, "-1 000.00", formatMoney(-1000))
+}
@@ -2,10 +2,21 @@ package services
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
+	"fmt"
+	"log"
 	"os"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/i18n"
+	"wanderwallet/internal/mail"
 	"wanderwallet/internal/models"
 	"wanderwallet/internal/repository"
 
@@ -16,27 +27,56 @@ import (
 
 var (
 	ErrUserAlreadyExists = errors.New("user already exists")
+	ErrEmailTaken        = repository.ErrEmailTaken
 	ErrUserNotFound      = errors.New("user not found")
 	ErrInvalidPassword   = errors.New("invalid password")
 	ErrTokenGeneration   = errors.New("failed to generate token")
+	ErrInvalidLocale     = errors.New("unsupported locale")
+	// ErrInvalidRefreshToken — токен обновления не найден, истёк или его сессия отозвана
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused — предъявлен уже использованный токен обновления;
+	// вся сессия отзывается, так как токен мог быть украден
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	ErrSessionNotFound    = errors.New("session not found")
+	// ErrInvalidResetToken — код сброса пароля не найден, истёк или уже использован
+	ErrInvalidResetToken = errors.New("invalid or expired reset token")
+)
+
+const (
+	// AccessTokenTTL — срок жизни токена доступа
+	AccessTokenTTL = 15 * time.Minute
+	// RefreshTokenTTL — срок жизни токена обновления
+	RefreshTokenTTL = 30 * 24 * time.Hour
+	// PasswordResetTTL — сколько действует код сброса пароля из письма
+	PasswordResetTTL = time.Hour
+	// mailTimeout — сколько ждать почтовый сервер при отправке письма
+	mailTimeout = 30 * time.Second
 )
 
 type UserService struct {
-	userRepo repository.UserRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	sessionRepo repository.SessionRepositoryInterface
+	mailer      mail.Mailer
+	// resetURL — страница сброса пароля; пустая — в письме только код
+	resetURL string
+
+	// mails — письма, отправляемые в фоне
+	mails sync.WaitGroup
 }
 
-func NewUserService(userRepo repository.UserRepositoryInterface) *UserService {
+func NewUserService(userRepo repository.UserRepositoryInterface, sessionRepo repository.SessionRepositoryInterface, mailer mail.Mailer, resetURL string) *UserService {
 	return &UserService{
-		userRepo: userRepo,
+		userRepo:    userRepo,
+		sessionRepo: sessionRepo,
+		mailer:      mailer,
+		resetURL:    resetURL,
 	}
 }
 
-type RegisterResponse struct {
-	User  *models.User
-	Token string
-}
+// maxUserAgent — длиннее User-Agent обрезается до размера колонки
+const maxUserAgent = 512
 
-func (s *UserService) Register(ctx context.Context, login, password string) (*RegisterResponse, error) {
+func (s *UserService) Register(ctx context.Context, login, password string, device dto.Device) (*dto.RegisterResponse, error) {
 	exists, err := s.userRepo.IsLoginExists(ctx, login)
 	if err != nil {
 		return nil, err
@@ -56,18 +96,20 @@ func (s *UserService) Register(ctx context.Context, login, password string) (*Re
 	}
 
 	if err := s.userRepo.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, repository.ErrLoginTaken) {
+			return nil, ErrUserAlreadyExists
+		}
 		return nil, err
 	}
 
-	token, err := s.generateToken(user.ID)
-
+	tokens, err := s.startSession(ctx, user.ID, device)
 	if err != nil {
-		return nil, ErrTokenGeneration
+		return nil, err
 	}
 
-	return &RegisterResponse{
-		User:  user,
-		Token: token,
+	return &dto.RegisterResponse{
+		User:   user,
+		Tokens: *tokens,
 	}, nil
 }
 
@@ -79,25 +121,146 @@ func (s *UserService) hashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
-func (s *UserService) generateToken(userID uint) (string, error) {
+// generateToken подписывает токен доступа; sid связывает его с сессией, чтобы после
+// выхода токен перестал приниматься, не дожидаясь истечения
+func (s *UserService) generateToken(userID uint, sessionID uint) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": strconv.Itoa(int(userID)),
-		"exp": time.Now().Add(time.Hour * 24 * 30).Unix(),
+		"sid": strconv.Itoa(int(sessionID)),
+		"exp": time.Now().Add(AccessTokenTTL).Unix(),
 	})
 
 	return token.SignedString([]byte(os.Getenv("SECRET_KEY")))
 }
 
-func (s *UserService) comparePassword(hashedPassword, password string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+// randomToken возвращает случайный токен для токенов обновления и кодов сброса пароля
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// newRefreshToken возвращает случайный токен обновления и запись с его хешем
+func newRefreshToken(sessionID uint, now time.Time) (string, *models.RefreshToken, error) {
+	token, err := randomToken()
+	if err != nil {
+		return "", nil, err
+	}
+	return token, &models.RefreshToken{
+		SessionID: sessionID,
+		Hash:      hashToken(token),
+		ExpiresAt: now.Add(RefreshTokenTTL),
+	}, nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// startSession открывает новую сессию после входа и выдаёт её первую пару токенов
+func (s *UserService) startSession(ctx context.Context, userID uint, device dto.Device) (*dto.Tokens, error) {
+	now := time.Now()
+	if err := s.sessionRepo.DeleteExpiredTokens(ctx, now); err != nil {
+		return nil, err
+	}
+	refresh, record, err := newRefreshToken(0, now)
+	if err != nil {
+		return nil, ErrTokenGeneration
+	}
+	userAgent := device.UserAgent
+	if len(userAgent) > maxUserAgent {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgent], "")
+	}
+	session := &models.Session{UserID: userID, UserAgent: userAgent, IP: device.IP, LastSeenAt: &now}
+	if err := s.sessionRepo.CreateSession(ctx, session, record); err != nil {
+		return nil, err
+	}
+	access, err := s.generateToken(userID, session.ID)
+	if err != nil {
+		return nil, ErrTokenGeneration
+	}
+	return &dto.Tokens{Token: access, RefreshToken: refresh}, nil
+}
+
+// Refresh обменивает токен обновления на новую пару токенов той же сессии. Повторное
+// предъявление уже обменянного токена отзывает сессию целиком: им пользуется кто-то ещё.
+func (s *UserService) Refresh(ctx context.Context, refreshToken string) (*dto.Tokens, error) {
+	current, err := s.sessionRepo.GetRefreshToken(ctx, hashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, err
+	}
+	now := time.Now()
+	if current.Session.RevokedAt != nil || !now.Before(current.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if current.UsedAt != nil {
+		return nil, s.revokeReused(ctx, current.SessionID, now)
+	}
+
+	refresh, next, err := newRefreshToken(current.SessionID, now)
+	if err != nil {
+		return nil, ErrTokenGeneration
+	}
+	rotated, err := s.sessionRepo.RotateRefreshToken(ctx, current.ID, next, now)
+	if err != nil {
+		return nil, err
+	}
+	if !rotated {
+		return nil, s.revokeReused(ctx, current.SessionID, now)
+	}
+	access, err := s.generateToken(current.Session.UserID, current.SessionID)
+	if err != nil {
+		return nil, ErrTokenGeneration
+	}
+	return &dto.Tokens{Token: access, RefreshToken: refresh}, nil
+}
+
+func (s *UserService) revokeReused(ctx context.Context, sessionID uint, now time.Time) error {
+	log.Printf("Refresh token reuse detected, revoking session %d\n", sessionID)
+	if err := s.sessionRepo.RevokeSession(ctx, sessionID, now); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Logout отзывает сессию: её токены доступа и обновления больше не принимаются
+func (s *UserService) Logout(ctx context.Context, sessionID uint) error {
+	return s.sessionRepo.RevokeSession(ctx, sessionID, time.Now())
+}
+
+// GetSessions возвращает действующие сессии пользователя
+func (s *UserService) GetSessions(ctx context.Context, userID uint) ([]models.Session, error) {
+	return s.sessionRepo.GetActiveSessions(ctx, userID, time.Now())
 }
 
-type LoginResponse struct {
-	User  *models.User
-	Token string
+// RevokeSession завершает сессию пользователя на другом устройстве
+func (s *UserService) RevokeSession(ctx context.Context, userID uint, sessionID uint) error {
+	revoked, err := s.sessionRepo.RevokeUserSession(ctx, userID, sessionID, time.Now())
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// RevokeOtherSessions завершает все сессии пользователя, кроме текущей, и возвращает их число
+func (s *UserService) RevokeOtherSessions(ctx context.Context, userID uint, currentID uint) (int64, error) {
+	return s.sessionRepo.RevokeOtherSessions(ctx, userID, currentID, time.Now())
 }
 
-func (s *UserService) Login(ctx context.Context, login, password string) (*LoginResponse, error) {
+func (s *UserService) comparePassword(hashedPassword, password string) error {
+	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
+}
+
+func (s *UserService) Login(ctx context.Context, login, password string, device dto.Device) (*dto.LoginResponse, error) {
 	user, err := s.userRepo.GetByLogin(ctx, login)
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
@@ -110,14 +273,14 @@ func (s *UserService) Login(ctx context.Context, login, password string) (*Login
 		return nil, ErrInvalidPassword
 	}
 
-	token, err := s.generateToken(user.ID)
+	tokens, err := s.startSession(ctx, user.ID, device)
 	if err != nil {
-		return nil, ErrTokenGeneration
+		return nil, err
 	}
 
-	return &LoginResponse{
-			User:  user,
-			Token: token,
+	return &dto.LoginResponse{
+			User:   user,
+			Tokens: *tokens,
 		},
 		nil
 }
@@ -125,3 +288,136 @@ func (s *UserService) Login(ctx context.Context, login, password string) (*Login
 func (s *UserService) GetUserByID(ctx context.Context, id uint) (*models.User, error) {
 	return s.userRepo.GetByID(ctx, id)
 }
+
+// SetLocale сохраняет предпочитаемый язык пользователя.
+// Пустая строка сбрасывает выбор: язык снова определяется заголовком Accept-Language.
+func (s *UserService) SetLocale(ctx context.Context, userID uint, locale string) (string, error) {
+	if locale != "" {
+		loc, ok := i18n.Parse(locale)
+		if !ok {
+			return "", ErrInvalidLocale
+		}
+		locale = string(loc)
+	}
+	if err := s.userRepo.UpdateLocale(ctx, userID, locale); err != nil {
+		return "", err
+	}
+	return locale, nil
+}
+
+// SetEmail сохраняет адрес для сброса пароля; пустая строка удаляет его.
+// Адрес, уже указанный у другого пользователя, не сохраняется (ErrEmailTaken).
+func (s *UserService) SetEmail(ctx context.Context, userID uint, email string) (string, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if err := s.userRepo.UpdateEmail(ctx, userID, email); err != nil {
+		return "", err
+	}
+	return email, nil
+}
+
+// ChangePassword меняет пароль после проверки текущего. Все сессии, кроме текущей,
+// отзываются: если пароль утёк, вошедший по нему потеряет доступ.
+func (s *UserService) ChangePassword(ctx context.Context, user *models.User, sessionID uint, current, password string) error {
+	if err := s.comparePassword(user.Password, current); err != nil {
+		return ErrInvalidPassword
+	}
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	return s.userRepo.ChangePassword(ctx, user.ID, hash, sessionID, time.Now())
+}
+
+// RequestPasswordReset отправляет код сброса пароля на почту пользователя, найденного
+// по логину или адресу. Письмо уходит в фоне. Неизвестный пользователь, пользователь без
+// почты и ошибка отправки письма не сообщаются вызывающему, чтобы по ответу нельзя было
+// перебирать логины.
+func (s *UserService) RequestPasswordReset(ctx context.Context, loginOrEmail string) error {
+	user, err := s.userRepo.GetByLoginOrEmail(ctx, strings.TrimSpace(loginOrEmail))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+	if user.Email == "" {
+		return nil
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return ErrTokenGeneration
+	}
+	if err := s.userRepo.CreateResetToken(ctx, &models.PasswordResetToken{
+		UserID:    user.ID,
+		Hash:      hashToken(token),
+		ExpiresAt: time.Now().Add(PasswordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	s.mails.Add(1)
+	go s.sendMail(user.ID, s.resetMessage(user, token))
+	return nil
+}
+
+// sendMail отправляет письмо в фоне: время ответа на запрос сброса не должно зависеть
+// от того, есть ли у пользователя почта, и от скорости почтового сервера
+func (s *UserService) sendMail(userID uint, msg mail.Message) {
+	defer s.mails.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), mailTimeout)
+	defer cancel()
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		log.Printf("Failed to send mail to user %d: %v\n", userID, err)
+	}
+}
+
+// Wait дожидается отправки писем, начатых в фоне
+func (s *UserService) Wait() {
+	s.mails.Wait()
+}
+
+func (s *UserService) resetMessage(user *models.User, token string) mail.Message {
+	loc, ok := i18n.Parse(user.Locale)
+	if !ok {
+		loc = i18n.Default
+	}
+	code := token
+	if s.resetURL != "" {
+		code = s.resetURL + "?token=" + token
+	}
+	return mail.Message{
+		To:      user.Email,
+		Subject: i18n.T(loc, "WanderWallet password reset"),
+		Body: fmt.Sprintf(i18n.T(loc, "To reset the password of your account %s, use this code within an hour:"), user.Login) +
+			"\n\n" + code + "\n\n" +
+			i18n.T(loc, "If you did not request a password reset, ignore this email."),
+	}
+}
+
+// ResetPassword устанавливает новый пароль по коду из письма и отзывает все сессии
+func (s *UserService) ResetPassword(ctx context.Context, token, password string) error {
+	reset, err := s.userRepo.GetResetToken(ctx, hashToken(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		return err
+	}
+	now := time.Now()
+	if reset.UsedAt != nil || !now.Before(reset.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+	hash, err := s.hashPassword(password)
+	if err != nil {
+		return err
+	}
+	ok, err := s.userRepo.ResetPassword(ctx, reset, hash, now)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidResetToken
+	}
+	return nil
+}
@@ -4,9 +4,14 @@ import (
 	"context"
 	"errors"
 	"os"
+	"strings"
 	"testing"
+	"time"
+	"wanderwallet/internal/dto"
+	"wanderwallet/internal/mail"
 	"wanderwallet/internal/mocks"
 	"wanderwallet/internal/models"
+	"wanderwallet/internal/repository"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -20,13 +25,24 @@ func TestMain(m *testing.M) {
 	os.Exit(code)
 }
 
+// fakeMailer запоминает отправленные письма
+type fakeMailer struct {
+	sent []mail.Message
+}
+
+func (m *fakeMailer) Send(ctx context.Context, msg mail.Message) error {
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
 func TestUserService_Register_Success(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	login := "testuser"
 	password := "testpass123"
@@ -40,15 +56,18 @@ func TestUserService_Register_Success(t *testing.T) {
 		Do(func(ctx context.Context, user *models.User) {
 			user.ID = 1 // Имитируем автоинкремент ID
 		})
+	mockSessionRepo.EXPECT().DeleteExpiredTokens(ctx, gomock.Any()).Return(nil)
+	mockSessionRepo.EXPECT().CreateSession(ctx, gomock.Any(), gomock.Any()).Return(nil)
 
 	// Act
-	response, err := service.Register(ctx, login, password)
+	response, err := service.Register(ctx, login, password, dto.Device{})
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
 	assert.Equal(t, login, response.User.Login)
 	assert.NotEmpty(t, response.Token)
+	assert.NotEmpty(t, response.RefreshToken)
 	assert.NotEqual(t, password, response.User.Password) // Пароль должен быть захеширован
 }
 
@@ -58,7 +77,8 @@ func TestUserService_Register_UserAlreadyExists(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	login := "existinguser"
 	password := "testpass123"
@@ -68,7 +88,7 @@ func TestUserService_Register_UserAlreadyExists(t *testing.T) {
 	mockRepo.EXPECT().IsLoginExists(ctx, login).Return(true, nil)
 
 	// Act
-	response, err := service.Register(ctx, login, password)
+	response, err := service.Register(ctx, login, password, dto.Device{})
 
 	// Assert
 	assert.Error(t, err)
@@ -82,7 +102,8 @@ func TestUserService_Register_DatabaseError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	login := "testuser"
 	password := "testpass123"
@@ -93,7 +114,7 @@ func TestUserService_Register_DatabaseError(t *testing.T) {
 	mockRepo.EXPECT().IsLoginExists(ctx, login).Return(false, dbError)
 
 	// Act
-	response, err := service.Register(ctx, login, password)
+	response, err := service.Register(ctx, login, password, dto.Device{})
 
 	// Assert
 	assert.Error(t, err)
@@ -101,13 +122,58 @@ func TestUserService_Register_DatabaseError(t *testing.T) {
 	assert.Nil(t, response)
 }
 
+func TestUserService_Register_ConcurrentLogin(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
+	ctx := context.Background()
+
+	// логин заняли между проверкой и вставкой — срабатывает уникальный индекс
+	mockRepo.EXPECT().IsLoginExists(ctx, "testuser").Return(false, nil)
+	mockRepo.EXPECT().CreateUser(ctx, gomock.Any()).Return(repository.ErrLoginTaken)
+
+	response, err := service.Register(ctx, "testuser", "testpass123", dto.Device{})
+	assert.ErrorIs(t, err, ErrUserAlreadyExists)
+	assert.Nil(t, response)
+}
+
+func TestUserService_SetEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
+	ctx := context.Background()
+
+	t.Run("normalized", func(t *testing.T) {
+		mockRepo.EXPECT().UpdateEmail(ctx, uint(1), "anna@example.com").Return(nil)
+
+		email, err := service.SetEmail(ctx, 1, "  Anna@Example.com ")
+		assert.NoError(t, err)
+		assert.Equal(t, "anna@example.com", email)
+	})
+
+	t.Run("duplicate email", func(t *testing.T) {
+		mockRepo.EXPECT().UpdateEmail(ctx, uint(2), "anna@example.com").Return(repository.ErrEmailTaken)
+
+		email, err := service.SetEmail(ctx, 2, "ANNA@example.com")
+		assert.ErrorIs(t, err, ErrEmailTaken)
+		assert.Empty(t, email)
+	})
+}
+
 func TestUserService_Login_Success(t *testing.T) {
 	// Arrange
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	login := "testuser"
 	password := "testpass123"
@@ -123,15 +189,27 @@ func TestUserService_Login_Success(t *testing.T) {
 
 	// Настраиваем мок
 	mockRepo.EXPECT().GetByLogin(ctx, login).Return(user, nil)
+	mockSessionRepo.EXPECT().DeleteExpiredTokens(ctx, gomock.Any()).Return(nil)
+	mockSessionRepo.EXPECT().
+		CreateSession(ctx, gomock.Any(), gomock.Any()).
+		Return(nil).
+		Do(func(ctx context.Context, session *models.Session, token *models.RefreshToken) {
+			assert.Equal(t, uint(1), session.UserID)
+			assert.Equal(t, "10.0.0.1", session.IP)
+			assert.Len(t, session.UserAgent, maxUserAgent)
+			assert.Len(t, token.Hash, 64)
+			session.ID = 7
+		})
 
 	// Act
-	response, err := service.Login(ctx, login, password)
+	response, err := service.Login(ctx, login, password, dto.Device{UserAgent: strings.Repeat("a", 600), IP: "10.0.0.1"})
 
 	// Assert
 	assert.NoError(t, err)
 	assert.NotNil(t, response)
 	assert.Equal(t, user, response.User)
 	assert.NotEmpty(t, response.Token)
+	assert.NotEmpty(t, response.RefreshToken)
 }
 
 func TestUserService_Login_UserNotFound(t *testing.T) {
@@ -140,7 +218,8 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	login := "nonexistentuser"
 	password := "testpass123"
@@ -150,7 +229,7 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 	mockRepo.EXPECT().GetByLogin(ctx, login).Return(nil, gorm.ErrRecordNotFound)
 
 	// Act
-	response, err := service.Login(ctx, login, password)
+	response, err := service.Login(ctx, login, password, dto.Device{})
 
 	// Assert
 	assert.Error(t, err)
@@ -164,7 +243,8 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	login := "testuser"
 	correctPassword := "correctpass123"
@@ -183,7 +263,7 @@ func TestUserService_Login_InvalidPassword(t *testing.T) {
 	mockRepo.EXPECT().GetByLogin(ctx, login).Return(user, nil)
 
 	// Act
-	response, err := service.Login(ctx, login, wrongPassword)
+	response, err := service.Login(ctx, login, wrongPassword, dto.Device{})
 
 	// Assert
 	assert.Error(t, err)
@@ -197,7 +277,8 @@ func TestUserService_GetUserByID_Success(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
-	service := NewUserService(mockRepo)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
 
 	userID := uint(1)
 	expectedUser := &models.User{
@@ -216,3 +297,199 @@ func TestUserService_GetUserByID_Success(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, expectedUser, user)
 }
+
+func TestUserService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
+	ctx := context.Background()
+
+	stored := func(used, revoked bool, expiresIn time.Duration) *models.RefreshToken {
+		token := &models.RefreshToken{ID: 3, SessionID: 7, ExpiresAt: time.Now().Add(expiresIn)}
+		token.Session = models.Session{ID: 7, UserID: 1}
+		if used {
+			usedAt := time.Now().Add(-time.Minute)
+			token.UsedAt = &usedAt
+		}
+		if revoked {
+			revokedAt := time.Now().Add(-time.Minute)
+			token.Session.RevokedAt = &revokedAt
+		}
+		return token
+	}
+
+	t.Run("rotates", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetRefreshToken(ctx, hashToken("old")).Return(stored(false, false, time.Hour), nil)
+		mockSessionRepo.EXPECT().
+			RotateRefreshToken(ctx, uint(3), gomock.Any(), gomock.Any()).
+			Return(true, nil).
+			Do(func(ctx context.Context, usedID uint, next *models.RefreshToken, now time.Time) {
+				assert.Equal(t, uint(7), next.SessionID)
+				assert.NotEqual(t, hashToken("old"), next.Hash)
+			})
+
+		tokens, err := service.Refresh(ctx, "old")
+		assert.NoError(t, err)
+		assert.NotEmpty(t, tokens.Token)
+		assert.NotEqual(t, "old", tokens.RefreshToken)
+	})
+
+	t.Run("reuse revokes session", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetRefreshToken(ctx, hashToken("old")).Return(stored(true, false, time.Hour), nil)
+		mockSessionRepo.EXPECT().RevokeSession(ctx, uint(7), gomock.Any()).Return(nil)
+
+		_, err := service.Refresh(ctx, "old")
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+
+	t.Run("concurrent reuse revokes session", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetRefreshToken(ctx, hashToken("old")).Return(stored(false, false, time.Hour), nil)
+		mockSessionRepo.EXPECT().RotateRefreshToken(ctx, uint(3), gomock.Any(), gomock.Any()).Return(false, nil)
+		mockSessionRepo.EXPECT().RevokeSession(ctx, uint(7), gomock.Any()).Return(nil)
+
+		_, err := service.Refresh(ctx, "old")
+		assert.ErrorIs(t, err, ErrRefreshTokenReused)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetRefreshToken(ctx, hashToken("old")).Return(stored(false, false, -time.Hour), nil)
+
+		_, err := service.Refresh(ctx, "old")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetRefreshToken(ctx, hashToken("old")).Return(stored(true, true, time.Hour), nil)
+
+		_, err := service.Refresh(ctx, "old")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		mockSessionRepo.EXPECT().GetRefreshToken(ctx, hashToken("nope")).Return(nil, gorm.ErrRecordNotFound)
+
+		_, err := service.Refresh(ctx, "nope")
+		assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+	})
+}
+
+func TestUserService_RevokeSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
+	ctx := context.Background()
+
+	mockSessionRepo.EXPECT().RevokeUserSession(ctx, uint(1), uint(7), gomock.Any()).Return(true, nil)
+	assert.NoError(t, service.RevokeSession(ctx, 1, 7))
+
+	// Чужая или уже отозванная сессия
+	mockSessionRepo.EXPECT().RevokeUserSession(ctx, uint(1), uint(8), gomock.Any()).Return(false, nil)
+	assert.ErrorIs(t, service.RevokeSession(ctx, 1, 8), ErrSessionNotFound)
+}
+
+func TestUserService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	service := NewUserService(mockRepo, mockSessionRepo, &fakeMailer{}, "")
+	ctx := context.Background()
+
+	hashed, _ := service.hashPassword("oldpass123")
+	user := &models.User{Login: "anna", Password: hashed}
+	user.ID = 1
+
+	// Сессии, кроме текущей (7), отзываются вместе со сменой пароля
+	mockRepo.EXPECT().
+		ChangePassword(ctx, uint(1), gomock.Any(), uint(7), gomock.Any()).
+		Return(nil).
+		Do(func(ctx context.Context, userID uint, hash string, keepSessionID uint, now time.Time) {
+			assert.NoError(t, service.comparePassword(hash, "newpass123"))
+		})
+	assert.NoError(t, service.ChangePassword(ctx, user, 7, "oldpass123", "newpass123"))
+
+	assert.ErrorIs(t, service.ChangePassword(ctx, user, 7, "wrong", "newpass123"), ErrInvalidPassword)
+}
+
+func TestUserService_PasswordReset(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockUserRepositoryInterface(ctrl)
+	mockSessionRepo := mocks.NewMockSessionRepositoryInterface(ctrl)
+	mailer := &fakeMailer{}
+	service := NewUserService(mockRepo, mockSessionRepo, mailer, "https://app.example/reset")
+	ctx := context.Background()
+
+	user := &models.User{Login: "anna", Email: "anna@example.com", Locale: "en"}
+	user.ID = 1
+
+	t.Run("sends code and resets once", func(t *testing.T) {
+		var stored models.PasswordResetToken
+		mockRepo.EXPECT().GetByLoginOrEmail(ctx, "anna@example.com").Return(user, nil)
+		mockRepo.EXPECT().
+			CreateResetToken(ctx, gomock.Any()).
+			Return(nil).
+			Do(func(ctx context.Context, token *models.PasswordResetToken) {
+				stored = *token
+				stored.ID = 5
+			})
+
+		assert.NoError(t, service.RequestPasswordReset(ctx, " anna@example.com "))
+		service.Wait()
+		assert.Len(t, mailer.sent, 1)
+		msg := mailer.sent[0]
+		assert.Equal(t, "anna@example.com", msg.To)
+		assert.Equal(t, "WanderWallet password reset", msg.Subject)
+		assert.Contains(t, msg.Body, "account anna")
+
+		const prefix = "https://app.example/reset?token="
+		start := strings.Index(msg.Body, prefix)
+		assert.GreaterOrEqual(t, start, 0)
+		code := strings.Fields(msg.Body[start+len(prefix):])[0]
+		assert.Equal(t, hashToken(code), stored.Hash)
+		assert.WithinDuration(t, time.Now().Add(PasswordResetTTL), stored.ExpiresAt, time.Minute)
+
+		mockRepo.EXPECT().GetResetToken(ctx, stored.Hash).Return(&stored, nil)
+		mockRepo.EXPECT().ResetPassword(ctx, &stored, gomock.Any(), gomock.Any()).Return(true, nil)
+		assert.NoError(t, service.ResetPassword(ctx, code, "newpass123"))
+
+		// Код уже погашен параллельным запросом
+		mockRepo.EXPECT().GetResetToken(ctx, stored.Hash).Return(&stored, nil)
+		mockRepo.EXPECT().ResetPassword(ctx, &stored, gomock.Any(), gomock.Any()).Return(false, nil)
+		assert.ErrorIs(t, service.ResetPassword(ctx, code, "newpass123"), ErrInvalidResetToken)
+	})
+
+	t.Run("unknown user is not revealed", func(t *testing.T) {
+		mailer.sent = nil
+		mockRepo.EXPECT().GetByLoginOrEmail(ctx, "ghost").Return(nil, gorm.ErrRecordNotFound)
+		assert.NoError(t, service.RequestPasswordReset(ctx, "ghost"))
+
+		noEmail := &models.User{Login: "boris"}
+		mockRepo.EXPECT().GetByLoginOrEmail(ctx, "boris").Return(noEmail, nil)
+		assert.NoError(t, service.RequestPasswordReset(ctx, "boris"))
+		service.Wait()
+		assert.Empty(t, mailer.sent)
+	})
+
+	t.Run("expired or used code", func(t *testing.T) {
+		expired := &models.PasswordResetToken{UserID: 1, ExpiresAt: time.Now().Add(-time.Minute)}
+		mockRepo.EXPECT().GetResetToken(ctx, hashToken("old")).Return(expired, nil)
+		assert.ErrorIs(t, service.ResetPassword(ctx, "old", "newpass123"), ErrInvalidResetToken)
+
+		usedAt := time.Now()
+		used := &models.PasswordResetToken{UserID: 1, ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt}
+		mockRepo.EXPECT().GetResetToken(ctx, hashToken("used")).Return(used, nil)
+		assert.ErrorIs(t, service.ResetPassword(ctx, "used", "newpass123"), ErrInvalidResetToken)
+
+		mockRepo.EXPECT().GetResetToken(ctx, hashToken("nope")).Return(nil, gorm.ErrRecordNotFound)
+		assert.ErrorIs(t, service.ResetPassword(ctx, "nope", "newpass123"), ErrInvalidResetToken)
+	})
+}